@@ -0,0 +1,159 @@
+// Package cmd implements the subcommands of the server binary.
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"AwisPalace_IngredientManagement/config"
+	"AwisPalace_IngredientManagement/utils"
+)
+
+type command struct {
+	name     string
+	usage    string
+	summary  string
+	sections []config.Section // Setting yang divalidasi sebelum command berjalan
+	run      func(args []string) error
+}
+
+var commands []command
+
+func init() {
+	databaseOnly := []config.Section{config.SectionDatabase}
+	withTimezone := []config.Section{config.SectionDatabase, config.SectionTimezone}
+
+	commands = []command{
+		{"serve", "serve [--port 8080]", "Start the HTTP API (default)", config.AllSections, runServe},
+		{"migrate", "migrate up|down [steps]|status", "Apply, roll back or list schema migrations", databaseOnly, runMigrate},
+		{"seed", "seed [--only units,ingredients,menus]", "Insert sample data", withTimezone, runSeed},
+		{"export", "export transactions --from YYYY-MM-DD --to YYYY-MM-DD [--format xlsx|csv] [--out file]", "Export transactions to a file", withTimezone, runExport},
+		{"user", "user create --email EMAIL [--name NAME] --role owner|manager|cashier|kitchen", "Create a user with a role", databaseOnly, runUser},
+	}
+}
+
+// configFlags are the global flags overriding a setting, named after the
+// variable they replace
+var configFlags = []struct {
+	name  string
+	key   string
+	usage string
+}{
+	{"db-host", "DB_HOST", "database host"},
+	{"db-port", "POSTGRES_PORT", "database port"},
+	{"db-user", "POSTGRES_USER", "database user"},
+	{"db-password", "POSTGRES_PASSWORD", "database password"},
+	{"db-name", "POSTGRES_DB", "database name"},
+	{"db-sslmode", "DB_SSLMODE", "database SSL mode"},
+	{"db-max-open-conns", "DB_MAX_OPEN_CONNS", "maximum open database connections"},
+	{"db-max-idle-conns", "DB_MAX_IDLE_CONNS", "maximum idle database connections"},
+	{"db-conn-max-lifetime", "DB_CONN_MAX_LIFETIME", "maximum lifetime of a database connection, e.g. 30m"},
+	{"db-connect-retries", "DB_CONNECT_RETRIES", "attempts to connect to the database at startup"},
+	{"jwt-secret", "JWT_SECRET", "secret signing the API tokens, at least 32 characters"},
+	{"jwt-ttl", "JWT_TTL", "lifetime of the API tokens, e.g. 168h"},
+	{"cors-origins", "CORS_ALLOWED_ORIGINS", "comma separated allowed origins, * for all"},
+	{"google-client-id", "GOOGLE_CLIENT_ID", "comma separated Google OAuth client IDs"},
+	{"google-jwks-url", "GOOGLE_JWKS_URL", "JWKS endpoint of the Google signing keys"},
+	{"transaction-code-format", "TRANSACTION_CODE_FORMAT", "format of the transaction codes"},
+	{"outlet-code", "OUTLET_CODE", "outlet code used in document codes"},
+	{"upload-dir", "UPLOAD_DIR", "directory for uploaded files"},
+	{"timezone", "APP_TIMEZONE", "timezone of the outlet, e.g. Asia/Jakarta"},
+}
+
+// Execute runs the subcommand named by the first argument after the global
+// flags; without a command it serves the API so existing deployments running
+// the bare binary keep working
+func Execute(args []string) error {
+	global := flag.NewFlagSet("main", flag.ContinueOnError)
+	global.Usage = func() { printUsage(global) }
+	configFile := global.String("config", "", "env file to read settings from (default .env when present)")
+	overrides := map[string]string{}
+	for _, setting := range configFlags {
+		global.Func(setting.name, fmt.Sprintf("%s (overrides %s)", setting.usage, setting.key), func(value string) error {
+			overrides[setting.key] = value
+			return nil
+		})
+	}
+	if err := global.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	args = global.Args()
+
+	if len(args) > 0 && args[0] == "help" {
+		printUsage(global)
+		return nil
+	}
+
+	// Tanpa command, jalankan serve
+	name := "serve"
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == name {
+			if err := loadConfig(*configFile, overrides, cmd.sections); err != nil {
+				return err
+			}
+			return cmd.run(args)
+		}
+	}
+
+	printUsage(global)
+	return fmt.Errorf("unknown command %q", name)
+}
+
+func printUsage(global *flag.FlagSet) {
+	fmt.Fprintln(os.Stderr, "Usage: main [--config FILE] [global flags] <command> [options]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.summary)
+		fmt.Fprintf(os.Stderr, "  %-10s   %s\n", "", cmd.usage)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Global flags:")
+	global.PrintDefaults()
+}
+
+// loadConfig reads the settings into config.App and validates the sections
+// the command uses. Settings come from the global flags, then the
+// environment, then the env file.
+func loadConfig(file string, overrides map[string]string, sections []config.Section) error {
+	required := file != ""
+	if file == "" {
+		file = config.DefaultEnvFile
+	}
+
+	cfg, err := config.Load(file, required, overrides)
+	if err != nil {
+		return err
+	}
+	if err := cfg.Validate(sections...); err != nil {
+		return fmt.Errorf("invalid configuration:\n%w", err)
+	}
+
+	config.App = cfg
+	time.Local = cfg.Location()
+	utils.ConfigureJWT(cfg.JWT.Secret, cfg.JWT.TTL)
+
+	return nil
+}
+
+// splitList splits a comma separated flag value, ignoring blanks
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
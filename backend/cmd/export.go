@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"AwisPalace_IngredientManagement/config"
+	"AwisPalace_IngredientManagement/controllers"
+)
+
+func runExport(args []string) error {
+	if len(args) == 0 || args[0] != "transactions" {
+		return errors.New("usage: export transactions --from YYYY-MM-DD --to YYYY-MM-DD [--format xlsx|csv] [--out file]")
+	}
+
+	flags := flag.NewFlagSet("export transactions", flag.ContinueOnError)
+	from := flags.String("from", "", "start date (YYYY-MM-DD), default 30 days ago")
+	to := flags.String("to", "", "end date (YYYY-MM-DD), default today")
+	format := flags.String("format", controllers.ExportFormatXLSX, "xlsx or csv")
+	out := flags.String("out", "", "output file, - for stdout (default transactions_<from>_to_<to>.<format>)")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if *format != controllers.ExportFormatXLSX && *format != controllers.ExportFormatCSV {
+		return fmt.Errorf("invalid format %q, use xlsx or csv", *format)
+	}
+
+	startDate := time.Now().AddDate(0, 0, -30)
+	if *from != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", *from, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid --from date: %w", err)
+		}
+		startDate = parsed
+	}
+
+	endDate := time.Now()
+	if *to != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", *to, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid --to date: %w", err)
+		}
+		endDate = parsed
+	}
+	// Set to end of day
+	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 0, endDate.Location())
+
+	config.ConnectDB()
+
+	transactions, err := controllers.LoadTransactionsForExport(config.DB, startDate, endDate)
+	if err != nil {
+		return err
+	}
+
+	waste, err := controllers.LoadWasteForExport(config.DB, startDate, endDate)
+	if err != nil {
+		return err
+	}
+
+	path := *out
+	if path == "" {
+		path = controllers.TransactionsExportFilename(startDate, endDate, *format)
+	}
+
+	var w io.Writer = os.Stdout
+	if path != "-" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if *format == controllers.ExportFormatCSV {
+		err = controllers.WriteTransactionsCSV(w, transactions)
+	} else {
+		err = controllers.WriteTransactionsExcel(w, transactions, waste, startDate, endDate)
+	}
+	if err != nil {
+		return err
+	}
+
+	if path != "-" {
+		fmt.Fprintf(os.Stderr, "✅ Exported %d transactions to %s\n", len(transactions), path)
+	}
+	return nil
+}
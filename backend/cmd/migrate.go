@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"AwisPalace_IngredientManagement/config"
+	"AwisPalace_IngredientManagement/databases/migrations"
+)
+
+// runMigrate handles `migrate up`, `migrate down [steps]` and `migrate status`
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: migrate up|down [steps]|status")
+	}
+
+	config.ConnectDB()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrations.Up(config.DB)
+		for _, migration := range applied {
+			fmt.Printf("✅ Applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("Schema is up to date")
+		}
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			var err error
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return errors.New("steps must be a positive number")
+			}
+		}
+
+		rolledBack, err := migrations.Down(config.DB, steps)
+		for _, migration := range rolledBack {
+			fmt.Printf("✅ Rolled back %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+
+	case "status":
+		statuses, err := migrations.Status(config.DB)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.AppliedAt != nil {
+				state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-40s %s\n", status.Version, status.Name, state)
+		}
+
+	default:
+		return fmt.Errorf("unknown migrate command %q, use up, down or status", args[0])
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+
+	"AwisPalace_IngredientManagement/config"
+	"AwisPalace_IngredientManagement/databases/migrations"
+	"AwisPalace_IngredientManagement/databases/seeders"
+)
+
+func runSeed(args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	only := flags.String("only", "", "comma separated seeders to run (units, ingredients, menus)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	names := splitList(*only)
+	for _, name := range names {
+		if !slices.ContainsFunc(seeders.Seeders, func(seeder seeders.Seeder) bool { return seeder.Name == name }) {
+			return fmt.Errorf("unknown seeder %q", name)
+		}
+	}
+
+	config.ConnectDB()
+
+	if err := migrations.EnsureUpToDate(config.DB); err != nil {
+		return err
+	}
+
+	return seeders.Run(config.DB, names)
+}
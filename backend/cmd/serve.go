@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+
+	"AwisPalace_IngredientManagement/config"
+	"AwisPalace_IngredientManagement/databases/migrations"
+	"AwisPalace_IngredientManagement/middleware"
+	"AwisPalace_IngredientManagement/routes"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+)
+
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	port := flags.Int("port", config.App.Server.Port, "port to listen on (overrides SERVER_PORT)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *port < 1 || *port > 65535 {
+		return fmt.Errorf("--port must be between 1 and 65535")
+	}
+
+	// Connect to DB
+	config.ConnectDB()
+
+	// Refuse to serve on an old schema
+	if err := migrations.EnsureUpToDate(config.DB); err != nil {
+		return err
+	}
+
+	deps, err := routes.NewDependencies(config.DB, config.App)
+	if err != nil {
+		return err
+	}
+
+	r := gin.Default()
+
+	// CORS must be registered before the routes to apply to them
+	r.Use(middleware.CORS(config.App.CORS.AllowedOrigins))
+
+	// init routes
+	routes.SetupRoutes(r, deps)
+
+	// Serve uploaded files as static files
+	r.Static("/uploads", config.App.UploadDir)
+
+	// Swagger
+	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	return r.Run(fmt.Sprintf(":%d", *port))
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+
+	"AwisPalace_IngredientManagement/config"
+	"AwisPalace_IngredientManagement/models"
+)
+
+var roles = []string{models.RoleOwner, models.RoleManager, models.RoleCashier, models.RoleKitchen}
+
+func runUser(args []string) error {
+	if len(args) == 0 || args[0] != "create" {
+		return errors.New("usage: user create --email EMAIL [--name NAME] --role owner|manager|cashier|kitchen")
+	}
+
+	flags := flag.NewFlagSet("user create", flag.ContinueOnError)
+	email := flags.String("email", "", "Google account email the user signs in with")
+	name := flags.String("name", "", "display name")
+	role := flags.String("role", models.RoleCashier, "owner, manager, cashier or kitchen")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if *email == "" {
+		return errors.New("--email is required")
+	}
+	if !slices.Contains(roles, *role) {
+		return fmt.Errorf("invalid role %q, use owner, manager, cashier or kitchen", *role)
+	}
+
+	config.ConnectDB()
+
+	var count int64
+	if err := config.DB.Model(&models.User{}).Where("email = ?", *email).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("user %s already exists, change the role with PUT /users/:id/role", *email)
+	}
+
+	user := models.User{
+		Email: *email,
+		Name:  *name,
+		Role:  *role,
+	}
+
+	// GoogleID diisi saat user pertama kali login dengan Google
+	if err := config.DB.Omit("GoogleID").Create(&user).Error; err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Created %s user %s (ID %d)\n", user.Role, user.Email, user.ID)
+	return nil
+}
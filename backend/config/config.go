@@ -0,0 +1,301 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// DefaultEnvFile is read when no --config file is given. It is optional, in
+// Docker the variables usually come from the environment.
+const DefaultEnvFile = ".env"
+
+// App is the configuration loaded at startup
+var App *Config
+
+type Config struct {
+	Server      ServerConfig
+	Database    DatabaseConfig
+	JWT         JWTConfig
+	CORS        CORSConfig
+	Google      GoogleConfig
+	Transaction TransactionConfig
+	UploadDir   string
+	Timezone    string
+}
+
+type ServerConfig struct {
+	Port int
+}
+
+type DatabaseConfig struct {
+	Host            string
+	Port            int
+	User            string
+	Password        string
+	Name            string
+	SSLMode         string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnectRetries  int
+}
+
+type JWTConfig struct {
+	Secret string
+	TTL    time.Duration
+}
+
+type CORSConfig struct {
+	AllowedOrigins []string // "*" mengizinkan semua origin
+}
+
+type GoogleConfig struct {
+	ClientIDs []string
+	JWKSURL   string
+}
+
+type TransactionConfig struct {
+	CodeFormat string
+	OutletCode string
+}
+
+// Section is a group of settings validated together, commands only validate
+// the sections they use
+type Section int
+
+const (
+	SectionServer Section = iota
+	SectionDatabase
+	SectionJWT
+	SectionCORS
+	SectionUploads
+	SectionTimezone
+	SectionGoogle
+)
+
+// AllSections are the settings the HTTP API needs
+var AllSections = []Section{SectionServer, SectionDatabase, SectionJWT, SectionCORS, SectionUploads, SectionTimezone, SectionGoogle}
+
+// Load reads the configuration from overrides (command line flags keyed by
+// variable name), then the environment, falling back to the values in
+// envFile. A missing envFile is only an error when required is true (the file
+// was given explicitly).
+func Load(envFile string, required bool, overrides map[string]string) (*Config, error) {
+	fileValues := map[string]string{}
+	if envFile != "" {
+		values, err := godotenv.Read(envFile)
+		if err != nil {
+			if required || !errors.Is(err, os.ErrNotExist) {
+				return nil, fmt.Errorf("failed to read config file %s: %w", envFile, err)
+			}
+		} else {
+			fileValues = values
+		}
+	}
+
+	env := &envReader{overrides: overrides, file: fileValues}
+
+	cfg := &Config{
+		Server: ServerConfig{
+			Port: env.int("SERVER_PORT", 8080),
+		},
+		Database: DatabaseConfig{
+			Host:            env.string("DB_HOST", "localhost"),
+			Port:            env.int("POSTGRES_PORT", 5432),
+			User:            env.string("POSTGRES_USER", ""),
+			Password:        env.string("POSTGRES_PASSWORD", ""),
+			Name:            env.string("POSTGRES_DB", ""),
+			SSLMode:         env.string("DB_SSLMODE", "disable"),
+			MaxOpenConns:    env.int("DB_MAX_OPEN_CONNS", 25),
+			MaxIdleConns:    env.int("DB_MAX_IDLE_CONNS", 5),
+			ConnMaxLifetime: env.duration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+			ConnectRetries:  env.int("DB_CONNECT_RETRIES", 10),
+		},
+		JWT: JWTConfig{
+			Secret: env.string("JWT_SECRET", ""),
+			TTL:    env.duration("JWT_TTL", 7*24*time.Hour),
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: env.list("CORS_ALLOWED_ORIGINS", []string{"*"}),
+		},
+		Google: GoogleConfig{
+			ClientIDs: env.list("GOOGLE_CLIENT_ID", nil),
+			JWKSURL:   env.string("GOOGLE_JWKS_URL", ""),
+		},
+		Transaction: TransactionConfig{
+			CodeFormat: env.string("TRANSACTION_CODE_FORMAT", ""),
+			OutletCode: env.string("OUTLET_CODE", ""),
+		},
+		UploadDir: env.string("UPLOAD_DIR", "./uploads"),
+		Timezone:  env.string("APP_TIMEZONE", "Asia/Jakarta"),
+	}
+
+	if len(env.errs) > 0 {
+		return nil, errors.Join(env.errs...)
+	}
+
+	return cfg, nil
+}
+
+// Validate reports every missing or invalid setting in sections
+func (c *Config) Validate(sections ...Section) error {
+	var errs []error
+	for _, section := range sections {
+		switch section {
+		case SectionServer:
+			errs = append(errs, c.validateServer()...)
+		case SectionDatabase:
+			errs = append(errs, c.validateDatabase()...)
+		case SectionJWT:
+			errs = append(errs, c.validateJWT()...)
+		case SectionCORS:
+			if len(c.CORS.AllowedOrigins) == 0 {
+				errs = append(errs, errors.New("CORS_ALLOWED_ORIGINS must not be empty"))
+			}
+		case SectionUploads:
+			if c.UploadDir == "" {
+				errs = append(errs, errors.New("UPLOAD_DIR is required"))
+			}
+		case SectionTimezone:
+			if _, err := time.LoadLocation(c.Timezone); err != nil {
+				errs = append(errs, fmt.Errorf("APP_TIMEZONE %q is not a valid timezone", c.Timezone))
+			}
+		case SectionGoogle:
+			// Tanpa client ID setiap login Google ditolak
+			if len(c.Google.ClientIDs) == 0 {
+				errs = append(errs, errors.New("GOOGLE_CLIENT_ID is required"))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *Config) validateServer() []error {
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		return []error{errors.New("SERVER_PORT must be between 1 and 65535")}
+	}
+	return nil
+}
+
+func (c *Config) validateDatabase() []error {
+	var errs []error
+
+	if c.Database.Host == "" {
+		errs = append(errs, errors.New("DB_HOST is required"))
+	}
+	if c.Database.User == "" {
+		errs = append(errs, errors.New("POSTGRES_USER is required"))
+	}
+	if c.Database.Name == "" {
+		errs = append(errs, errors.New("POSTGRES_DB is required"))
+	}
+	if c.Database.Port < 1 || c.Database.Port > 65535 {
+		errs = append(errs, errors.New("POSTGRES_PORT must be between 1 and 65535"))
+	}
+	if c.Database.MaxOpenConns < 0 || c.Database.MaxIdleConns < 0 {
+		errs = append(errs, errors.New("DB_MAX_OPEN_CONNS and DB_MAX_IDLE_CONNS must not be negative"))
+	}
+	if c.Database.ConnectRetries < 1 {
+		errs = append(errs, errors.New("DB_CONNECT_RETRIES must be at least 1"))
+	}
+
+	return errs
+}
+
+func (c *Config) validateJWT() []error {
+	var errs []error
+
+	if len(c.JWT.Secret) < 32 {
+		errs = append(errs, errors.New("JWT_SECRET is required and must be at least 32 characters"))
+	}
+	if c.JWT.TTL <= 0 {
+		errs = append(errs, errors.New("JWT_TTL must be positive"))
+	}
+
+	return errs
+}
+
+// Location returns the configured timezone
+func (c *Config) Location() *time.Location {
+	location, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return location
+}
+
+// envReader reads variables from the overrides first, then the environment,
+// then the env file, collecting parse errors
+type envReader struct {
+	overrides map[string]string
+	file      map[string]string
+	errs      []error
+}
+
+func (r *envReader) lookup(key string) (string, bool) {
+	if value, ok := r.overrides[key]; ok {
+		return value, true
+	}
+	if value, ok := os.LookupEnv(key); ok {
+		return value, true
+	}
+	value, ok := r.file[key]
+	return value, ok
+}
+
+func (r *envReader) string(key, fallback string) string {
+	if value, ok := r.lookup(key); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+func (r *envReader) int(key string, fallback int) int {
+	value, ok := r.lookup(key)
+	if !ok || value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%s must be a number", key))
+		return fallback
+	}
+	return parsed
+}
+
+func (r *envReader) duration(key string, fallback time.Duration) time.Duration {
+	value, ok := r.lookup(key)
+	if !ok || value == "" {
+		return fallback
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%s must be a duration such as 30m or 168h", key))
+		return fallback
+	}
+	return parsed
+}
+
+func (r *envReader) list(key string, fallback []string) []string {
+	value, ok := r.lookup(key)
+	if !ok || value == "" {
+		return fallback
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -41,7 +41,6 @@ package config
 import (
 	"fmt"
 	"log"
-	"os"
 	"time"
 
 	"gorm.io/driver/postgres"
@@ -50,27 +49,45 @@ import (
 
 var DB *gorm.DB
 
+// ConnectDB opens the database described by App.Database, retrying while
+// the server is still starting up
 func ConnectDB() {
-    dsn := fmt.Sprintf(
-        "host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Jakarta",
-        os.Getenv("DB_HOST"),
-        os.Getenv("POSTGRES_USER"),
-        os.Getenv("POSTGRES_PASSWORD"),
-        os.Getenv("POSTGRES_DB"),
-        os.Getenv("POSTGRES_PORT"),
-    )
-
-    var err error
-    for i := 0; i < 10; i++ {
-        DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
-        if err == nil {
-            log.Println("✅ Connected to database")
-            return
-        }
-
-        log.Printf("❌ DB not ready, retrying in 3s... (%d/10)", i+1)
-        time.Sleep(3 * time.Second)
-    }
-
-    log.Fatalf("❌ Failed to connect to database after retries: %v", err)
+	cfg := App.Database
+	dsn := fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%d sslmode=%s TimeZone=%s",
+		cfg.Host,
+		cfg.User,
+		cfg.Password,
+		cfg.Name,
+		cfg.Port,
+		cfg.SSLMode,
+		App.Timezone,
+	)
+
+	var err error
+	for i := 0; i < cfg.ConnectRetries; i++ {
+		DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+		if err == nil {
+			configurePool(cfg)
+			log.Println("✅ Connected to database")
+			return
+		}
+
+		log.Printf("❌ DB not ready, retrying in 3s... (%d/%d)", i+1, cfg.ConnectRetries)
+		time.Sleep(3 * time.Second)
+	}
+
+	log.Fatalf("❌ Failed to connect to database after retries: %v", err)
+}
+
+func configurePool(cfg DatabaseConfig) {
+	sqlDB, err := DB.DB()
+	if err != nil {
+		log.Printf("⚠️ Could not configure connection pool: %v", err)
+		return
+	}
+
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 }
@@ -7,6 +7,7 @@ import (
 	"AwisPalace_IngredientManagement/utils"
 	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/gin-gonic/gin"
 )
@@ -35,39 +36,57 @@ func GoogleAuth(c *gin.Context) {
 		return
 	}
 
-	// Verify Google ID token (optional but recommended for production)
-	// Uncomment this in production
-	/*
-		payload, err := idtoken.Validate(context.Background(), req.IDToken, "YOUR_GOOGLE_CLIENT_ID")
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"status":  "error",
-				"message": "Invalid ID token: " + err.Error(),
-			})
-			return
-		}
+	// Verify Google ID token signature, audience and expiry
+	payload, err := getGoogleTokenVerifier().Verify(c.Request.Context(), req.IDToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Invalid ID token: " + err.Error(),
+		})
+		return
+	}
 
-		// Verify email matches
-		if payload.Claims["email"] != req.Email {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"status":  "error",
-				"message": "Email mismatch",
-			})
-			return
-		}
-	*/
+	// Verify email matches
+	if req.Email != "" && !strings.EqualFold(payload.Email, req.Email) {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Email mismatch",
+		})
+		return
+	}
+
+	// Profile data is taken from the verified token, not the request body
+	req.Email = payload.Email
+	if payload.Name != "" {
+		req.Name = payload.Name
+	}
+	if payload.Picture != "" {
+		req.PhotoURL = payload.Picture
+	}
 
-	// Check if user exists
+	// Check if user exists, users created before verification stored the email as GoogleID
 	var user models.User
-	result := config.DB.Where("email = ?", req.Email).First(&user)
+	result := config.DB.Where("google_id = ?", payload.Subject).First(&user)
+	if result.Error != nil {
+		result = config.DB.Where("email = ?", payload.Email).First(&user)
+	}
 
 	if result.Error != nil {
-		// User doesn't exist, create new user
+		// User doesn't exist, create new user.
+		// The very first user becomes the owner so roles can be assigned.
+		role := models.RoleCashier
+		var userCount int64
+		config.DB.Model(&models.User{}).Count(&userCount)
+		if userCount == 0 {
+			role = models.RoleOwner
+		}
+
 		user = models.User{
 			Email:    req.Email,
 			Name:     req.Name,
 			PhotoURL: req.PhotoURL,
-			GoogleID: req.Email, // Using email as GoogleID for simplicity
+			GoogleID: payload.Subject,
+			Role:     role,
 		}
 
 		if err := config.DB.Create(&user).Error; err != nil {
@@ -79,7 +98,7 @@ func GoogleAuth(c *gin.Context) {
 		}
 
 		// Generate token
-		token, err := utils.GenerateToken(user.ID, user.Email)
+		token, err := utils.GenerateToken(user.ID, user.Email, user.Role)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"status":  "error",
@@ -99,20 +118,24 @@ func GoogleAuth(c *gin.Context) {
 					Email:    user.Email,
 					Name:     user.Name,
 					PhotoURL: user.PhotoURL,
+					Role:     user.Role,
 				},
 			},
 		})
 		return
 	}
 
-	// User exists, update photo if changed
-	if req.PhotoURL != "" && req.PhotoURL != user.PhotoURL {
-		user.PhotoURL = req.PhotoURL
+	// User exists, store the real Google subject and update photo if changed
+	if user.GoogleID != payload.Subject || (req.PhotoURL != "" && req.PhotoURL != user.PhotoURL) {
+		user.GoogleID = payload.Subject
+		if req.PhotoURL != "" {
+			user.PhotoURL = req.PhotoURL
+		}
 		config.DB.Save(&user)
 	}
 
 	// Generate token
-	token, err := utils.GenerateToken(user.ID, user.Email)
+	token, err := utils.GenerateToken(user.ID, user.Email, user.Role)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "error",
@@ -132,6 +155,7 @@ func GoogleAuth(c *gin.Context) {
 				Email:    user.Email,
 				Name:     user.Name,
 				PhotoURL: user.PhotoURL,
+				Role:     user.Role,
 			},
 		},
 	})
@@ -188,6 +212,7 @@ func VerifyToken(c *gin.Context) {
 			Email:    user.Email,
 			Name:     user.Name,
 			PhotoURL: user.PhotoURL,
+			Role:     user.Role,
 		},
 	})
 }
@@ -225,8 +250,18 @@ func RefreshToken(c *gin.Context) {
 		return
 	}
 
+	// Reload user so role changes are picked up
+	var user models.User
+	if err := config.DB.First(&user, claims.UserID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "User not found",
+		})
+		return
+	}
+
 	// Generate new token
-	newToken, err := utils.GenerateToken(claims.UserID, claims.Email)
+	newToken, err := utils.GenerateToken(user.ID, user.Email, user.Role)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "error",
@@ -243,3 +278,51 @@ func RefreshToken(c *gin.Context) {
 		},
 	})
 }
+
+// currentUserID returns the authenticated user ID set by AuthMiddleware, if any
+func currentUserID(c *gin.Context) *uint {
+	value, exists := c.Get("user_id")
+	if !exists {
+		return nil
+	}
+
+	userID, ok := value.(uint)
+	if !ok || userID == 0 {
+		return nil
+	}
+
+	return &userID
+}
+
+var (
+	googleTokenVerifier     *utils.GoogleTokenVerifier
+	googleTokenVerifierOnce sync.Once
+)
+
+// SetGoogleTokenVerifier replaces the verifier used by GoogleAuth,
+// e.g. with one backed by a local fake JWKS server
+func SetGoogleTokenVerifier(verifier *utils.GoogleTokenVerifier) {
+	googleTokenVerifierOnce.Do(func() {})
+	googleTokenVerifier = verifier
+}
+
+// getGoogleTokenVerifier builds the verifier from the Google client IDs and
+// JWKS URL in config.App on first use
+func getGoogleTokenVerifier() *utils.GoogleTokenVerifier {
+	googleTokenVerifierOnce.Do(func() {
+		var audiences []string
+		var jwksURL string
+		if config.App != nil {
+			audiences = config.App.Google.ClientIDs
+			jwksURL = config.App.Google.JWKSURL
+		}
+
+		if jwksURL == "" {
+			jwksURL = utils.GoogleJWKSURL
+		}
+
+		googleTokenVerifier = utils.NewGoogleTokenVerifier(audiences, utils.NewJWKSKeySource(jwksURL))
+	})
+
+	return googleTokenVerifier
+}
@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"AwisPalace_IngredientManagement/config"
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// useTestGoogleKey serves key from a local JWKS endpoint and installs a
+// verifier for clientID backed by it
+func useTestGoogleKey(t *testing.T, key *rsa.PrivateKey, clientID string) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(gin.H{
+			"keys": []gin.H{{
+				"kty": "RSA",
+				"kid": "test-key",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	SetGoogleTokenVerifier(utils.NewGoogleTokenVerifier([]string{clientID}, utils.NewJWKSKeySource(server.URL)))
+	t.Cleanup(func() {
+		googleTokenVerifier = nil
+		googleTokenVerifierOnce = sync.Once{}
+	})
+}
+
+func TestGoogleAuthUsesInstalledVerifier(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "auth.db")), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.User{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	previousDB := config.DB
+	config.DB = db
+	t.Cleanup(func() { config.DB = previousDB })
+
+	utils.ConfigureJWT("auth-controller-test-secret-0123456789", time.Hour)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	const clientID = "test-client.apps.googleusercontent.com"
+	useTestGoogleKey(t, key, clientID)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/auth/google", GoogleAuth)
+
+	login := func(audience string) (*httptest.ResponseRecorder, dto.AuthResponse) {
+		now := time.Now()
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, utils.GoogleClaims{
+			Email:         "owner@example.com",
+			EmailVerified: true,
+			Name:          "Owner",
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    "accounts.google.com",
+				Subject:   "google-owner",
+				Audience:  jwt.ClaimStrings{audience},
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+			},
+		})
+		token.Header["kid"] = "test-key"
+		idToken, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("sign token: %v", err)
+		}
+
+		body, _ := json.Marshal(dto.GoogleAuthRequest{IDToken: idToken})
+		req := httptest.NewRequest(http.MethodPost, "/auth/google", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response dto.AuthResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w, response
+	}
+
+	// User pertama menjadi owner
+	w, response := login(clientID)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("first login status %d, want 201: %s", w.Code, w.Body.String())
+	}
+	if response.Data.User.Role != models.RoleOwner || response.Data.Token == "" {
+		t.Errorf("first login = %+v, want an owner with a token", response.Data)
+	}
+
+	if w, _ := login(clientID); w.Code != http.StatusOK {
+		t.Errorf("second login status %d, want 200: %s", w.Code, w.Body.String())
+	}
+
+	if w, _ := login("other-client.apps.googleusercontent.com"); w.Code != http.StatusUnauthorized {
+		t.Errorf("foreign audience status %d, want 401: %s", w.Code, w.Body.String())
+	}
+
+	var count int64
+	db.Model(&models.User{}).Count(&count)
+	if count != 1 {
+		t.Errorf("users = %d, want 1", count)
+	}
+}
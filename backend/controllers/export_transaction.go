@@ -3,12 +3,19 @@ package controllers
 import (
 	"AwisPalace_IngredientManagement/config"
 	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+	"AwisPalace_IngredientManagement/services"
+	"encoding/csv"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
 )
 
 // ExportTransactionDTO represents the data structure for export
@@ -40,12 +47,14 @@ type IngredientUsageDTO struct {
 
 // ExportTransactions godoc
 // @Summary Export Transactions to Excel
-// @Description Export transaction data with ingredient usage to Excel file. Returns Excel file with 3 sheets: Transactions (all transaction details), Ingredient Usage (ingredients used per transaction with stock changes), and Summary (statistics and top selling items). Supports date range filtering, defaults to last 30 days if dates not specified.
+// @Description Export transaction data with ingredient usage to Excel file. Returns Excel file with 4 sheets: Transactions (all transaction details), Ingredient Usage (ingredients used per transaction with stock changes), Summary (statistics and top selling items) and Waste (waste records with totals by reason, ingredient and date). The CSV format only contains transactions. Supports date range filtering, defaults to last 30 days if dates not specified.
 // @Tags Transactions
 // @Accept json
 // @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Param start_date query string false "Start date in YYYY-MM-DD format. Defaults to 30 days ago if not specified." example(2024-01-01)
 // @Param end_date query string false "End date in YYYY-MM-DD format. Defaults to today if not specified." example(2024-12-31)
+// @Param format query string false "xlsx (default) or csv"
+// @Security BearerAuth
 // @Router /export/transactions [get]
 func ExportTransactions(c *gin.Context) {
 	// Get query parameters
@@ -57,7 +66,7 @@ func ExportTransactions(c *gin.Context) {
 	var err error
 
 	if startDateStr != "" {
-		startDate, err = time.Parse("2006-01-02", startDateStr)
+		startDate, err = time.ParseInLocation("2006-01-02", startDateStr, time.Local)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"status":  "error",
@@ -71,7 +80,7 @@ func ExportTransactions(c *gin.Context) {
 	}
 
 	if endDateStr != "" {
-		endDate, err = time.Parse("2006-01-02", endDateStr)
+		endDate, err = time.ParseInLocation("2006-01-02", endDateStr, time.Local)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"status":  "error",
@@ -87,14 +96,8 @@ func ExportTransactions(c *gin.Context) {
 	}
 
 	// Fetch transactions with details
-	var transactions []models.Transaction
-	if err := config.DB.
-		Preload("TransactionItems.Menu").
-		Preload("TransactionItems.StockReductions.Ingredient").
-		Preload("TransactionItems.StockReductions.Unit").
-		Where("transaction_date BETWEEN ? AND ?", startDate, endDate).
-		Order("transaction_date DESC").
-		Find(&transactions).Error; err != nil {
+	transactions, err := LoadTransactionsForExport(config.DB, startDate, endDate)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "error",
 			"message": "Failed to fetch transactions: " + err.Error(),
@@ -102,7 +105,28 @@ func ExportTransactions(c *gin.Context) {
 		return
 	}
 
-	if len(transactions) == 0 {
+	format := c.DefaultQuery("format", ExportFormatXLSX)
+	if format != ExportFormatXLSX && format != ExportFormatCSV {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid format. Use xlsx or csv",
+		})
+		return
+	}
+
+	var waste []models.WasteRecord
+	if format == ExportFormatXLSX {
+		waste, err = LoadWasteForExport(config.DB, startDate, endDate)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": "Failed to fetch waste records: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	if len(transactions) == 0 && len(waste) == 0 {
 		c.JSON(http.StatusNotFound, gin.H{
 			"status":  "error",
 			"message": "No transactions found in the specified date range",
@@ -110,11 +134,79 @@ func ExportTransactions(c *gin.Context) {
 		return
 	}
 
+	// Generate filename
+	filename := TransactionsExportFilename(startDate, endDate, format)
+
+	// Set headers for download
+	c.Header("Content-Description", "File Transfer")
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+
+	if format == ExportFormatCSV {
+		c.Header("Content-Type", "text/csv")
+		if err := WriteTransactionsCSV(c.Writer, transactions); err != nil {
+			log.Printf("Failed to write CSV export: %v", err)
+		}
+		return
+	}
+
+	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+
+	// Write to response
+	if err := WriteTransactionsExcel(c.Writer, transactions, waste, startDate, endDate); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to generate Excel file: " + err.Error(),
+		})
+		return
+	}
+}
+
+// Export formats
+const (
+	ExportFormatXLSX = "xlsx"
+	ExportFormatCSV  = "csv"
+)
+
+// LoadTransactionsForExport loads the transactions between startDate and
+// endDate with their items and ingredient usage, newest first
+func LoadTransactionsForExport(db *gorm.DB, startDate, endDate time.Time) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	err := db.
+		Preload("TransactionItems.Menu").
+		Preload("TransactionItems.StockReductions.Ingredient").
+		Preload("TransactionItems.StockReductions.Unit").
+		Where("transaction_date BETWEEN ? AND ?", startDate, endDate).
+		Order("transaction_date DESC").
+		Find(&transactions).Error
+
+	return transactions, err
+}
+
+// LoadWasteForExport loads the waste recorded between startDate and endDate,
+// oldest first
+func LoadWasteForExport(db *gorm.DB, startDate, endDate time.Time) ([]models.WasteRecord, error) {
+	return repositories.NewGormStore(db).Waste().FindAll(repositories.WasteFilter{
+		StartDate: startDate,
+		EndDate:   endDate,
+	})
+}
+
+// TransactionsExportFilename returns the download name of an export
+func TransactionsExportFilename(startDate, endDate time.Time, format string) string {
+	return fmt.Sprintf("transactions_%s_to_%s.%s",
+		startDate.Format("2006-01-02"),
+		endDate.Format("2006-01-02"),
+		format)
+}
+
+// WriteTransactionsExcel writes the Transactions, Ingredient Usage, Summary
+// and Waste sheets to w
+func WriteTransactionsExcel(w io.Writer, transactions []models.Transaction, waste []models.WasteRecord, startDate, endDate time.Time) error {
 	// Create Excel file
 	f := excelize.NewFile()
 	defer func() {
 		if err := f.Close(); err != nil {
-			fmt.Println(err)
+			log.Printf("Failed to close Excel export: %v", err)
 		}
 	}()
 
@@ -122,6 +214,7 @@ func ExportTransactions(c *gin.Context) {
 	f.SetSheetName("Sheet1", "Transactions")
 	f.NewSheet("Ingredient Usage")
 	f.NewSheet("Summary")
+	f.NewSheet("Waste")
 
 	// Fill Transactions sheet
 	fillTransactionsSheet(f, transactions)
@@ -132,24 +225,41 @@ func ExportTransactions(c *gin.Context) {
 	// Fill Summary sheet
 	fillSummarySheet(f, transactions, startDate, endDate)
 
-	// Generate filename
-	filename := fmt.Sprintf("transactions_%s_to_%s.xlsx",
-		startDate.Format("2006-01-02"),
-		endDate.Format("2006-01-02"))
+	// Fill Waste sheet
+	fillWasteSheet(f, waste)
 
-	// Set headers for download
-	c.Header("Content-Description", "File Transfer")
-	c.Header("Content-Disposition", "attachment; filename="+filename)
-	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	return f.Write(w)
+}
 
-	// Write to response
-	if err := f.Write(c.Writer); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to generate Excel file: " + err.Error(),
-		})
-		return
+// WriteTransactionsCSV writes one row per transaction item to w, with the
+// same columns as the Transactions sheet
+func WriteTransactionsCSV(w io.Writer, transactions []models.Transaction) error {
+	writer := csv.NewWriter(w)
+
+	writer.Write([]string{
+		"ID", "Transaction Code", "Date", "Menu Name",
+		"Quantity", "Price", "Subtotal", "Total Amount", "Status", "Notes",
+	})
+
+	for _, trx := range transactions {
+		for _, item := range trx.TransactionItems {
+			writer.Write([]string{
+				strconv.FormatUint(uint64(trx.ID), 10),
+				trx.TransactionCode,
+				trx.TransactionDate.Format("2006-01-02 15:04:05"),
+				item.Menu.Name,
+				strconv.Itoa(item.Quantity),
+				strconv.FormatFloat(item.Price, 'f', 2, 64),
+				strconv.FormatFloat(float64(item.Quantity)*item.Price, 'f', 2, 64),
+				strconv.FormatFloat(trx.TotalAmount, 'f', 2, 64),
+				trx.Status,
+				trx.Notes,
+			})
+		}
 	}
+
+	writer.Flush()
+	return writer.Error()
 }
 
 // fillTransactionsSheet fills the transactions sheet
@@ -330,7 +440,7 @@ func fillSummarySheet(f *excelize.File, transactions []models.Transaction, start
 	totalMenusSold := 0
 
 	for _, trx := range transactions {
-		totalRevenue += trx.TotalAmount
+		totalRevenue += trx.NetAmount()
 		totalItems += len(trx.TransactionItems)
 		for _, item := range trx.TransactionItems {
 			totalMenusSold += item.Quantity
@@ -421,3 +531,109 @@ func fillSummarySheet(f *excelize.File, transactions []models.Transaction, start
 		f.SetCellValue(sheet, fmt.Sprintf("B%d", row), qty)
 	}
 }
+
+// fillWasteSheet fills the waste sheet with the records followed by their
+// totals by reason, ingredient and date
+func fillWasteSheet(f *excelize.File, waste []models.WasteRecord) {
+	sheet := "Waste"
+
+	// Set column widths
+	f.SetColWidth(sheet, "A", "A", 20)
+	f.SetColWidth(sheet, "B", "B", 25)
+	f.SetColWidth(sheet, "C", "C", 15)
+	f.SetColWidth(sheet, "D", "D", 15)
+	f.SetColWidth(sheet, "E", "E", 12)
+	f.SetColWidth(sheet, "F", "F", 15)
+	f.SetColWidth(sheet, "G", "G", 15)
+	f.SetColWidth(sheet, "H", "H", 30)
+
+	// Create header style
+	headerStyle, _ := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true, Color: "FFFFFF"},
+		Fill:      excelize.Fill{Type: "pattern", Color: []string{"C00000"}, Pattern: 1},
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center"},
+		Border: []excelize.Border{
+			{Type: "top", Color: "000000", Style: 1},
+			{Type: "bottom", Color: "000000", Style: 1},
+			{Type: "left", Color: "000000", Style: 1},
+			{Type: "right", Color: "000000", Style: 1},
+		},
+	})
+
+	// Title style
+	titleStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true, Size: 14},
+	})
+
+	setHeaders := func(row int, headers []string) {
+		for i, header := range headers {
+			cell, _ := excelize.CoordinatesToCellName(i+1, row)
+			f.SetCellValue(sheet, cell, header)
+			f.SetCellStyle(sheet, cell, cell, headerStyle)
+		}
+	}
+
+	setHeaders(1, []string{
+		"Date", "Ingredient Name", "Reason", "Qty Reduced",
+		"Unit", "Unit Cost", "Value", "Notes",
+	})
+
+	// Fill data
+	row := 2
+	for _, record := range waste {
+		f.SetCellValue(sheet, fmt.Sprintf("A%d", row), record.WastedAt.Format("2006-01-02 15:04:05"))
+		f.SetCellValue(sheet, fmt.Sprintf("B%d", row), record.Ingredient.Name)
+		f.SetCellValue(sheet, fmt.Sprintf("C%d", row), record.Reason)
+		f.SetCellValue(sheet, fmt.Sprintf("D%d", row), record.QuantityReduced)
+		f.SetCellValue(sheet, fmt.Sprintf("E%d", row), record.Unit.Name)
+		f.SetCellValue(sheet, fmt.Sprintf("F%d", row), record.UnitCost)
+		f.SetCellValue(sheet, fmt.Sprintf("G%d", row), record.Value())
+		f.SetCellValue(sheet, fmt.Sprintf("H%d", row), record.Notes)
+		row++
+	}
+
+	report := services.SummarizeWaste(waste)
+
+	row++
+	f.SetCellValue(sheet, fmt.Sprintf("A%d", row), "BY REASON")
+	f.SetCellStyle(sheet, fmt.Sprintf("A%d", row), fmt.Sprintf("A%d", row), titleStyle)
+	row++
+	setHeaders(row, []string{"Reason", "Records", "Value"})
+	for _, total := range report.ByReason {
+		row++
+		f.SetCellValue(sheet, fmt.Sprintf("A%d", row), total.Reason)
+		f.SetCellValue(sheet, fmt.Sprintf("B%d", row), total.Records)
+		f.SetCellValue(sheet, fmt.Sprintf("C%d", row), total.Value)
+	}
+
+	row += 2
+	f.SetCellValue(sheet, fmt.Sprintf("A%d", row), "BY INGREDIENT")
+	f.SetCellStyle(sheet, fmt.Sprintf("A%d", row), fmt.Sprintf("A%d", row), titleStyle)
+	row++
+	setHeaders(row, []string{"Ingredient Name", "Records", "Qty Reduced", "Unit", "Value"})
+	for _, total := range report.ByIngredient {
+		row++
+		f.SetCellValue(sheet, fmt.Sprintf("A%d", row), total.Ingredient.Name)
+		f.SetCellValue(sheet, fmt.Sprintf("B%d", row), total.Records)
+		f.SetCellValue(sheet, fmt.Sprintf("C%d", row), total.Quantity)
+		f.SetCellValue(sheet, fmt.Sprintf("D%d", row), total.Unit.Name)
+		f.SetCellValue(sheet, fmt.Sprintf("E%d", row), total.Value)
+	}
+
+	row += 2
+	f.SetCellValue(sheet, fmt.Sprintf("A%d", row), "BY DATE")
+	f.SetCellStyle(sheet, fmt.Sprintf("A%d", row), fmt.Sprintf("A%d", row), titleStyle)
+	row++
+	setHeaders(row, []string{"Date", "Records", "Value"})
+	for _, total := range report.ByDate {
+		row++
+		f.SetCellValue(sheet, fmt.Sprintf("A%d", row), total.Date)
+		f.SetCellValue(sheet, fmt.Sprintf("B%d", row), total.Records)
+		f.SetCellValue(sheet, fmt.Sprintf("C%d", row), total.Value)
+	}
+
+	row += 2
+	f.SetCellValue(sheet, fmt.Sprintf("A%d", row), "TOTAL VALUE:")
+	f.SetCellStyle(sheet, fmt.Sprintf("A%d", row), fmt.Sprintf("A%d", row), titleStyle)
+	f.SetCellValue(sheet, fmt.Sprintf("B%d", row), fmt.Sprintf("Rp %.2f", report.TotalValue))
+}
@@ -0,0 +1,336 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"AwisPalace_IngredientManagement/config"
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetReceipts godoc
+// @Summary Get Goods Receipts
+// @Description Get Goods Receipts with optional purchase order filter
+// @Tags Goods Receipts
+// @Param purchase_order_id query int false "Purchase Order ID"
+// @Security BearerAuth
+// @Router /receipts [get]
+func GetReceipts(c *gin.Context) {
+	var receipts []models.GoodsReceipt
+
+	query := preloadGoodsReceipt(config.DB)
+	if purchaseOrderID := c.Query("purchase_order_id"); purchaseOrderID != "" {
+		query = query.Where("purchase_order_id = ?", purchaseOrderID)
+	}
+
+	if err := query.Order("created_at DESC").Find(&receipts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	response := make([]dto.GoodsReceipt, 0, len(receipts))
+	for _, receipt := range receipts {
+		response = append(response, toGoodsReceiptDTO(receipt))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response,
+	})
+}
+
+// GetReceipt godoc
+// @Summary Get Goods Receipt
+// @Description Get Goods Receipt by ID
+// @Tags Goods Receipts
+// @Param id path int true "Goods Receipt ID"
+// @Security BearerAuth
+// @Router /receipts/{id} [get]
+func GetReceipt(c *gin.Context) {
+	id := c.Param("id")
+
+	var receipt models.GoodsReceipt
+	if err := preloadGoodsReceipt(config.DB).First(&receipt, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Goods receipt not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   toGoodsReceiptDTO(receipt),
+	})
+}
+
+// PostReceipt godoc
+// @Summary Receive Goods
+// @Description Receive goods for a sent Purchase Order and raise ingredient stock. Every item becomes a stock lot with its optional expiry date.
+// @Tags Goods Receipts
+// @Param receipt body dto.GoodsReceiptCreateRequest true "Receive goods"
+// @Security BearerAuth
+// @Router /receipts [post]
+func PostReceipt(c *gin.Context) {
+	var input dto.GoodsReceiptCreateRequest
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	receivedDate := time.Now()
+	if input.ReceivedDate != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", input.ReceivedDate, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Invalid received_date format (YYYY-MM-DD)",
+			})
+			return
+		}
+		receivedDate = parsed
+	}
+
+	expiryDates := make([]*time.Time, len(input.Items))
+	for i, item := range input.Items {
+		if item.ExpiryDate == "" {
+			continue
+		}
+		parsed, err := time.ParseInLocation("2006-01-02", item.ExpiryDate, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Invalid expiry_date format (YYYY-MM-DD)",
+			})
+			return
+		}
+		expiryDates[i] = &parsed
+	}
+
+	var receipt models.GoodsReceipt
+	err := utils.RunInTransaction(config.DB, func(tx *gorm.DB) error {
+		// Lock the purchase order first: receipts posted at the same time wait
+		// here and then see the received quantities of each other, because the
+		// lines are loaded after the lock is held
+		var purchaseOrder models.PurchaseOrder
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("Lines").
+			Preload("Lines.Unit").
+			First(&purchaseOrder, input.PurchaseOrderID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return withStatus(http.StatusNotFound, fmt.Errorf("Purchase order with ID %d not found", input.PurchaseOrderID))
+			}
+			return err
+		}
+
+		if purchaseOrder.Status != models.PurchaseOrderStatusSent &&
+			purchaseOrder.Status != models.PurchaseOrderStatusPartiallyReceived {
+			return withStatus(http.StatusConflict, fmt.Errorf("Cannot receive goods for a purchase order with status %s", purchaseOrder.Status))
+		}
+
+		receipt = models.GoodsReceipt{
+			ReceiptNumber: fmt.Sprintf("GR-%s-%s",
+				receivedDate.Format("20060102"),
+				strings.ToUpper(uuid.New().String()[:8]),
+			),
+			ReceivedDate:    receivedDate,
+			Notes:           input.Notes,
+			PurchaseOrderID: purchaseOrder.ID,
+		}
+
+		if err := tx.Create(&receipt).Error; err != nil {
+			return err
+		}
+
+		lines := make(map[uint]*models.PurchaseOrderLine, len(purchaseOrder.Lines))
+		ingredientIDs := make([]uint, 0, len(purchaseOrder.Lines))
+		for i := range purchaseOrder.Lines {
+			lines[purchaseOrder.Lines[i].ID] = &purchaseOrder.Lines[i]
+			ingredientIDs = append(ingredientIDs, purchaseOrder.Lines[i].IngredientID)
+		}
+
+		// Lock ingredients in ID order so receipts and sales cannot deadlock
+		if err := utils.LockIngredients(tx, ingredientIDs); err != nil {
+			return err
+		}
+
+		for i, item := range input.Items {
+			line, ok := lines[item.PurchaseOrderLineID]
+			if !ok {
+				return withStatus(http.StatusBadRequest, fmt.Errorf("Line with ID %d does not belong to purchase order %s", item.PurchaseOrderLineID, purchaseOrder.PONumber))
+			}
+
+			if item.Quantity > line.Outstanding() {
+				return withStatus(http.StatusBadRequest, fmt.Errorf("Received quantity for line %d exceeds outstanding quantity. Outstanding: %.2f, Received: %.2f", line.ID, line.Outstanding(), item.Quantity))
+			}
+
+			// Get current ingredient stock
+			var ingredient models.Ingredient
+			if err := preloadIngredientUnits(tx).First(&ingredient, line.IngredientID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return withStatus(http.StatusNotFound, fmt.Errorf("Ingredient with ID %d not found", line.IngredientID))
+				}
+				return err
+			}
+
+			// Calculate quantity to add in the ingredient's stock unit
+			quantityToAdd, err := utils.ConvertQuantity(item.Quantity, line.Unit, ingredient.Unit, ingredient.Conversions)
+			if err != nil {
+				return withStatus(http.StatusBadRequest, fmt.Errorf("Ingredient %s: %w", ingredient.Name, err))
+			}
+
+			// Record stock movement in the ledger
+			movement := models.StockMovement{
+				IngredientID:  ingredient.ID,
+				Type:          models.StockMovementPurchase,
+				Quantity:      quantityToAdd,
+				UserID:        currentUserID(c),
+				ReferenceType: "purchase_order_line",
+				ReferenceID:   line.ID,
+				Notes:         receipt.ReceiptNumber,
+			}
+
+			if err := utils.RecordStockMovement(tx, &movement); err != nil {
+				return err
+			}
+
+			// Update ingredient cost from the purchase price per stock unit
+			if quantityToAdd > 0 {
+				purchaseUnitCost := line.UnitCost * item.Quantity / quantityToAdd
+				unitCost := utils.NextUnitCost(ingredient, movement.StockBefore, quantityToAdd, purchaseUnitCost)
+				if err := tx.Model(&ingredient).Update("unit_cost", unitCost).Error; err != nil {
+					return err
+				}
+			}
+
+			// Create receipt item record
+			receiptItem := models.GoodsReceiptItem{
+				GoodsReceiptID:      receipt.ID,
+				PurchaseOrderLineID: line.ID,
+				IngredientID:        ingredient.ID,
+				QuantityReceived:    item.Quantity,
+				ReceivedUnitID:      line.UnitID,
+				UnitCost:            line.UnitCost,
+				QuantityAdded:       quantityToAdd,
+				StockBefore:         movement.StockBefore,
+				StockAfter:          movement.StockAfter,
+				UnitID:              ingredient.UnitID,
+			}
+
+			if err := tx.Create(&receiptItem).Error; err != nil {
+				return err
+			}
+
+			// Barang yang diterima menjadi lot baru untuk konsumsi FEFO
+			lot := models.StockLot{
+				IngredientID:       ingredient.ID,
+				LotNumber:          item.LotNumber,
+				ReceivedDate:       receivedDate,
+				ExpiryDate:         expiryDates[i],
+				Quantity:           quantityToAdd,
+				Remaining:          quantityToAdd,
+				UnitID:             ingredient.UnitID,
+				GoodsReceiptItemID: &receiptItem.ID,
+			}
+
+			if err := tx.Create(&lot).Error; err != nil {
+				return err
+			}
+
+			// Update received quantity on the PO line
+			line.ReceivedQuantity += item.Quantity
+			if err := tx.Model(line).Update("received_quantity", line.ReceivedQuantity).Error; err != nil {
+				return err
+			}
+		}
+
+		// Update purchase order status
+		purchaseOrder.Status = models.PurchaseOrderStatusReceived
+		for _, line := range purchaseOrder.Lines {
+			if line.Outstanding() > 0 {
+				purchaseOrder.Status = models.PurchaseOrderStatusPartiallyReceived
+				break
+			}
+		}
+
+		return tx.Model(&purchaseOrder).Update("status", purchaseOrder.Status).Error
+	})
+	if err != nil {
+		respondStatusError(c, err)
+		return
+	}
+
+	preloadGoodsReceipt(config.DB).First(&receipt, receipt.ID)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Goods received successfully",
+		"data":    toGoodsReceiptDTO(receipt),
+	})
+}
+
+func preloadGoodsReceipt(db *gorm.DB) *gorm.DB {
+	return db.Preload("PurchaseOrder").
+		Preload("Items").
+		Preload("Items.Ingredient").
+		Preload("Items.ReceivedUnit").
+		Preload("Items.Unit")
+}
+
+func toGoodsReceiptDTO(receipt models.GoodsReceipt) dto.GoodsReceipt {
+	receiptDTO := dto.GoodsReceipt{
+		ID:              receipt.ID,
+		ReceiptNumber:   receipt.ReceiptNumber,
+		ReceivedDate:    receipt.ReceivedDate,
+		Notes:           receipt.Notes,
+		PurchaseOrderID: receipt.PurchaseOrderID,
+		PONumber:        receipt.PurchaseOrder.PONumber,
+		Items:           make([]dto.GoodsReceiptItem, 0, len(receipt.Items)),
+		CreatedAt:       receipt.CreatedAt,
+		UpdatedAt:       receipt.UpdatedAt,
+	}
+
+	for _, item := range receipt.Items {
+		receiptDTO.Items = append(receiptDTO.Items, dto.GoodsReceiptItem{
+			ID:                  item.ID,
+			PurchaseOrderLineID: item.PurchaseOrderLineID,
+			Ingredient: dto.PurchaseOrderLineIngredient{
+				ID:   item.Ingredient.ID,
+				Name: item.Ingredient.Name,
+				Slug: item.Ingredient.Slug,
+			},
+			QuantityReceived: item.QuantityReceived,
+			ReceivedUnit: dto.PurchaseOrderLineUnit{
+				ID:   item.ReceivedUnit.ID,
+				Name: item.ReceivedUnit.Name,
+			},
+			UnitCost:      item.UnitCost,
+			QuantityAdded: item.QuantityAdded,
+			StockBefore:   item.StockBefore,
+			StockAfter:    item.StockAfter,
+			Unit: dto.PurchaseOrderLineUnit{
+				ID:   item.Unit.ID,
+				Name: item.Unit.Name,
+			},
+		})
+	}
+
+	return receiptDTO
+}
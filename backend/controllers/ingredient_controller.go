@@ -1,28 +1,84 @@
 package controllers
 
 import (
-	"fmt"
 	"net/http"
-	"strings"
+	"strconv"
 
-	"AwisPalace_IngredientManagement/config"
 	"AwisPalace_IngredientManagement/dto"
-	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+	"AwisPalace_IngredientManagement/services"
 	"AwisPalace_IngredientManagement/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jinzhu/copier"
 )
 
+var ingredientSorts = utils.SortFields{
+	"id":         "id",
+	"name":       "name",
+	"stock":      "stock",
+	"unit_cost":  "unit_cost",
+	"created_at": "created_at",
+}
+
+// IngredientController serves the ingredient endpoints built on the service layer
+type IngredientController struct {
+	service *services.IngredientService
+}
+
+func NewIngredientController(service *services.IngredientService) *IngredientController {
+	return &IngredientController{service: service}
+}
+
 // GetIngredients godoc
 // @Summary Get Ingredients
-// @Description Get Ingredients
+// @Description Get Ingredients with pagination, sorting and filters
 // @Tags Ingredients
+// @Param page query int false "Page (default 1)"
+// @Param limit query int false "Items per page (default 20, max 100)"
+// @Param cursor query int false "ID of the last item of the previous page (sort by id only)"
+// @Param sort query string false "id, name, stock, unit_cost, created_at; prefix with - for descending"
+// @Param search query string false "Search by name"
+// @Param unit_id query int false "Unit ID"
+// @Param stock_below query number false "Only ingredients with stock below this value"
+// @Security BearerAuth
 // @Router /ingredients [get]
-func GetIngredients(c *gin.Context) {
-	var ingredients []models.Ingredient
+func (ctrl *IngredientController) GetIngredients(c *gin.Context) {
+	params, err := utils.ParseListParams(c.Request.URL.Query(), ingredientSorts, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
 
-	if err := config.DB.Preload("Unit").Find(&ingredients).Error; err != nil {
+	filter := repositories.IngredientFilter{Search: c.Query("search")}
+	if unitID := c.Query("unit_id"); unitID != "" {
+		id, err := strconv.ParseUint(unitID, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "unit_id must be a number",
+			})
+			return
+		}
+		filter.UnitID = uint(id)
+	}
+	if stockBelow := c.Query("stock_below"); stockBelow != "" {
+		threshold, err := strconv.ParseFloat(stockBelow, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "stock_below must be a number",
+			})
+			return
+		}
+		filter.StockBelow = &threshold
+	}
+
+	ingredients, meta, err := ctrl.service.List(filter, params)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status": "error",
 			"error":  err.Error(),
@@ -33,30 +89,86 @@ func GetIngredients(c *gin.Context) {
 	var result []dto.Ingredient
 	copier.Copy(&result, &ingredients)
 
-	// ==========================
-	// CEK STOK HAMPIR HABIS (<= 5)
-	// ==========================
-	var lowStockIngredients []string
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Get Data Ingredient Success",
+		"data":    result,
+		"meta":    meta,
+	})
+}
 
+// GetLowStockIngredients godoc
+// @Summary Get Low Stock Ingredients
+// @Description Get ingredients at or below their reorder point or minimum stock, with the shortage to the par level
+// @Tags Ingredients
+// @Security BearerAuth
+// @Router /ingredients/low-stock [get]
+func (ctrl *IngredientController) GetLowStockIngredients(c *gin.Context) {
+	ingredients, err := ctrl.service.LowStock()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	response := make([]dto.LowStockIngredient, 0, len(ingredients))
 	for _, ingredient := range ingredients {
-		if ingredient.Stock <= 5 {
-			lowStockIngredients = append(lowStockIngredients, ingredient.Name)
-		}
+		var unit dto.Unit
+		copier.Copy(&unit, &ingredient.Unit)
+
+		response = append(response, dto.LowStockIngredient{
+			ID:           ingredient.ID,
+			Name:         ingredient.Name,
+			Slug:         ingredient.Slug,
+			Stock:        ingredient.Stock,
+			Unit:         unit,
+			MinimumStock: ingredient.MinimumStock,
+			ReorderPoint: ingredient.ReorderPoint,
+			ParLevel:     ingredient.ParLevel,
+			BelowMinimum: ingredient.MinimumStock > 0 && ingredient.Stock <= ingredient.MinimumStock,
+			Shortage:     ingredient.Shortage(),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response,
+	})
+}
+
+// GetIngredient godoc
+// @Summary Get Ingredient
+// @Description Get Ingredient by ID with its suppliers, cheapest price per stock unit first
+// @Tags Ingredients
+// @Param id path int true "Ingredient ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.IngredientDetail
+// @Router /ingredients/{id} [get]
+func (ctrl *IngredientController) GetIngredient(c *gin.Context) {
+	id, ok := ingredientIDParam(c)
+	if !ok {
+		return
+	}
+
+	ingredient, offers, err := ctrl.service.Get(id)
+	if err != nil {
+		respondIngredientError(c, err)
+		return
 	}
 
-	message := "Get Data Ingredient Success"
+	var result dto.IngredientDetail
+	copier.Copy(&result.Ingredient, &ingredient)
 
-	if len(lowStockIngredients) > 0 {
-		message = fmt.Sprintf(
-			"%s sudah mulai habis",
-			strings.Join(lowStockIngredients, ", "),
-		)
+	result.Suppliers = make([]dto.SupplierIngredient, 0, len(offers))
+	for _, offer := range offers {
+		result.Suppliers = append(result.Suppliers, toSupplierIngredientDTO(offer))
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"status":  "success",
-		"message": message,
-		"data":    result,
+		"status": "success",
+		"data":   result,
 	})
 }
 
@@ -65,8 +177,9 @@ func GetIngredients(c *gin.Context) {
 // @Description Post Ingredients
 // @Tags Ingredients
 // @Param ingredient body dto.IngredientParamRequest true "Create ingredient"
+// @Security BearerAuth
 // @Router /ingredients [post]
-func PostIngredients(c *gin.Context) {
+func (ctrl *IngredientController) PostIngredients(c *gin.Context) {
 	var input dto.IngredientParamRequest
 
 	// Bind JSON ke struct
@@ -79,17 +192,10 @@ func PostIngredients(c *gin.Context) {
 		return
 	}
 
-	// Mapping DTO ke model database
-	ingredient := models.Ingredient{
-		Name:   input.Name,
-		Slug:   utils.GenerateSlug(input.Name),
-		Stock:  input.Stock,
-		UnitID: input.UnitID,
-	}
-
-	// Simpan ke database
-	if err := config.DB.Create(&ingredient).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+	// Stok awal dicatat lewat ledger
+	ingredient, err := ctrl.service.Create(input, currentUserID(c))
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{
 			"status":  "error",
 			"message": "Failed to create ingredient",
 			"error":   err.Error(),
@@ -114,9 +220,13 @@ func PostIngredients(c *gin.Context) {
 // @Tags Ingredients
 // @Param id path int true "Ingredient ID"
 // @Param ingredient body dto.IngredientParamRequest true "Updated ingredient data"
+// @Security BearerAuth
 // @Router /ingredients/{id} [put]
-func UpdateIngredients(c *gin.Context) {
-	id := c.Param("id")
+func (ctrl *IngredientController) UpdateIngredients(c *gin.Context) {
+	id, ok := ingredientIDParam(c)
+	if !ok {
+		return
+	}
 
 	var input dto.IngredientParamRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -128,26 +238,10 @@ func UpdateIngredients(c *gin.Context) {
 		return
 	}
 
-	var ingredient models.Ingredient
-	if err := config.DB.First(&ingredient, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"status":  "error",
-			"message": "Ingredient not found",
-		})
-		return
-	}
-
-	ingredient.Name = input.Name
-	ingredient.Slug = utils.GenerateSlug(input.Name)
-	ingredient.Stock = input.Stock
-	ingredient.UnitID = input.UnitID
-
-	if err := config.DB.Save(&ingredient).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to update ingredient",
-			"error":   err.Error(),
-		})
+	// Perubahan stok dicatat lewat ledger
+	ingredient, err := ctrl.service.Update(id, input, currentUserID(c))
+	if err != nil {
+		respondIngredientError(c, err)
 		return
 	}
 
@@ -166,34 +260,47 @@ func UpdateIngredients(c *gin.Context) {
 // @Description Delete a ingredient by ID
 // @Tags Ingredients
 // @Param id path string true "Ingredient ID"
+// @Security BearerAuth
 // @Router /ingredients/{id} [delete]
-func DeleteIngredients(c *gin.Context) {
-	id := c.Param("id")
+func (ctrl *IngredientController) DeleteIngredients(c *gin.Context) {
+	id, ok := ingredientIDParam(c)
+	if !ok {
+		return
+	}
 
-	var ingredient models.Ingredient
+	if err := ctrl.service.Delete(id); err != nil {
+		respondIngredientError(c, err)
+		return
+	}
 
-	// Cek apakah ingredient dengan ID tersebut ada
-	if err := config.DB.First(&ingredient, "id = ?", id).Error; err != nil {
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "ingredient deleted successfully",
+	})
+}
+
+// ingredientIDParam parses the id path parameter, responding 404 when it is invalid
+func ingredientIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"status":  "error",
 			"message": "Ingredient not found",
-			"error":   err.Error(),
 		})
-		return
+		return 0, false
 	}
+	return uint(id), true
+}
 
-	// Hapus data ingredient
-	if err := config.DB.Delete(&ingredient).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to delete ingredient",
-			"error":   err.Error(),
-		})
-		return
+func respondIngredientError(c *gin.Context, err error) {
+	status := serviceErrorStatus(err)
+	message := err.Error()
+	if status == http.StatusNotFound {
+		message = "Ingredient not found"
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "success",
-		"message": "ingredient deleted successfully",
+	c.JSON(status, gin.H{
+		"status":  "error",
+		"message": message,
 	})
 }
@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"AwisPalace_IngredientManagement/config"
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+	"AwisPalace_IngredientManagement/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/copier"
+	"gorm.io/gorm"
+)
+
+// GetIngredientConversions godoc
+// @Summary Get Ingredient Conversions
+// @Description Get unit conversions of an ingredient (e.g. 1 pcs = 60 g)
+// @Tags Ingredients
+// @Param id path int true "Ingredient ID"
+// @Security BearerAuth
+// @Router /ingredients/{id}/conversions [get]
+func GetIngredientConversions(c *gin.Context) {
+	id := c.Param("id")
+
+	var ingredient models.Ingredient
+	if err := preloadIngredientUnits(config.DB).First(&ingredient, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Ingredient not found",
+		})
+		return
+	}
+
+	result := make([]dto.IngredientConversion, 0)
+	copier.Copy(&result, &ingredient.Conversions)
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Get Data Ingredient Conversion Success",
+		"data":    result,
+	})
+}
+
+// PostIngredientConversion godoc
+// @Summary Post Ingredient Conversion
+// @Description Add a unit conversion to an ingredient, 1 unit_id = quantity to_unit_id
+// @Tags Ingredients
+// @Param id path int true "Ingredient ID"
+// @Param conversion body dto.IngredientConversionRequest true "Conversion data"
+// @Security BearerAuth
+// @Router /ingredients/{id}/conversions [post]
+func PostIngredientConversion(c *gin.Context) {
+	id := c.Param("id")
+
+	var input dto.IngredientConversionRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid input data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	var ingredient models.Ingredient
+	if err := config.DB.First(&ingredient, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Ingredient not found",
+		})
+		return
+	}
+
+	var units []models.Unit
+	if err := config.DB.Find(&units, []uint{input.UnitID, input.ToUnitID}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if input.UnitID == input.ToUnitID || len(units) != 2 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "unit_id and to_unit_id must be two different existing units",
+		})
+		return
+	}
+
+	conversion := models.IngredientUnitConversion{
+		IngredientID: ingredient.ID,
+		UnitID:       input.UnitID,
+		Quantity:     input.Quantity,
+		ToUnitID:     input.ToUnitID,
+	}
+
+	if err := config.DB.Create(&conversion).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to create ingredient conversion",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	config.DB.Preload("Unit").Preload("ToUnit").First(&conversion, conversion.ID)
+
+	var result dto.IngredientConversion
+	copier.Copy(&result, &conversion)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Ingredient conversion created successfully",
+		"data":    result,
+	})
+}
+
+// DeleteIngredientConversion godoc
+// @Summary Delete Ingredient Conversion
+// @Description Delete a unit conversion of an ingredient
+// @Tags Ingredients
+// @Param id path int true "Ingredient ID"
+// @Param conversionId path int true "Conversion ID"
+// @Security BearerAuth
+// @Router /ingredients/{id}/conversions/{conversionId} [delete]
+func DeleteIngredientConversion(c *gin.Context) {
+	id := c.Param("id")
+	conversionID := c.Param("conversionId")
+
+	var conversion models.IngredientUnitConversion
+	if err := config.DB.Where("ingredient_id = ?", id).First(&conversion, conversionID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Ingredient conversion not found",
+		})
+		return
+	}
+
+	if err := config.DB.Delete(&conversion).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to delete ingredient conversion",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Ingredient conversion deleted successfully",
+	})
+}
+
+// preloadIngredientUnits memuat unit dasar ingredient beserta konversinya
+func preloadIngredientUnits(db *gorm.DB) *gorm.DB {
+	return repositories.PreloadIngredientUnits(db)
+}
+
+// validateIngredientUnit memastikan unitID bisa dikonversi ke unit stok ingredient
+func validateIngredientUnit(db *gorm.DB, ingredientID, unitID uint) (models.Ingredient, error) {
+	var ingredient models.Ingredient
+	if err := preloadIngredientUnits(db).First(&ingredient, ingredientID).Error; err != nil {
+		return ingredient, fmt.Errorf("ingredient with ID %d not found", ingredientID)
+	}
+
+	var unit models.Unit
+	if err := db.First(&unit, unitID).Error; err != nil {
+		return ingredient, fmt.Errorf("unit with ID %d not found", unitID)
+	}
+
+	if _, err := utils.ConvertQuantity(1, unit, ingredient.Unit, ingredient.Conversions); err != nil {
+		return ingredient, fmt.Errorf("ingredient %s: %w", ingredient.Name, err)
+	}
+
+	return ingredient, nil
+}
@@ -2,6 +2,8 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
+	"mime/multipart"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -10,30 +12,83 @@ import (
 	"AwisPalace_IngredientManagement/config"
 	"AwisPalace_IngredientManagement/dto"
 	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+	"AwisPalace_IngredientManagement/services"
 	"AwisPalace_IngredientManagement/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
+// MenuController serves the menu endpoints backed by a MenuService
+type MenuController struct {
+	service *services.MenuService
+}
+
+func NewMenuController(service *services.MenuService) *MenuController {
+	return &MenuController{service: service}
+}
+
 // ==================== GET MENUS ====================
 
+var menuSorts = utils.SortFields{
+	"id":         "id",
+	"name":       "name",
+	"price":      "price",
+	"created_at": "created_at",
+}
+
 // GetMenus godoc
 // @Summary Get Menus
-// @Description Get all menus with ingredients
+// @Description Get menus with ingredients, with pagination, sorting and filters
 // @Tags Menus
 // @Produce json
+// @Param page query int false "Page (default 1)"
+// @Param limit query int false "Items per page (default 20, max 100)"
+// @Param cursor query int false "ID of the last item of the previous page (sort by id only)"
+// @Param sort query string false "id, name, price, created_at; prefix with - for descending"
+// @Param search query string false "Search by name"
+// @Param ingredient_id query int false "Only menus using this ingredient"
+// @Param available_only query bool false "Only menus that are not sold out and can be made from the current stock"
 // @Success 200 {object} map[string]interface{}
+// @Security BearerAuth
 // @Router /menus [get]
-func GetMenus(c *gin.Context) {
-	var menus []models.Menu
+func (ctrl *MenuController) GetMenus(c *gin.Context) {
+	params, err := utils.ParseListParams(c.Request.URL.Query(), menuSorts, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
 
-	if err := config.DB.
-		Preload("MenuIngredients").
-		Preload("MenuIngredients.Ingredient").
-		Preload("MenuIngredients.Unit").
-		Find(&menus).Error; err != nil {
+	filter := repositories.MenuFilter{Search: c.Query("search")}
+	if ingredientID := c.Query("ingredient_id"); ingredientID != "" {
+		id, err := strconv.ParseUint(ingredientID, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "ingredient_id must be a number",
+			})
+			return
+		}
+		filter.IngredientID = uint(id)
+	}
+	if availableOnly := c.Query("available_only"); availableOnly != "" {
+		value, err := strconv.ParseBool(availableOnly)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "available_only must be true or false",
+			})
+			return
+		}
+		filter.AvailableOnly = value
+	}
 
+	menus, meta, err := ctrl.service.List(filter, params)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "error",
 			"message": err.Error(),
@@ -41,42 +96,16 @@ func GetMenus(c *gin.Context) {
 		return
 	}
 
-	var response []dto.Menu
+	response := make([]dto.Menu, 0, len(menus))
 
 	for _, menu := range menus {
-		menuDTO := dto.Menu{
-			ID:          menu.ID,
-			Name:        menu.Name,
-			Slug:        menu.Slug,
-			Image:       menu.Image,
-			Price:       menu.Price,
-			Description: menu.Description,
-			CreatedAt:   menu.CreatedAt,
-			UpdatedAt:   menu.UpdatedAt,
-		}
-
-		for _, mi := range menu.MenuIngredients {
-			menuDTO.Ingredients = append(menuDTO.Ingredients, dto.MenuIngredient{
-				ID: mi.ID,
-				Ingredient: dto.MenuIngredientIngredient{
-					ID:   mi.Ingredient.ID,
-					Name: mi.Ingredient.Name,
-					Slug: mi.Ingredient.Slug,
-				},
-				Quantity: mi.Quantity,
-				Unit: dto.MenuIngredientUnit{
-					ID:   mi.Unit.ID,
-					Name: mi.Unit.Name,
-				},
-			})
-		}
-
-		response = append(response, menuDTO)
+		response = append(response, toMenuDTO(menu))
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"status": "success",
 		"data":   response,
+		"meta":   meta,
 	})
 }
 
@@ -84,56 +113,26 @@ func GetMenus(c *gin.Context) {
 
 // GetMenu godoc
 // @Summary Get Menu
-// @Description Get Menu by ID
+// @Description Get Menu by ID with recipe cost, gross margin, food cost percentage and the portions that can be made from the current stock
 // @Tags Menus
 // @Param id path int true "Menu ID"
+// @Security BearerAuth
 // @Router /menus/{id} [get]
-func ShowMenu(c *gin.Context) {
-	id := c.Param("id")
-	var menu models.Menu
-
-	if err := config.DB.
-		Preload("MenuIngredients").
-		Preload("MenuIngredients.Ingredient").
-		Preload("MenuIngredients.Unit").
-		First(&menu, id).Error; err != nil {
-
-		c.JSON(http.StatusNotFound, gin.H{
-			"status":  "error",
-			"message": "Menu not found",
-		})
+func (ctrl *MenuController) ShowMenu(c *gin.Context) {
+	id, ok := menuIDParam(c)
+	if !ok {
 		return
 	}
 
-	menuDTO := dto.Menu{
-		ID:          menu.ID,
-		Name:        menu.Name,
-		Slug:        menu.Slug,
-		Image:       menu.Image,
-		Description: menu.Description,
-		CreatedAt:   menu.CreatedAt,
-		UpdatedAt:   menu.UpdatedAt,
-	}
-
-	for _, mi := range menu.MenuIngredients {
-		menuDTO.Ingredients = append(menuDTO.Ingredients, dto.MenuIngredient{
-			ID: mi.ID,
-			Ingredient: dto.MenuIngredientIngredient{
-				ID:   mi.Ingredient.ID,
-				Name: mi.Ingredient.Name,
-				Slug: mi.Ingredient.Slug,
-			},
-			Quantity: mi.Quantity,
-			Unit: dto.MenuIngredientUnit{
-				ID:   mi.Unit.ID,
-				Name: mi.Unit.Name,
-			},
-		})
+	menu, err := ctrl.service.Get(id)
+	if err != nil {
+		respondMenuError(c, err)
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"status": "success",
-		"data":   menuDTO,
+		"data":   toMenuDTO(menu),
 	})
 }
 
@@ -151,48 +150,19 @@ func ShowMenu(c *gin.Context) {
 // @Param ingredients formData string true "Ingredients JSON array"
 // @Param image formData file true "Menu Image"
 // @Success 201 {object} map[string]interface{}
+// @Security BearerAuth
 // @Router /menus [post]
-func PostMenu(c *gin.Context) {
-	// Parse form data
-	name := c.PostForm("name")
-	priceStr := c.PostForm("price")
-	description := c.PostForm("description")
-	ingredientsStr := c.PostForm("ingredients")
-
-	// Validate required fields
-	if name == "" || priceStr == "" || ingredientsStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "name, price, and ingredients are required",
-		})
+func (ctrl *MenuController) PostMenu(c *gin.Context) {
+	input, ok := bindMenuForm(c)
+	if !ok {
 		return
 	}
 
-	// Parse price
-	price, err := strconv.ParseFloat(priceStr, 64)
-	if err != nil {
+	// Validate recipe units against ingredient stock units
+	if err := ctrl.service.ValidateRecipe(input.Ingredients); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
-			"message": "Invalid price format",
-		})
-		return
-	}
-
-	// Parse ingredients JSON
-	var ingredients []dto.MenuIngredientRequest
-	if err := json.Unmarshal([]byte(ingredientsStr), &ingredients); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid ingredients format: " + err.Error(),
-		})
-		return
-	}
-
-	// Validate ingredients
-	if len(ingredients) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "At least one ingredient is required",
+			"message": err.Error(),
 		})
 		return
 	}
@@ -207,82 +177,19 @@ func PostMenu(c *gin.Context) {
 		return
 	}
 
-	// Validate file extension
-	ext := filepath.Ext(file.Filename)
-	allowedExts := map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".gif": true}
-	if !allowedExts[ext] {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Only image files (jpg, jpeg, png, gif) are allowed",
-		})
-		return
-	}
-
-	// Generate unique filename
-	filename := uuid.New().String() + ext
-	uploadPath := "./uploads/" + filename
-
-	// Create uploads directory if not exists
-	if err := os.MkdirAll("./uploads", os.ModePerm); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to create upload directory",
-		})
-		return
-	}
-
-	// Save file
-	if err := c.SaveUploadedFile(file, uploadPath); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to upload image: " + err.Error(),
-		})
+	filename, ok := saveMenuImage(c, file)
+	if !ok {
 		return
 	}
+	input.Image = filename
 
-	// Start transaction
-	tx := config.DB.Begin()
-
-	menu := models.Menu{
-		Name:        name,
-		Slug:        utils.GenerateSlug(name),
-		Description: description,
-		Image:       filename,
-		Price:       price,
-	}
-
-	if err := tx.Create(&menu).Error; err != nil {
-		tx.Rollback()
-		os.Remove(uploadPath)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": err.Error(),
-		})
+	menu, err := ctrl.service.Create(input)
+	if err != nil {
+		os.Remove(filepath.Join(uploadDir(), filename))
+		respondMenuError(c, err)
 		return
 	}
 
-	// Insert ingredients
-	for _, item := range ingredients {
-		menuIngredient := models.MenuIngredient{
-			MenuID:       menu.ID,
-			IngredientID: item.IngredientID,
-			Quantity:     item.Quantity,
-			UnitID:       item.UnitID,
-		}
-
-		if err := tx.Create(&menuIngredient).Error; err != nil {
-			tx.Rollback()
-			os.Remove(uploadPath)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"status":  "error",
-				"message": err.Error(),
-			})
-			return
-		}
-	}
-
-	tx.Commit()
-
 	// Generate full image URL
 	baseURL := getBaseURL(c)
 	imageURL := baseURL + "/uploads/" + filename
@@ -313,171 +220,50 @@ func PostMenu(c *gin.Context) {
 // @Param ingredients formData string true "Ingredients JSON array"
 // @Param image formData file false "Menu Image (optional)"
 // @Success 200 {object} map[string]interface{}
+// @Security BearerAuth
 // @Router /menus/{id} [put]
-func UpdateMenu(c *gin.Context) {
-	menuID := c.Param("id")
-
-	// Parse form data
-	name := c.PostForm("name")
-	priceStr := c.PostForm("price")
-	description := c.PostForm("description")
-	ingredientsStr := c.PostForm("ingredients")
-
-	// Validate required fields
-	if name == "" || priceStr == "" || ingredientsStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "name, price, and ingredients are required",
-		})
+func (ctrl *MenuController) UpdateMenu(c *gin.Context) {
+	menuID, ok := menuIDParam(c)
+	if !ok {
 		return
 	}
 
-	// Parse price
-	price, err := strconv.ParseFloat(priceStr, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid price format",
-		})
+	input, ok := bindMenuForm(c)
+	if !ok {
 		return
 	}
 
-	// Parse ingredients JSON
-	var ingredients []dto.MenuIngredientRequest
-	if err := json.Unmarshal([]byte(ingredientsStr), &ingredients); err != nil {
+	// Validate recipe units against ingredient stock units
+	if err := ctrl.service.ValidateRecipe(input.Ingredients); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
-			"message": "Invalid ingredients format: " + err.Error(),
-		})
-		return
-	}
-
-	// Validate ingredients
-	if len(ingredients) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "At least one ingredient is required",
-		})
-		return
-	}
-
-	// Check if menu exists
-	var menu models.Menu
-	if err := config.DB.First(&menu, menuID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"status":  "error",
-			"message": "Menu not found",
+			"message": err.Error(),
 		})
 		return
 	}
 
-	// Store old image filename for deletion if new image is uploaded
-	oldImageFilename := menu.Image
-	var newFilename string
-
 	// Handle file upload (optional for update)
-	file, err := c.FormFile("image")
-	if err == nil {
-		// Validate file extension
-		ext := filepath.Ext(file.Filename)
-		allowedExts := map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".gif": true}
-		if !allowedExts[ext] {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Only image files (jpg, jpeg, png, gif) are allowed",
-			})
-			return
-		}
-
-		uploadDir := "./uploads"
-		newFilename = uuid.New().String() + ext // ✅ FIX: Assign ke variable newFilename
-		uploadPath := filepath.Join(uploadDir, newFilename)
-
-		if err := os.MkdirAll(uploadDir, 0755); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"status":  "error",
-				"message": "Failed to create upload directory",
-			})
-			return
-		}
-
-		if err := c.SaveUploadedFile(file, uploadPath); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"status":  "error",
-				"message": "Failed to upload image: " + err.Error(),
-			})
+	if file, err := c.FormFile("image"); err == nil {
+		filename, ok := saveMenuImage(c, file)
+		if !ok {
 			return
 		}
+		input.Image = filename
 	}
 
-	// Start transaction
-	tx := config.DB.Begin()
-
-	// Update menu
-	menu.Name = name
-	menu.Slug = utils.GenerateSlug(name)
-	menu.Description = description
-	menu.Price = price
-
-	// Update image only if new image uploaded
-	if newFilename != "" {
-		menu.Image = newFilename
-	}
-
-	if err := tx.Save(&menu).Error; err != nil {
-		tx.Rollback()
+	menu, oldImageFilename, err := ctrl.service.Update(menuID, input)
+	if err != nil {
 		// Delete new uploaded file if exists
-		if newFilename != "" {
-			os.Remove("./uploads/" + newFilename)
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": err.Error(),
-		})
-		return
-	}
-
-	// Delete old ingredients
-	if err := tx.Where("menu_id = ?", menu.ID).Delete(&models.MenuIngredient{}).Error; err != nil {
-		tx.Rollback()
-		if newFilename != "" {
-			os.Remove("./uploads/" + newFilename)
+		if input.Image != "" {
+			os.Remove(filepath.Join(uploadDir(), input.Image))
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to delete old ingredients",
-		})
+		respondMenuError(c, err)
 		return
 	}
 
-	// Insert new ingredients
-	for _, item := range ingredients {
-		menuIngredient := models.MenuIngredient{
-			MenuID:       menu.ID,
-			IngredientID: item.IngredientID,
-			Quantity:     item.Quantity,
-			UnitID:       item.UnitID,
-		}
-
-		if err := tx.Create(&menuIngredient).Error; err != nil {
-			tx.Rollback()
-			if newFilename != "" {
-				os.Remove("./uploads/" + newFilename)
-			}
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"status":  "error",
-				"message": err.Error(),
-			})
-			return
-		}
-	}
-
-	tx.Commit()
-
 	// Delete old image file if new image was uploaded
-	if newFilename != "" && oldImageFilename != "" {
-		oldImagePath := filepath.Join("./uploads", oldImageFilename)
-		os.Remove(oldImagePath)
+	if input.Image != "" && oldImageFilename != "" {
+		os.Remove(filepath.Join(uploadDir(), oldImageFilename))
 	}
 
 	// Generate full image URL
@@ -504,57 +290,215 @@ func UpdateMenu(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Menu ID"
 // @Success 200 {object} map[string]interface{}
+// @Security BearerAuth
 // @Router /menus/{id} [delete]
-func DeleteMenu(c *gin.Context) {
-	id := c.Param("id")
+func (ctrl *MenuController) DeleteMenu(c *gin.Context) {
+	id, ok := menuIDParam(c)
+	if !ok {
+		return
+	}
 
-	tx := config.DB.Begin()
+	menu, err := ctrl.service.Delete(id)
+	if err != nil {
+		respondMenuError(c, err)
+		return
+	}
 
-	var menu models.Menu
-	if err := tx.First(&menu, id).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusNotFound, gin.H{
+	// Delete image file after successful database deletion
+	if menu.Image != "" {
+		os.Remove(filepath.Join(uploadDir(), menu.Image))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Menu deleted successfully",
+	})
+}
+
+// ==================== SOLD OUT ====================
+
+// SetMenuSoldOut godoc
+// @Summary Mark Menu Sold Out
+// @Description Mark a menu as sold out (86'd) so it cannot be sold, or available again
+// @Tags Menus
+// @Param id path int true "Menu ID"
+// @Param request body dto.MenuSoldOutRequest true "Sold out flag"
+// @Security BearerAuth
+// @Router /menus/{id}/sold-out [put]
+func (ctrl *MenuController) SetMenuSoldOut(c *gin.Context) {
+	id, ok := menuIDParam(c)
+	if !ok {
+		return
+	}
+
+	var input dto.MenuSoldOutRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
-			"message": "Menu not found",
+			"message": err.Error(),
 		})
 		return
 	}
 
-	// Store image filename for deletion
-	imageFile := menu.Image
+	menu, err := ctrl.service.SetSoldOut(id, *input.SoldOut)
+	if err != nil {
+		respondMenuError(c, err)
+		return
+	}
 
-	// Delete ingredients first (foreign key constraint)
-	if err := tx.Where("menu_id = ?", menu.ID).
-		Delete(&models.MenuIngredient{}).Error; err != nil {
+	message := "Menu marked as available"
+	if menu.SoldOut {
+		message = "Menu marked as sold out"
+	}
 
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": message,
+		"data":    toMenuDTO(menu),
+	})
+}
+
+// ==================== MODIFIERS ====================
+
+// SetMenuModifiers godoc
+// @Summary Set Menu Modifiers
+// @Description Replace the modifier groups of a menu (size, add-ons, removals). Each option has a price delta and recipe changes per portion, a negative quantity removes from the recipe
+// @Tags Menus
+// @Accept json
+// @Produce json
+// @Param id path int true "Menu ID"
+// @Param request body dto.MenuModifiersRequest true "Modifier groups"
+// @Security BearerAuth
+// @Router /menus/{id}/modifiers [put]
+func (ctrl *MenuController) SetMenuModifiers(c *gin.Context) {
+	id, ok := menuIDParam(c)
+	if !ok {
+		return
+	}
+
+	var input dto.MenuModifiersRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
 			"message": err.Error(),
 		})
 		return
 	}
 
-	// Delete menu
-	if err := tx.Delete(&menu).Error; err != nil {
-		tx.Rollback()
+	menu, err := ctrl.service.SetModifiers(id, input.Groups)
+	if err != nil {
+		respondMenuError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Menu modifiers updated successfully",
+		"data":    toMenuDTO(menu),
+	})
+}
+
+// bindMenuForm reads name, price, description and the ingredients JSON of
+// the multipart form, responding with 400 when they are invalid
+func bindMenuForm(c *gin.Context) (services.MenuInput, bool) {
+	var input services.MenuInput
+
+	// Parse form data
+	input.Name = c.PostForm("name")
+	priceStr := c.PostForm("price")
+	input.Description = c.PostForm("description")
+	ingredientsStr := c.PostForm("ingredients")
+
+	// Validate required fields
+	if input.Name == "" || priceStr == "" || ingredientsStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "name, price, and ingredients are required",
+		})
+		return input, false
+	}
+
+	// Parse price
+	price, err := strconv.ParseFloat(priceStr, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid price format",
+		})
+		return input, false
+	}
+	input.Price = price
+
+	// Parse ingredients JSON
+	if err := json.Unmarshal([]byte(ingredientsStr), &input.Ingredients); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid ingredients format: " + err.Error(),
+		})
+		return input, false
+	}
+
+	return input, true
+}
+
+// saveMenuImage checks the extension of the uploaded file and stores it in
+// the upload directory under a new unique name
+func saveMenuImage(c *gin.Context, file *multipart.FileHeader) (string, bool) {
+	// Validate file extension
+	ext := filepath.Ext(file.Filename)
+	allowedExts := map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".gif": true}
+	if !allowedExts[ext] {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Only image files (jpg, jpeg, png, gif) are allowed",
+		})
+		return "", false
+	}
+
+	// Generate unique filename
+	filename := uuid.New().String() + ext
+
+	// Create uploads directory if not exists
+	if err := os.MkdirAll(uploadDir(), os.ModePerm); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "error",
-			"message": err.Error(),
+			"message": "Failed to create upload directory",
 		})
-		return
+		return "", false
 	}
 
-	tx.Commit()
+	if err := c.SaveUploadedFile(file, filepath.Join(uploadDir(), filename)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to upload image: " + err.Error(),
+		})
+		return "", false
+	}
 
-	// Delete image file after successful database deletion
-	if imageFile != "" {
-		os.Remove("./uploads/" + imageFile)
+	return filename, true
+}
+
+func menuIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Menu not found",
+		})
+		return 0, false
 	}
+	return uint(id), true
+}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "success",
-		"message": "Menu deleted successfully",
+func respondMenuError(c *gin.Context, err error) {
+	message := err.Error()
+	if errors.Is(err, services.ErrNotFound) {
+		message = "Menu not found"
+	}
+
+	c.JSON(serviceErrorStatus(err), gin.H{
+		"status":  "error",
+		"message": message,
 	})
 }
 
@@ -565,3 +509,91 @@ func getBaseURL(c *gin.Context) string {
 	}
 	return scheme + "://" + c.Request.Host
 }
+
+func toMenuDTO(menu models.Menu) dto.Menu {
+	menuDTO := dto.Menu{
+		ID:          menu.ID,
+		Name:        menu.Name,
+		Slug:        menu.Slug,
+		Image:       menu.Image,
+		Price:       menu.Price,
+		Description: menu.Description,
+		SoldOut:     menu.SoldOut,
+		CreatedAt:   menu.CreatedAt,
+		UpdatedAt:   menu.UpdatedAt,
+	}
+
+	for _, mi := range menu.MenuIngredients {
+		menuDTO.Ingredients = append(menuDTO.Ingredients, dto.MenuIngredient{
+			ID: mi.ID,
+			Ingredient: dto.MenuIngredientIngredient{
+				ID:   mi.Ingredient.ID,
+				Name: mi.Ingredient.Name,
+				Slug: mi.Ingredient.Slug,
+			},
+			Quantity: mi.Quantity,
+			Unit: dto.MenuIngredientUnit{
+				ID:   mi.Unit.ID,
+				Name: mi.Unit.Name,
+			},
+		})
+	}
+
+	for _, group := range menu.ModifierGroups {
+		groupDTO := dto.MenuModifierGroup{
+			ID:        group.ID,
+			Name:      group.Name,
+			MinSelect: group.MinSelect,
+			MaxSelect: group.MaxSelect,
+		}
+
+		for _, option := range group.Options {
+			optionDTO := dto.MenuModifierOption{
+				ID:         option.ID,
+				Name:       option.Name,
+				PriceDelta: option.PriceDelta,
+			}
+
+			for _, change := range option.Ingredients {
+				optionDTO.Ingredients = append(optionDTO.Ingredients, dto.MenuModifierIngredient{
+					Ingredient: dto.MenuIngredientIngredient{
+						ID:   change.Ingredient.ID,
+						Name: change.Ingredient.Name,
+						Slug: change.Ingredient.Slug,
+					},
+					Quantity: change.Quantity,
+					Unit: dto.MenuIngredientUnit{
+						ID:   change.Unit.ID,
+						Name: change.Unit.Name,
+					},
+				})
+			}
+
+			groupDTO.Options = append(groupDTO.Options, optionDTO)
+		}
+
+		menuDTO.ModifierGroups = append(menuDTO.ModifierGroups, groupDTO)
+	}
+
+	// Recipes saved before unit validation may not convert, leave cost empty then
+	if cost, err := utils.RecipeCost(menu.MenuIngredients); err == nil {
+		menuDTO.Cost = cost
+		menuDTO.GrossMargin = menu.Price - cost
+		menuDTO.FoodCostPercentage = utils.FoodCostPercentage(cost, menu.Price)
+	}
+
+	if portions, err := utils.AvailablePortions(menu.MenuIngredients); err == nil {
+		menuDTO.AvailablePortions = &portions
+		menuDTO.Available = !menu.SoldOut && portions > 0
+	}
+
+	return menuDTO
+}
+
+// uploadDir returns the directory menu images are stored in
+func uploadDir() string {
+	if config.App != nil && config.App.UploadDir != "" {
+		return config.App.UploadDir
+	}
+	return "./uploads"
+}
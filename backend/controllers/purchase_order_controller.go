@@ -0,0 +1,386 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"AwisPalace_IngredientManagement/config"
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+	"AwisPalace_IngredientManagement/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetPurchaseOrders godoc
+// @Summary Get Purchase Orders
+// @Description Get Purchase Orders with optional status filter
+// @Tags Purchase Orders
+// @Param status query string false "Status (draft, sent, partially_received, received)"
+// @Security BearerAuth
+// @Router /purchase-orders [get]
+func GetPurchaseOrders(c *gin.Context) {
+	var purchaseOrders []models.PurchaseOrder
+
+	query := repositories.PreloadPurchaseOrder(config.DB)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Order("created_at DESC").Find(&purchaseOrders).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	response := make([]dto.PurchaseOrder, 0, len(purchaseOrders))
+	for _, purchaseOrder := range purchaseOrders {
+		response = append(response, toPurchaseOrderDTO(purchaseOrder))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response,
+	})
+}
+
+// GetPurchaseOrder godoc
+// @Summary Get Purchase Order
+// @Description Get Purchase Order by ID
+// @Tags Purchase Orders
+// @Param id path int true "Purchase Order ID"
+// @Security BearerAuth
+// @Router /purchase-orders/{id} [get]
+func GetPurchaseOrder(c *gin.Context) {
+	id := c.Param("id")
+
+	var purchaseOrder models.PurchaseOrder
+	if err := repositories.PreloadPurchaseOrder(config.DB).First(&purchaseOrder, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Purchase order not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   toPurchaseOrderDTO(purchaseOrder),
+	})
+}
+
+// PostPurchaseOrder godoc
+// @Summary Create Purchase Order
+// @Description Create a draft Purchase Order
+// @Tags Purchase Orders
+// @Param purchase_order body dto.PurchaseOrderCreateRequest true "Create purchase order"
+// @Security BearerAuth
+// @Router /purchase-orders [post]
+func PostPurchaseOrder(c *gin.Context) {
+	var input dto.PurchaseOrderCreateRequest
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	purchaseOrder := models.PurchaseOrder{
+		PONumber: utils.NewPurchaseOrderNumber(time.Now()),
+		Status:   models.PurchaseOrderStatusDraft,
+	}
+
+	if err := applyPurchaseOrderInput(&purchaseOrder, input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	err := utils.RunInTransaction(config.DB, func(tx *gorm.DB) error {
+		purchaseOrder.ID = 0
+		if err := tx.Create(&purchaseOrder).Error; err != nil {
+			return err
+		}
+		return savePurchaseOrderLines(tx, &purchaseOrder, input.Lines)
+	})
+	if err != nil {
+		respondStatusError(c, err)
+		return
+	}
+
+	repositories.PreloadPurchaseOrder(config.DB).First(&purchaseOrder, purchaseOrder.ID)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Purchase order created successfully",
+		"data":    toPurchaseOrderDTO(purchaseOrder),
+	})
+}
+
+// UpdatePurchaseOrder godoc
+// @Summary Update Purchase Order
+// @Description Update a draft Purchase Order by ID (lines are replaced)
+// @Tags Purchase Orders
+// @Param id path int true "Purchase Order ID"
+// @Param purchase_order body dto.PurchaseOrderCreateRequest true "Updated purchase order"
+// @Security BearerAuth
+// @Router /purchase-orders/{id} [put]
+func UpdatePurchaseOrder(c *gin.Context) {
+	id := c.Param("id")
+
+	var input dto.PurchaseOrderCreateRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var purchaseOrder models.PurchaseOrder
+	if err := config.DB.First(&purchaseOrder, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Purchase order not found",
+		})
+		return
+	}
+
+	if purchaseOrder.Status != models.PurchaseOrderStatusDraft {
+		c.JSON(http.StatusConflict, gin.H{
+			"status":  "error",
+			"message": "Only draft purchase orders can be updated",
+		})
+		return
+	}
+
+	if err := applyPurchaseOrderInput(&purchaseOrder, input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	err := utils.RunInTransaction(config.DB, func(tx *gorm.DB) error {
+		// Delete old lines
+		if err := tx.Where("purchase_order_id = ?", purchaseOrder.ID).Delete(&models.PurchaseOrderLine{}).Error; err != nil {
+			return fmt.Errorf("Failed to delete old lines: %w", err)
+		}
+		return savePurchaseOrderLines(tx, &purchaseOrder, input.Lines)
+	})
+	if err != nil {
+		respondStatusError(c, err)
+		return
+	}
+
+	repositories.PreloadPurchaseOrder(config.DB).First(&purchaseOrder, purchaseOrder.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Purchase order updated successfully",
+		"data":    toPurchaseOrderDTO(purchaseOrder),
+	})
+}
+
+// SendPurchaseOrder godoc
+// @Summary Send Purchase Order
+// @Description Mark a draft Purchase Order as sent to the supplier
+// @Tags Purchase Orders
+// @Param id path int true "Purchase Order ID"
+// @Security BearerAuth
+// @Router /purchase-orders/{id}/send [post]
+func SendPurchaseOrder(c *gin.Context) {
+	id := c.Param("id")
+
+	var purchaseOrder models.PurchaseOrder
+	if err := config.DB.First(&purchaseOrder, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Purchase order not found",
+		})
+		return
+	}
+
+	if purchaseOrder.Status != models.PurchaseOrderStatusDraft {
+		c.JSON(http.StatusConflict, gin.H{
+			"status":  "error",
+			"message": "Only draft purchase orders can be sent",
+		})
+		return
+	}
+
+	purchaseOrder.Status = models.PurchaseOrderStatusSent
+	if err := config.DB.Save(&purchaseOrder).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Purchase order sent successfully",
+		"data": gin.H{
+			"id":     purchaseOrder.ID,
+			"status": purchaseOrder.Status,
+		},
+	})
+}
+
+// DeletePurchaseOrder godoc
+// @Summary Delete Purchase Order
+// @Description Delete a draft Purchase Order by ID
+// @Tags Purchase Orders
+// @Param id path int true "Purchase Order ID"
+// @Security BearerAuth
+// @Router /purchase-orders/{id} [delete]
+func DeletePurchaseOrder(c *gin.Context) {
+	id := c.Param("id")
+
+	err := utils.RunInTransaction(config.DB, func(tx *gorm.DB) error {
+		var purchaseOrder models.PurchaseOrder
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&purchaseOrder, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return withStatus(http.StatusNotFound, errors.New("Purchase order not found"))
+			}
+			return err
+		}
+
+		if purchaseOrder.Status != models.PurchaseOrderStatusDraft {
+			return withStatus(http.StatusConflict, errors.New("Only draft purchase orders can be deleted"))
+		}
+
+		if err := tx.Where("purchase_order_id = ?", purchaseOrder.ID).Delete(&models.PurchaseOrderLine{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&purchaseOrder).Error
+	})
+	if err != nil {
+		respondStatusError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Purchase order deleted successfully",
+	})
+}
+
+// applyPurchaseOrderInput memetakan header PO dari request
+func applyPurchaseOrderInput(purchaseOrder *models.PurchaseOrder, input dto.PurchaseOrderCreateRequest) error {
+	purchaseOrder.OrderDate = time.Now()
+	if input.OrderDate != "" {
+		orderDate, err := time.Parse("2006-01-02", input.OrderDate)
+		if err != nil {
+			return errors.New("Invalid order_date format (YYYY-MM-DD)")
+		}
+		purchaseOrder.OrderDate = orderDate
+	}
+
+	purchaseOrder.ExpectedDate = nil
+	if input.ExpectedDate != "" {
+		expectedDate, err := time.Parse("2006-01-02", input.ExpectedDate)
+		if err != nil {
+			return errors.New("Invalid expected_date format (YYYY-MM-DD)")
+		}
+		purchaseOrder.ExpectedDate = &expectedDate
+	}
+
+	purchaseOrder.Notes = input.Notes
+
+	purchaseOrder.SupplierID = nil
+	if input.SupplierID != nil {
+		if err := config.DB.First(&models.Supplier{}, *input.SupplierID).Error; err != nil {
+			return errors.New("Supplier not found")
+		}
+		purchaseOrder.SupplierID = input.SupplierID
+	}
+
+	return nil
+}
+
+// savePurchaseOrderLines menyimpan line PO dan menghitung ulang total
+func savePurchaseOrderLines(tx *gorm.DB, purchaseOrder *models.PurchaseOrder, lines []dto.PurchaseOrderLineRequest) error {
+	var totalAmount float64
+
+	for _, line := range lines {
+		if _, err := validateIngredientUnit(tx, line.IngredientID, line.UnitID); err != nil {
+			return withStatus(http.StatusBadRequest, err)
+		}
+
+		purchaseOrderLine := models.PurchaseOrderLine{
+			PurchaseOrderID: purchaseOrder.ID,
+			IngredientID:    line.IngredientID,
+			Quantity:        line.Quantity,
+			UnitID:          line.UnitID,
+			UnitCost:        line.UnitCost,
+		}
+
+		if err := tx.Create(&purchaseOrderLine).Error; err != nil {
+			return err
+		}
+
+		totalAmount += line.Quantity * line.UnitCost
+	}
+
+	purchaseOrder.TotalAmount = totalAmount
+	return tx.Save(purchaseOrder).Error
+}
+
+func toPurchaseOrderDTO(purchaseOrder models.PurchaseOrder) dto.PurchaseOrder {
+	purchaseOrderDTO := dto.PurchaseOrder{
+		ID:           purchaseOrder.ID,
+		PONumber:     purchaseOrder.PONumber,
+		OrderDate:    purchaseOrder.OrderDate,
+		ExpectedDate: purchaseOrder.ExpectedDate,
+		Status:       purchaseOrder.Status,
+		TotalAmount:  purchaseOrder.TotalAmount,
+		Notes:        purchaseOrder.Notes,
+		Lines:        make([]dto.PurchaseOrderLine, 0, len(purchaseOrder.Lines)),
+		CreatedAt:    purchaseOrder.CreatedAt,
+		UpdatedAt:    purchaseOrder.UpdatedAt,
+	}
+
+	if purchaseOrder.Supplier != nil {
+		purchaseOrderDTO.Supplier = &dto.SupplierSummary{
+			ID:           purchaseOrder.Supplier.ID,
+			Name:         purchaseOrder.Supplier.Name,
+			LeadTimeDays: purchaseOrder.Supplier.LeadTimeDays,
+			PaymentTerms: purchaseOrder.Supplier.PaymentTerms,
+		}
+	}
+
+	for _, line := range purchaseOrder.Lines {
+		purchaseOrderDTO.Lines = append(purchaseOrderDTO.Lines, dto.PurchaseOrderLine{
+			ID: line.ID,
+			Ingredient: dto.PurchaseOrderLineIngredient{
+				ID:   line.Ingredient.ID,
+				Name: line.Ingredient.Name,
+				Slug: line.Ingredient.Slug,
+			},
+			Quantity:         line.Quantity,
+			ReceivedQuantity: line.ReceivedQuantity,
+			Unit: dto.PurchaseOrderLineUnit{
+				ID:   line.Unit.ID,
+				Name: line.Unit.Name,
+			},
+			UnitCost: line.UnitCost,
+			Subtotal: line.Quantity * line.UnitCost,
+		})
+	}
+
+	return purchaseOrderDTO
+}
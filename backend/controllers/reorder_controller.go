@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReorderController serves the reorder suggestion endpoints
+type ReorderController struct {
+	service *services.ReorderService
+}
+
+func NewReorderController(service *services.ReorderService) *ReorderController {
+	return &ReorderController{service: service}
+}
+
+// GetReorderSuggestions godoc
+// @Summary Get Reorder Suggestions
+// @Description Suggest order quantities from the average daily usage of the last days of sales, the current stock, open purchase orders, the lead time of the cheapest supplier and the par level
+// @Tags Ingredients
+// @Param days query int false "Days of sales used for the average daily usage (default 28, max 365)"
+// @Security BearerAuth
+// @Router /ingredients/reorder-suggestions [get]
+func (ctrl *ReorderController) GetReorderSuggestions(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(services.DefaultReorderWindowDays)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "days must be a number",
+		})
+		return
+	}
+
+	suggestions, err := ctrl.service.Suggestions(days)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	response := make([]dto.ReorderSuggestion, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		response = append(response, toReorderSuggestionDTO(suggestion))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response,
+	})
+}
+
+// PostReorderDrafts godoc
+// @Summary Create Purchase Orders from Reorder Suggestions
+// @Description Turn the reorder suggestions into draft purchase orders, one per supplier
+// @Tags Purchase Orders
+// @Param request body dto.ReorderDraftRequest false "Usage window and ingredients to order"
+// @Security BearerAuth
+// @Router /purchase-orders/from-suggestions [post]
+func (ctrl *ReorderController) PostReorderDrafts(c *gin.Context) {
+	var input dto.ReorderDraftRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+			return
+		}
+	}
+	if input.Days == 0 {
+		input.Days = services.DefaultReorderWindowDays
+	}
+
+	purchaseOrders, err := ctrl.service.CreateDrafts(input.Days, input.IngredientIDs)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	response := make([]dto.PurchaseOrder, 0, len(purchaseOrders))
+	for _, purchaseOrder := range purchaseOrders {
+		response = append(response, toPurchaseOrderDTO(purchaseOrder))
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Draft purchase orders created successfully",
+		"data":    response,
+	})
+}
+
+func toReorderSuggestionDTO(suggestion services.ReorderSuggestion) dto.ReorderSuggestion {
+	ingredient := suggestion.Ingredient
+
+	suggestionDTO := dto.ReorderSuggestion{
+		Ingredient: dto.PurchaseOrderLineIngredient{
+			ID:   ingredient.ID,
+			Name: ingredient.Name,
+			Slug: ingredient.Slug,
+		},
+		Unit: dto.PurchaseOrderLineUnit{
+			ID:   ingredient.Unit.ID,
+			Name: ingredient.Unit.Name,
+		},
+		Stock:             ingredient.Stock,
+		OnOrder:           suggestion.OnOrder,
+		AverageDailyUsage: suggestion.AverageDailyUsage,
+		LeadTimeDays:      suggestion.LeadTimeDays,
+		StockAtDelivery:   suggestion.StockAtDelivery,
+		MinimumStock:      ingredient.MinimumStock,
+		ReorderPoint:      ingredient.ReorderPoint,
+		ParLevel:          ingredient.ParLevel,
+		SuggestedQuantity: suggestion.SuggestedQuantity,
+		OrderQuantity:     suggestion.OrderQuantity,
+		OrderUnit: dto.PurchaseOrderLineUnit{
+			ID:   suggestion.OrderUnit.ID,
+			Name: suggestion.OrderUnit.Name,
+		},
+		Packs:         suggestion.Packs,
+		UnitCost:      suggestion.UnitCost,
+		EstimatedCost: suggestion.EstimatedCost,
+	}
+
+	if suggestion.Offer != nil {
+		supplier := suggestion.Offer.Supplier
+		suggestionDTO.Supplier = &dto.SupplierSummary{
+			ID:           supplier.ID,
+			Name:         supplier.Name,
+			LeadTimeDays: supplier.LeadTimeDays,
+			PaymentTerms: supplier.PaymentTerms,
+		}
+	}
+
+	return suggestionDTO
+}
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"AwisPalace_IngredientManagement/services"
+	"AwisPalace_IngredientManagement/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serviceErrorStatus maps an error returned by a service to an HTTP status
+func serviceErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidInput),
+		errors.Is(err, utils.ErrInsufficientStock),
+		errors.Is(err, utils.ErrIncompatibleUnit):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrConflict):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// statusError is an error raised inside a database transaction together with
+// the HTTP status to respond with once the transaction is rolled back
+type statusError struct {
+	status int
+	err    error
+}
+
+func (e *statusError) Error() string {
+	return e.err.Error()
+}
+
+func (e *statusError) Unwrap() error {
+	return e.err
+}
+
+func withStatus(status int, err error) error {
+	return &statusError{status: status, err: err}
+}
+
+// respondStatusError responds with the status attached by withStatus, the
+// status of a service error, or 500
+func respondStatusError(c *gin.Context, err error) {
+	status := serviceErrorStatus(err)
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		status = statusErr.status
+	}
+
+	c.JSON(status, gin.H{
+		"status":  "error",
+		"message": err.Error(),
+	})
+}
@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"AwisPalace_IngredientManagement/config"
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var stockAlertSorts = utils.SortFields{
+	"id":         "id",
+	"created_at": "created_at",
+}
+
+// GetStockAlerts godoc
+// @Summary Get Stock Alerts
+// @Description Get low stock alerts raised by stock movements, newest first
+// @Tags Stock Alerts
+// @Param acknowledged query bool false "Filter by acknowledged state"
+// @Param ingredient_id query int false "Ingredient ID"
+// @Param level query string false "Level (below_reorder_point, below_minimum)"
+// @Param page query int false "Page (default 1)"
+// @Param limit query int false "Items per page (default 20, max 100)"
+// @Param cursor query int false "ID of the last item of the previous page (sort by id only)"
+// @Param sort query string false "id, created_at; prefix with - for descending (default -id)"
+// @Security BearerAuth
+// @Router /stock-alerts [get]
+func GetStockAlerts(c *gin.Context) {
+	var alerts []models.StockAlert
+
+	params, err := utils.ParseListParams(c.Request.URL.Query(), stockAlertSorts, "-id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	query := config.DB.Model(&models.StockAlert{})
+	switch c.Query("acknowledged") {
+	case "true":
+		query = query.Where("acknowledged_at IS NOT NULL")
+	case "false":
+		query = query.Where("acknowledged_at IS NULL")
+	}
+	if ingredientID := c.Query("ingredient_id"); ingredientID != "" {
+		query = query.Where("ingredient_id = ?", ingredientID)
+	}
+	if level := c.Query("level"); level != "" {
+		query = query.Where("level = ?", level)
+	}
+
+	meta, err := utils.Paginate(query, params, &alerts, func(db *gorm.DB) *gorm.DB {
+		return db.Preload("Ingredient")
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	response := make([]dto.StockAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		response = append(response, toStockAlertDTO(alert))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response,
+		"meta":   meta,
+	})
+}
+
+// AcknowledgeStockAlert godoc
+// @Summary Acknowledge Stock Alert
+// @Description Mark a stock alert as acknowledged by the current user
+// @Tags Stock Alerts
+// @Param id path int true "Stock Alert ID"
+// @Security BearerAuth
+// @Router /stock-alerts/{id}/acknowledge [post]
+func AcknowledgeStockAlert(c *gin.Context) {
+	id := c.Param("id")
+
+	var alert models.StockAlert
+	if err := config.DB.Preload("Ingredient").First(&alert, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Stock alert not found",
+		})
+		return
+	}
+
+	if alert.AcknowledgedAt != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"status":  "error",
+			"message": "Stock alert is already acknowledged",
+		})
+		return
+	}
+
+	now := time.Now()
+	alert.AcknowledgedAt = &now
+	alert.AcknowledgedByID = currentUserID(c)
+
+	if err := config.DB.Model(&alert).Updates(map[string]interface{}{
+		"acknowledged_at":    alert.AcknowledgedAt,
+		"acknowledged_by_id": alert.AcknowledgedByID,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Stock alert acknowledged",
+		"data":    toStockAlertDTO(alert),
+	})
+}
+
+func toStockAlertDTO(alert models.StockAlert) dto.StockAlert {
+	return dto.StockAlert{
+		ID: alert.ID,
+		Ingredient: dto.StockAlertIngredient{
+			ID:   alert.Ingredient.ID,
+			Name: alert.Ingredient.Name,
+			Slug: alert.Ingredient.Slug,
+		},
+		Level:            alert.Level,
+		Stock:            alert.Stock,
+		Threshold:        alert.Threshold,
+		StockMovementID:  alert.StockMovementID,
+		AcknowledgedAt:   alert.AcknowledgedAt,
+		AcknowledgedByID: alert.AcknowledgedByID,
+		CreatedAt:        alert.CreatedAt,
+	}
+}
@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StockLotController serves the stock lot (batch) endpoints
+type StockLotController struct {
+	service *services.StockLotService
+}
+
+func NewStockLotController(service *services.StockLotService) *StockLotController {
+	return &StockLotController{service: service}
+}
+
+// GetIngredientLots godoc
+// @Summary Get Ingredient Lots
+// @Description Get the lots of an ingredient that still hold stock, in the order sales consume them (first expiry first out)
+// @Tags Ingredients
+// @Param id path int true "Ingredient ID"
+// @Security BearerAuth
+// @Router /ingredients/{id}/lots [get]
+func (ctrl *StockLotController) GetIngredientLots(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Ingredient not found",
+		})
+		return
+	}
+
+	lots, err := ctrl.service.IngredientLots(uint(id))
+	if err != nil {
+		status := serviceErrorStatus(err)
+		message := err.Error()
+		if status == http.StatusNotFound {
+			message = "Ingredient not found"
+		}
+
+		c.JSON(status, gin.H{
+			"status":  "error",
+			"message": message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   toStockLotDTOs(lots),
+	})
+}
+
+// GetExpiringLots godoc
+// @Summary Get Expiring Lots
+// @Description Get the lots that still hold stock and expire within the given number of days, including lots that have already expired
+// @Tags Ingredients
+// @Param days query int false "Days from today (default 7)"
+// @Security BearerAuth
+// @Router /ingredients/expiring-lots [get]
+func (ctrl *StockLotController) GetExpiringLots(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "7"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "days must be a number",
+		})
+		return
+	}
+
+	lots, err := ctrl.service.Expiring(days)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   toStockLotDTOs(lots),
+	})
+}
+
+func toStockLotDTOs(lots []models.StockLot) []dto.StockLot {
+	now := time.Now()
+
+	response := make([]dto.StockLot, 0, len(lots))
+	for _, lot := range lots {
+		days := lot.DaysUntilExpiry(now)
+		response = append(response, dto.StockLot{
+			ID: lot.ID,
+			Ingredient: dto.StockReductionIngredient{
+				ID:   lot.Ingredient.ID,
+				Name: lot.Ingredient.Name,
+				Slug: lot.Ingredient.Slug,
+			},
+			LotNumber:       lot.LotNumber,
+			ReceivedDate:    lot.ReceivedDate,
+			ExpiryDate:      lot.ExpiryDate,
+			DaysUntilExpiry: days,
+			Expired:         days != nil && *days < 0,
+			Quantity:        lot.Quantity,
+			Remaining:       lot.Remaining,
+			Unit: dto.StockReductionUnit{
+				ID:   lot.Unit.ID,
+				Name: lot.Unit.Name,
+			},
+			GoodsReceiptItemID: lot.GoodsReceiptItemID,
+		})
+	}
+	return response
+}
@@ -0,0 +1,181 @@
+package controllers
+
+import (
+	"math"
+	"net/http"
+
+	"AwisPalace_IngredientManagement/config"
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var stockMovementSorts = utils.SortFields{
+	"id":         "id",
+	"type":       "type",
+	"quantity":   "quantity",
+	"created_at": "created_at",
+}
+
+// GetIngredientMovements godoc
+// @Summary Get Ingredient Stock Movements
+// @Description Get the stock ledger of an ingredient with pagination, newest first. The reconciliation covers the whole ledger, not only the page.
+// @Tags Ingredients
+// @Param id path int true "Ingredient ID"
+// @Param page query int false "Page (default 1)"
+// @Param limit query int false "Items per page (default 20, max 100)"
+// @Param cursor query int false "ID of the last item of the previous page (sort by id only)"
+// @Param sort query string false "id, type, quantity, created_at; prefix with - for descending"
+// @Param type query string false "Movement type (sale, sale_reversal, purchase, adjustment, waste, transfer, stocktake)"
+// @Security BearerAuth
+// @Router /ingredients/{id}/movements [get]
+func GetIngredientMovements(c *gin.Context) {
+	id := c.Param("id")
+
+	params, err := utils.ParseListParams(c.Request.URL.Query(), stockMovementSorts, "-id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var ingredient models.Ingredient
+	if err := config.DB.First(&ingredient, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Ingredient not found",
+		})
+		return
+	}
+
+	query := config.DB.Model(&models.StockMovement{}).Where("ingredient_id = ?", ingredient.ID)
+	if movementType := c.Query("type"); movementType != "" {
+		query = query.Where("type = ?", movementType)
+	}
+
+	var movements []models.StockMovement
+	meta, err := utils.Paginate(query, params, &movements, func(db *gorm.DB) *gorm.DB {
+		return db.Preload("Unit")
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	// Saldo ledger dihitung dari seluruh movement, bukan hanya halaman ini,
+	// dan harus sama dengan stok saat ini
+	var ledgerStock float64
+	if err := config.DB.Model(&models.StockMovement{}).
+		Where("ingredient_id = ?", ingredient.ID).
+		Select("COALESCE(SUM(quantity), 0)").
+		Scan(&ledgerStock).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	response := make([]dto.StockMovement, 0, len(movements))
+	for _, movement := range movements {
+		response = append(response, toStockMovementDTO(movement))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data": gin.H{
+			"current_stock": ingredient.Stock,
+			"ledger_stock":  ledgerStock,
+			"reconciled":    math.Abs(ledgerStock-ingredient.Stock) < 0.00005,
+			"movements":     response,
+		},
+		"meta": meta,
+	})
+}
+
+// PostIngredientMovement godoc
+// @Summary Post Ingredient Stock Movement
+// @Description Record a manual stock adjustment or transfer for an ingredient
+// @Tags Ingredients
+// @Param id path int true "Ingredient ID"
+// @Param movement body dto.StockMovementCreateRequest true "Stock movement"
+// @Security BearerAuth
+// @Router /ingredients/{id}/movements [post]
+func PostIngredientMovement(c *gin.Context) {
+	id := c.Param("id")
+
+	var input dto.StockMovementCreateRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid input data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	var ingredient models.Ingredient
+	if err := config.DB.First(&ingredient, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Ingredient not found",
+		})
+		return
+	}
+
+	movement := models.StockMovement{
+		IngredientID:  ingredient.ID,
+		Type:          input.Type,
+		Quantity:      input.Quantity,
+		UserID:        currentUserID(c),
+		ReferenceType: "ingredient",
+		ReferenceID:   ingredient.ID,
+		Notes:         input.Notes,
+	}
+
+	err := utils.RunInTransaction(config.DB, func(tx *gorm.DB) error {
+		// Percobaan ulang setelah deadlock mencatat movement dari awal
+		movement.ID = 0
+		return utils.RecordStockMovement(tx, &movement)
+	})
+	if err != nil {
+		respondStatusError(c, err)
+		return
+	}
+
+	config.DB.Preload("Unit").First(&movement, movement.ID)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Stock movement recorded successfully",
+		"data":    toStockMovementDTO(movement),
+	})
+}
+
+func toStockMovementDTO(movement models.StockMovement) dto.StockMovement {
+	return dto.StockMovement{
+		ID:           movement.ID,
+		IngredientID: movement.IngredientID,
+		Type:         movement.Type,
+		Quantity:     movement.Quantity,
+		StockBefore:  movement.StockBefore,
+		StockAfter:   movement.StockAfter,
+		Unit: dto.StockMovementUnit{
+			ID:   movement.Unit.ID,
+			Name: movement.Unit.Name,
+		},
+		UserID:        movement.UserID,
+		ReferenceType: movement.ReferenceType,
+		ReferenceID:   movement.ReferenceID,
+		Notes:         movement.Notes,
+		CreatedAt:     movement.CreatedAt,
+	}
+}
@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"AwisPalace_IngredientManagement/config"
+	"AwisPalace_IngredientManagement/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+func TestPostIngredientMovementRetriesDeadlock(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ingredient, _ := setupTransactionTestDB(t, 10)
+
+	// Insert movement pertama gagal dengan deadlock, percobaan berikutnya lolos
+	deadlocks := 0
+	if err := config.DB.Callback().Create().Before("gorm:create").Register("test:deadlock", func(db *gorm.DB) {
+		if db.Statement.Table == "stock_movements" && deadlocks == 0 {
+			deadlocks++
+			db.AddError(&pgconn.PgError{Code: "40P01", Message: "deadlock detected"})
+		}
+	}); err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+
+	router := gin.New()
+	router.POST("/ingredients/:id/movements", PostIngredientMovement)
+
+	body, _ := json.Marshal(gin.H{"type": models.StockMovementAdjustment, "quantity": -3})
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/ingredients/%d/movements", ingredient.ID), bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status %d, want 201 after the retry: %s", w.Code, w.Body.String())
+	}
+	if deadlocks != 1 {
+		t.Fatalf("injected %d deadlocks, want 1", deadlocks)
+	}
+
+	var current models.Ingredient
+	if err := config.DB.First(&current, ingredient.ID).Error; err != nil {
+		t.Fatalf("load ingredient: %v", err)
+	}
+	if current.Stock != 7 {
+		t.Errorf("stock = %.2f, want 7", current.Stock)
+	}
+
+	var movements int64
+	config.DB.Model(&models.StockMovement{}).Where("ingredient_id = ?", ingredient.ID).Count(&movements)
+	if movements != 1 {
+		t.Errorf("movements = %d, want 1", movements)
+	}
+}
+
+func TestPostIngredientMovementReportsOtherErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ingredient, _ := setupTransactionTestDB(t, 10)
+
+	router := gin.New()
+	router.POST("/ingredients/:id/movements", PostIngredientMovement)
+
+	body, _ := json.Marshal(gin.H{"type": models.StockMovementAdjustment, "quantity": -11})
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/ingredients/%d/movements", ingredient.ID), bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status %d, want 400 for insufficient stock: %s", w.Code, w.Body.String())
+	}
+}
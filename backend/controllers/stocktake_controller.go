@@ -0,0 +1,356 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+	"AwisPalace_IngredientManagement/services"
+	"AwisPalace_IngredientManagement/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var stocktakeSorts = utils.SortFields{
+	"id":         "id",
+	"code":       "code",
+	"status":     "status",
+	"created_at": "created_at",
+	"posted_at":  "posted_at",
+}
+
+// StocktakeController serves the stocktake (stock opname) endpoints
+type StocktakeController struct {
+	service *services.StocktakeService
+}
+
+func NewStocktakeController(service *services.StocktakeService) *StocktakeController {
+	return &StocktakeController{service: service}
+}
+
+// GetStocktakes godoc
+// @Summary Get Stocktakes
+// @Description Get Stocktakes with pagination, sorting and filters
+// @Tags Stocktakes
+// @Param page query int false "Page (default 1)"
+// @Param limit query int false "Items per page (default 20, max 100)"
+// @Param cursor query int false "ID of the last item of the previous page (sort by id only)"
+// @Param sort query string false "id, code, status, created_at, posted_at; prefix with - for descending"
+// @Param status query string false "Status (draft, counting, posted)"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Router /stocktakes [get]
+func (ctrl *StocktakeController) GetStocktakes(c *gin.Context) {
+	params, err := utils.ParseListParams(c.Request.URL.Query(), stocktakeSorts, "-id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	stocktakes, meta, err := ctrl.service.List(repositories.StocktakeFilter{
+		Status: c.Query("status"),
+	}, params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]dto.Stocktake, 0, len(stocktakes))
+	for _, stocktake := range stocktakes {
+		response = append(response, toStocktakeDTO(stocktake))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response,
+		"meta":   meta,
+	})
+}
+
+// GetStocktake godoc
+// @Summary Get Stocktake
+// @Description Get Stocktake by ID with its counted lines
+// @Tags Stocktakes
+// @Param id path int true "Stocktake ID"
+// @Security BearerAuth
+// @Router /stocktakes/{id} [get]
+func (ctrl *StocktakeController) GetStocktake(c *gin.Context) {
+	id, ok := stocktakeID(c)
+	if !ok {
+		return
+	}
+
+	stocktake, err := ctrl.service.Get(id)
+	if err != nil {
+		respondStocktakeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   toStocktakeDTO(stocktake),
+	})
+}
+
+// PostStocktake godoc
+// @Summary Create Stocktake
+// @Description Create a draft Stocktake of the given ingredients, or of all ingredients when ingredient_ids is empty
+// @Tags Stocktakes
+// @Param stocktake body dto.StocktakeCreateRequest true "Create stocktake"
+// @Security BearerAuth
+// @Router /stocktakes [post]
+func (ctrl *StocktakeController) PostStocktake(c *gin.Context) {
+	var input dto.StocktakeCreateRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	stocktake, err := ctrl.service.Create(input, currentUserID(c))
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Stocktake created successfully",
+		"data":    toStocktakeDTO(stocktake),
+	})
+}
+
+// StartStocktake godoc
+// @Summary Start Stocktake
+// @Description Start counting a draft Stocktake. The current stock of every ingredient is taken as its theoretical stock.
+// @Tags Stocktakes
+// @Param id path int true "Stocktake ID"
+// @Security BearerAuth
+// @Router /stocktakes/{id}/start [post]
+func (ctrl *StocktakeController) StartStocktake(c *gin.Context) {
+	id, ok := stocktakeID(c)
+	if !ok {
+		return
+	}
+
+	stocktake, err := ctrl.service.Start(id)
+	if err != nil {
+		respondStocktakeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Stocktake started successfully",
+		"data":    toStocktakeDTO(stocktake),
+	})
+}
+
+// CountStocktake godoc
+// @Summary Record Stocktake Counts
+// @Description Enter counted quantities per ingredient in the ingredient's stock unit. Counting an ingredient again replaces the earlier quantity.
+// @Tags Stocktakes
+// @Param id path int true "Stocktake ID"
+// @Param counts body dto.StocktakeCountRequest true "Counted quantities"
+// @Security BearerAuth
+// @Router /stocktakes/{id}/counts [put]
+func (ctrl *StocktakeController) CountStocktake(c *gin.Context) {
+	id, ok := stocktakeID(c)
+	if !ok {
+		return
+	}
+
+	var input dto.StocktakeCountRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	stocktake, err := ctrl.service.Count(id, input.Counts)
+	if err != nil {
+		respondStocktakeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Counts recorded successfully",
+		"data":    toStocktakeDTO(stocktake),
+	})
+}
+
+// PostStocktakeVariance godoc
+// @Summary Post Stocktake
+// @Description Book the variance of every counted ingredient as a stocktake stock movement in one transaction
+// @Tags Stocktakes
+// @Param id path int true "Stocktake ID"
+// @Security BearerAuth
+// @Router /stocktakes/{id}/post [post]
+func (ctrl *StocktakeController) PostStocktakeVariance(c *gin.Context) {
+	id, ok := stocktakeID(c)
+	if !ok {
+		return
+	}
+
+	stocktake, err := ctrl.service.Post(id, currentUserID(c))
+	if err != nil {
+		respondStocktakeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Stocktake posted successfully",
+		"data":    toStocktakeDTO(stocktake),
+	})
+}
+
+// GetStocktakeVariance godoc
+// @Summary Stocktake Variance Report
+// @Description Variance per ingredient with its value at unit cost and percentage of the theoretical stock
+// @Tags Stocktakes
+// @Param id path int true "Stocktake ID"
+// @Security BearerAuth
+// @Router /stocktakes/{id}/variance [get]
+func (ctrl *StocktakeController) GetStocktakeVariance(c *gin.Context) {
+	id, ok := stocktakeID(c)
+	if !ok {
+		return
+	}
+
+	stocktake, err := ctrl.service.VarianceReport(id)
+	if err != nil {
+		respondStocktakeError(c, err)
+		return
+	}
+
+	report := dto.StocktakeVarianceReport{
+		ID:        stocktake.ID,
+		Code:      stocktake.Code,
+		Status:    stocktake.Status,
+		StartedAt: stocktake.StartedAt,
+		PostedAt:  stocktake.PostedAt,
+		Lines:     make([]dto.StocktakeLine, 0, len(stocktake.Lines)),
+	}
+	for _, line := range stocktake.Lines {
+		if line.Counted() {
+			report.CountedLines++
+		} else {
+			report.UncountedLines++
+		}
+		report.TotalVarianceValue += line.VarianceValue()
+		report.Lines = append(report.Lines, toStocktakeLineDTO(line))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   report,
+	})
+}
+
+// DeleteStocktake godoc
+// @Summary Delete Stocktake
+// @Description Delete a Stocktake that has not been posted
+// @Tags Stocktakes
+// @Param id path int true "Stocktake ID"
+// @Security BearerAuth
+// @Router /stocktakes/{id} [delete]
+func (ctrl *StocktakeController) DeleteStocktake(c *gin.Context) {
+	id, ok := stocktakeID(c)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.service.Delete(id); err != nil {
+		respondStocktakeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Stocktake deleted successfully",
+	})
+}
+
+// stocktakeID parses the id path parameter, responding 404 when it is invalid
+func stocktakeID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Stocktake not found",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func respondStocktakeError(c *gin.Context, err error) {
+	status := serviceErrorStatus(err)
+	message := err.Error()
+	if status == http.StatusNotFound {
+		message = "Stocktake not found"
+	}
+
+	c.JSON(status, gin.H{
+		"status":  "error",
+		"message": message,
+	})
+}
+
+func toStocktakeDTO(stocktake models.Stocktake) dto.Stocktake {
+	stocktakeDTO := dto.Stocktake{
+		ID:          stocktake.ID,
+		Code:        stocktake.Code,
+		Status:      stocktake.Status,
+		Notes:       stocktake.Notes,
+		StartedAt:   stocktake.StartedAt,
+		PostedAt:    stocktake.PostedAt,
+		CreatedByID: stocktake.CreatedByID,
+		PostedByID:  stocktake.PostedByID,
+		Lines:       make([]dto.StocktakeLine, 0, len(stocktake.Lines)),
+		CreatedAt:   stocktake.CreatedAt,
+		UpdatedAt:   stocktake.UpdatedAt,
+	}
+
+	for _, line := range stocktake.Lines {
+		stocktakeDTO.Lines = append(stocktakeDTO.Lines, toStocktakeLineDTO(line))
+	}
+
+	return stocktakeDTO
+}
+
+func toStocktakeLineDTO(line models.StocktakeLine) dto.StocktakeLine {
+	return dto.StocktakeLine{
+		ID: line.ID,
+		Ingredient: dto.StockReductionIngredient{
+			ID:   line.Ingredient.ID,
+			Name: line.Ingredient.Name,
+			Slug: line.Ingredient.Slug,
+		},
+		Unit: dto.StockReductionUnit{
+			ID:   line.Unit.ID,
+			Name: line.Unit.Name,
+		},
+		TheoreticalStock:   line.TheoreticalStock,
+		CountedQuantity:    line.CountedQuantity,
+		Variance:           line.Variance(),
+		VarianceValue:      line.VarianceValue(),
+		VariancePercentage: line.VariancePercentage(),
+		UnitCost:           line.UnitCost,
+		StockMovementID:    line.StockMovementID,
+	}
+}
@@ -0,0 +1,366 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+	"AwisPalace_IngredientManagement/services"
+	"AwisPalace_IngredientManagement/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var supplierSorts = utils.SortFields{
+	"id":             "id",
+	"name":           "name",
+	"lead_time_days": "lead_time_days",
+	"created_at":     "created_at",
+}
+
+// SupplierController serves the supplier directory endpoints
+type SupplierController struct {
+	service *services.SupplierService
+}
+
+func NewSupplierController(service *services.SupplierService) *SupplierController {
+	return &SupplierController{service: service}
+}
+
+// GetSuppliers godoc
+// @Summary Get Suppliers
+// @Description Get Suppliers with pagination, sorting and filters
+// @Tags Suppliers
+// @Param page query int false "Page (default 1)"
+// @Param limit query int false "Items per page (default 20, max 100)"
+// @Param cursor query int false "ID of the last item of the previous page (sort by id only)"
+// @Param sort query string false "id, name, lead_time_days, created_at; prefix with - for descending"
+// @Param search query string false "Search by name or contact name"
+// @Param ingredient_id query int false "Only suppliers of this ingredient"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Router /suppliers [get]
+func (ctrl *SupplierController) GetSuppliers(c *gin.Context) {
+	params, err := utils.ParseListParams(c.Request.URL.Query(), supplierSorts, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	filter := repositories.SupplierFilter{Search: c.Query("search")}
+	if ingredientID := c.Query("ingredient_id"); ingredientID != "" {
+		id, err := strconv.ParseUint(ingredientID, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "ingredient_id must be a number",
+			})
+			return
+		}
+		filter.IngredientID = uint(id)
+	}
+
+	suppliers, meta, err := ctrl.service.List(filter, params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]dto.Supplier, 0, len(suppliers))
+	for _, supplier := range suppliers {
+		response = append(response, toSupplierDTO(supplier))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response,
+		"meta":   meta,
+	})
+}
+
+// GetSupplier godoc
+// @Summary Get Supplier
+// @Description Get Supplier by ID with the ingredients it sells
+// @Tags Suppliers
+// @Param id path int true "Supplier ID"
+// @Security BearerAuth
+// @Router /suppliers/{id} [get]
+func (ctrl *SupplierController) GetSupplier(c *gin.Context) {
+	id, ok := supplierID(c)
+	if !ok {
+		return
+	}
+
+	supplier, err := ctrl.service.Get(id)
+	if err != nil {
+		respondSupplierError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   toSupplierDTO(supplier),
+	})
+}
+
+// PostSupplier godoc
+// @Summary Create Supplier
+// @Description Create Supplier
+// @Tags Suppliers
+// @Param supplier body dto.SupplierRequest true "Create supplier"
+// @Security BearerAuth
+// @Router /suppliers [post]
+func (ctrl *SupplierController) PostSupplier(c *gin.Context) {
+	var input dto.SupplierRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	supplier, err := ctrl.service.Create(input)
+	if err != nil {
+		respondSupplierError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Supplier created successfully",
+		"data":    toSupplierDTO(supplier),
+	})
+}
+
+// UpdateSupplier godoc
+// @Summary Update Supplier
+// @Description Update Supplier by ID
+// @Tags Suppliers
+// @Param id path int true "Supplier ID"
+// @Param supplier body dto.SupplierRequest true "Update supplier"
+// @Security BearerAuth
+// @Router /suppliers/{id} [put]
+func (ctrl *SupplierController) UpdateSupplier(c *gin.Context) {
+	id, ok := supplierID(c)
+	if !ok {
+		return
+	}
+
+	var input dto.SupplierRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	supplier, err := ctrl.service.Update(id, input)
+	if err != nil {
+		respondSupplierError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Supplier updated successfully",
+		"data":    toSupplierDTO(supplier),
+	})
+}
+
+// DeleteSupplier godoc
+// @Summary Delete Supplier
+// @Description Delete Supplier by ID together with its ingredient links
+// @Tags Suppliers
+// @Param id path int true "Supplier ID"
+// @Security BearerAuth
+// @Router /suppliers/{id} [delete]
+func (ctrl *SupplierController) DeleteSupplier(c *gin.Context) {
+	id, ok := supplierID(c)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.service.Delete(id); err != nil {
+		respondSupplierError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Supplier deleted successfully",
+	})
+}
+
+// PutSupplierIngredient godoc
+// @Summary Link Ingredient to Supplier
+// @Description Add or update the SKU, pack size, pack unit and last price of an ingredient sold by the supplier
+// @Tags Suppliers
+// @Param id path int true "Supplier ID"
+// @Param ingredientId path int true "Ingredient ID"
+// @Param ingredient body dto.SupplierIngredientRequest true "Supplier ingredient"
+// @Security BearerAuth
+// @Router /suppliers/{id}/ingredients/{ingredientId} [put]
+func (ctrl *SupplierController) PutSupplierIngredient(c *gin.Context) {
+	id, ok := supplierID(c)
+	if !ok {
+		return
+	}
+
+	ingredientID, err := strconv.ParseUint(c.Param("ingredientId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Ingredient not found",
+		})
+		return
+	}
+
+	var input dto.SupplierIngredientRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	offer, err := ctrl.service.SetIngredient(id, uint(ingredientID), input)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Supplier ingredient saved successfully",
+		"data":    toSupplierIngredientDTO(offer),
+	})
+}
+
+// DeleteSupplierIngredient godoc
+// @Summary Unlink Ingredient from Supplier
+// @Description Remove an ingredient from the supplier
+// @Tags Suppliers
+// @Param id path int true "Supplier ID"
+// @Param ingredientId path int true "Ingredient ID"
+// @Security BearerAuth
+// @Router /suppliers/{id}/ingredients/{ingredientId} [delete]
+func (ctrl *SupplierController) DeleteSupplierIngredient(c *gin.Context) {
+	id, ok := supplierID(c)
+	if !ok {
+		return
+	}
+
+	ingredientID, err := strconv.ParseUint(c.Param("ingredientId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Ingredient not found",
+		})
+		return
+	}
+
+	if err := ctrl.service.RemoveIngredient(id, uint(ingredientID)); err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Supplier ingredient deleted successfully",
+	})
+}
+
+// supplierID parses the id path parameter, responding 404 when it is invalid
+func supplierID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Supplier not found",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func respondSupplierError(c *gin.Context, err error) {
+	status := serviceErrorStatus(err)
+	message := err.Error()
+	if status == http.StatusNotFound {
+		message = "Supplier not found"
+	}
+
+	c.JSON(status, gin.H{
+		"status":  "error",
+		"message": message,
+	})
+}
+
+func toSupplierDTO(supplier models.Supplier) dto.Supplier {
+	supplierDTO := dto.Supplier{
+		ID:           supplier.ID,
+		Name:         supplier.Name,
+		ContactName:  supplier.ContactName,
+		Phone:        supplier.Phone,
+		Email:        supplier.Email,
+		Address:      supplier.Address,
+		LeadTimeDays: supplier.LeadTimeDays,
+		PaymentTerms: supplier.PaymentTerms,
+		Notes:        supplier.Notes,
+		CreatedAt:    supplier.CreatedAt,
+		UpdatedAt:    supplier.UpdatedAt,
+	}
+
+	for _, link := range supplier.Ingredients {
+		// Ingredient yang sudah dihapus tidak ditampilkan
+		if link.Ingredient.ID == 0 {
+			continue
+		}
+
+		link.Supplier = supplier
+		supplierDTO.Ingredients = append(supplierDTO.Ingredients,
+			toSupplierIngredientDTO(services.NewSupplierOffer(link, link.Ingredient)))
+	}
+
+	return supplierDTO
+}
+
+func toSupplierIngredientDTO(offer services.SupplierOffer) dto.SupplierIngredient {
+	return dto.SupplierIngredient{
+		ID: offer.ID,
+		Supplier: dto.SupplierSummary{
+			ID:           offer.Supplier.ID,
+			Name:         offer.Supplier.Name,
+			LeadTimeDays: offer.Supplier.LeadTimeDays,
+			PaymentTerms: offer.Supplier.PaymentTerms,
+		},
+		Ingredient: dto.StockReductionIngredient{
+			ID:   offer.Ingredient.ID,
+			Name: offer.Ingredient.Name,
+			Slug: offer.Ingredient.Slug,
+		},
+		SKU:      offer.SKU,
+		PackSize: offer.PackSize,
+		PackUnit: dto.StockReductionUnit{
+			ID:   offer.PackUnit.ID,
+			Name: offer.PackUnit.Name,
+		},
+		LastPrice:   offer.LastPrice,
+		UnitPrice:   offer.UnitPrice,
+		LastPriceAt: offer.LastPriceAt,
+	}
+}
@@ -1,33 +1,101 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
-	"AwisPalace_IngredientManagement/config"
 	"AwisPalace_IngredientManagement/dto"
 	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+	"AwisPalace_IngredientManagement/services"
+	"AwisPalace_IngredientManagement/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
+var transactionSorts = utils.SortFields{
+	"id":               "id",
+	"transaction_date": "transaction_date",
+	"total_amount":     "total_amount",
+	"created_at":       "created_at",
+}
+
+// TransactionController serves the transaction endpoints backed by a TransactionService
+type TransactionController struct {
+	service *services.TransactionService
+}
+
+func NewTransactionController(service *services.TransactionService) *TransactionController {
+	return &TransactionController{service: service}
+}
+
 // GetTransactions godoc
 // @Summary Get Transactions
-// @Description Get Transactions with optional date filter (default: this week)
+// @Description Get Transactions with optional date filter (default: this week), pagination, sorting and filters
 // @Tags Transactions
 // @Param start_date query string false "Start date (YYYY-MM-DD)"
 // @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param page query int false "Page (default 1)"
+// @Param limit query int false "Items per page (default 20, max 100)"
+// @Param cursor query int false "ID of the last item of the previous page (sort by id only)"
+// @Param sort query string false "id, transaction_date, total_amount, created_at; prefix with - for descending (default -id)"
+// @Param status query string false "Status (completed, cancelled, partially_refunded, refunded)"
+// @Param menu_id query int false "Only transactions containing this menu"
+// @Param min_total query number false "Minimum total amount"
+// @Param max_total query number false "Maximum total amount"
+// @Security BearerAuth
 // @Router /transactions [get]
-func GetTransactions(c *gin.Context) {
-	var transactions []models.Transaction
+func (ctrl *TransactionController) GetTransactions(c *gin.Context) {
+	params, err := utils.ParseListParams(c.Request.URL.Query(), transactionSorts, "-id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	filter, err := parseTransactionFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	transactions, meta, err := ctrl.service.List(filter, params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	response := make([]dto.Transaction, 0, len(transactions))
+	for _, transaction := range transactions {
+		response = append(response, toTransactionDTO(transaction))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response,
+		"meta":   meta,
+	})
+}
+
+// parseTransactionFilter reads the date range (default: this week) and the
+// other filters of GetTransactions
+func parseTransactionFilter(c *gin.Context) (repositories.TransactionFilter, error) {
+	var filter repositories.TransactionFilter
 
 	startDateStr := c.Query("start_date")
 	endDateStr := c.Query("end_date")
 
-	var startDate, endDate time.Time
-	var err error
-
 	if startDateStr == "" || endDateStr == "" {
 		now := time.Now()
 		weekday := int(now.Weekday())
@@ -35,7 +103,7 @@ func GetTransactions(c *gin.Context) {
 			weekday = 7
 		}
 
-		startDate = time.Date(
+		filter.StartDate = time.Date(
 			now.Year(),
 			now.Month(),
 			now.Day()-weekday+1,
@@ -43,107 +111,46 @@ func GetTransactions(c *gin.Context) {
 			now.Location(),
 		)
 
-		endDate = startDate.AddDate(0, 0, 7).Add(-time.Nanosecond)
+		filter.EndDate = filter.StartDate.AddDate(0, 0, 7).Add(-time.Nanosecond)
 	} else {
-		startDate, err = time.Parse("2006-01-02", startDateStr)
+		var err error
+		filter.StartDate, err = time.Parse("2006-01-02", startDateStr)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Invalid start_date format (YYYY-MM-DD)",
-			})
-			return
+			return filter, errors.New("Invalid start_date format (YYYY-MM-DD)")
 		}
 
-		endDate, err = time.Parse("2006-01-02", endDateStr)
+		filter.EndDate, err = time.Parse("2006-01-02", endDateStr)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  "error",
-				"message": "Invalid end_date format (YYYY-MM-DD)",
-			})
-			return
+			return filter, errors.New("Invalid end_date format (YYYY-MM-DD)")
 		}
 
-		endDate = endDate.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
-	}
-
-	//
-	if err := config.DB.
-		Where("transaction_date BETWEEN ? AND ?", startDate, endDate).
-		Preload("TransactionItems").
-		Preload("TransactionItems.Menu").
-		Preload("TransactionItems.StockReductions").
-		Preload("TransactionItems.StockReductions.Ingredient").
-		Preload("TransactionItems.StockReductions.Unit").
-		Order("created_at DESC").
-		Find(&transactions).Error; err != nil {
-
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": err.Error(),
-		})
-		return
+		filter.EndDate = filter.EndDate.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
 	}
 
-	//
-	var response []dto.Transaction
+	filter.Status = c.Query("status")
 
-	for _, transaction := range transactions {
-		transactionDTO := dto.Transaction{
-			ID:              transaction.ID,
-			TransactionCode: transaction.TransactionCode,
-			TransactionDate: transaction.TransactionDate,
-			TotalAmount:     transaction.TotalAmount,
-			Notes:           transaction.Notes,
-			Status:          transaction.Status,
-			CreatedAt:       transaction.CreatedAt,
-			UpdatedAt:       transaction.UpdatedAt,
+	if menuID := c.Query("menu_id"); menuID != "" {
+		id, err := strconv.ParseUint(menuID, 10, 64)
+		if err != nil {
+			return filter, errors.New("menu_id must be a number")
 		}
+		filter.MenuID = uint(id)
+	}
 
-		for _, item := range transaction.TransactionItems {
-			itemDTO := dto.TransactionItem{
-				ID:       item.ID,
-				Quantity: item.Quantity,
-				Price:    item.Price,
-				Menu: dto.TransactionItemMenu{
-					ID:    item.Menu.ID,
-					Name:  item.Menu.Name,
-					Slug:  item.Menu.Slug,
-					Image: item.Menu.Image,
-				},
-			}
-
-			for _, reduction := range item.StockReductions {
-				itemDTO.StockReductions = append(itemDTO.StockReductions, dto.StockReduction{
-					ID:              reduction.ID,
-					QuantityReduced: reduction.QuantityReduced,
-					StockBefore:     reduction.StockBefore,
-					StockAfter:      reduction.StockAfter,
-					Ingredient: dto.StockReductionIngredient{
-						ID:   reduction.Ingredient.ID,
-						Name: reduction.Ingredient.Name,
-						Slug: reduction.Ingredient.Slug,
-					},
-					Unit: dto.StockReductionUnit{
-						ID:   reduction.Unit.ID,
-						Name: reduction.Unit.Name,
-					},
-				})
+	for param, target := range map[string]**float64{
+		"min_total": &filter.MinTotal,
+		"max_total": &filter.MaxTotal,
+	} {
+		if value := c.Query(param); value != "" {
+			amount, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return filter, fmt.Errorf("%s must be a number", param)
 			}
-
-			transactionDTO.Items = append(transactionDTO.Items, itemDTO)
+			*target = &amount
 		}
-
-		response = append(response, transactionDTO)
 	}
 
-	if response == nil {
-		response = make([]dto.Transaction, 0)
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"status": "success",
-		"data":   response,
-	})
+	return filter, nil
 }
 
 // GetTransaction godoc
@@ -151,74 +158,23 @@ func GetTransactions(c *gin.Context) {
 // @Description Get Transaction by ID
 // @Tags Transactions
 // @Param id path int true "Transaction ID"
+// @Security BearerAuth
 // @Router /transactions/{id} [get]
-func GetTransaction(c *gin.Context) {
-	id := c.Param("id")
-	var transaction models.Transaction
-
-	if err := config.DB.
-		Preload("TransactionItems").
-		Preload("TransactionItems.Menu").
-		Preload("TransactionItems.StockReductions").
-		Preload("TransactionItems.StockReductions.Ingredient").
-		Preload("TransactionItems.StockReductions.Unit").
-		First(&transaction, id).Error; err != nil {
-
-		c.JSON(http.StatusNotFound, gin.H{
-			"status":  "error",
-			"message": "Transaction not found",
-		})
+func (ctrl *TransactionController) GetTransaction(c *gin.Context) {
+	id, ok := transactionIDParam(c)
+	if !ok {
 		return
 	}
 
-	transactionDTO := dto.Transaction{
-		ID:              transaction.ID,
-		TransactionCode: transaction.TransactionCode,
-		TransactionDate: transaction.TransactionDate,
-		TotalAmount:     transaction.TotalAmount,
-		Notes:           transaction.Notes,
-		Status:          transaction.Status,
-		CreatedAt:       transaction.CreatedAt,
-		UpdatedAt:       transaction.UpdatedAt,
-	}
-
-	for _, item := range transaction.TransactionItems {
-		itemDTO := dto.TransactionItem{
-			ID:       item.ID,
-			Quantity: item.Quantity,
-			Price:    item.Price,
-			Menu: dto.TransactionItemMenu{
-				ID:    item.Menu.ID,
-				Name:  item.Menu.Name,
-				Slug:  item.Menu.Slug,
-				Image: item.Menu.Image,
-			},
-		}
-
-		for _, reduction := range item.StockReductions {
-			itemDTO.StockReductions = append(itemDTO.StockReductions, dto.StockReduction{
-				ID:              reduction.ID,
-				QuantityReduced: reduction.QuantityReduced,
-				StockBefore:     reduction.StockBefore,
-				StockAfter:      reduction.StockAfter,
-				Ingredient: dto.StockReductionIngredient{
-					ID:   reduction.Ingredient.ID,
-					Name: reduction.Ingredient.Name,
-					Slug: reduction.Ingredient.Slug,
-				},
-				Unit: dto.StockReductionUnit{
-					ID:   reduction.Unit.ID,
-					Name: reduction.Unit.Name,
-				},
-			})
-		}
-
-		transactionDTO.Items = append(transactionDTO.Items, itemDTO)
+	transaction, err := ctrl.service.Get(id)
+	if err != nil {
+		respondTransactionError(c, err)
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"status": "success",
-		"data":   transactionDTO,
+		"data":   toTransactionDTO(transaction),
 	})
 }
 
@@ -227,8 +183,10 @@ func GetTransaction(c *gin.Context) {
 // @Description Create Transaction
 // @Tags Transactions
 // @Param transaction body dto.TransactionCreateRequest true "Create transaction"
+// @Param Idempotency-Key header string false "Unique key per order, retries with the same key return the original response"
+// @Security BearerAuth
 // @Router /transactions [post]
-func PostTransaction(c *gin.Context) {
+func (ctrl *TransactionController) PostTransaction(c *gin.Context) {
 	var input dto.TransactionCreateRequest
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -239,235 +197,336 @@ func PostTransaction(c *gin.Context) {
 		return
 	}
 
-	tx := config.DB.Begin()
-
-	// Generate transaction code
-	transactionCode := fmt.Sprintf("TRX-%s-%d",
-		time.Now().Format("20060102"),
-		time.Now().Unix()%10000,
-	)
-
-	transaction := models.Transaction{
-		TransactionCode: transactionCode,
-		TransactionDate: time.Now(),
-		TotalAmount:     0,
-		Notes:           input.Notes,
-		Status:          "completed",
-	}
-
-	if err := tx.Create(&transaction).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
+	transaction, err := ctrl.service.Create(input, currentUserID(c))
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{
 			"status":  "error",
 			"message": err.Error(),
 		})
 		return
 	}
 
-	var totalAmount float64
-
-	for _, item := range input.Items {
-		// Get menu data
-		var menu models.Menu
-		if err := tx.Preload("MenuIngredients").
-			Preload("MenuIngredients.Unit").
-			First(&menu, item.MenuID).Error; err != nil {
-
-			tx.Rollback()
-			c.JSON(http.StatusNotFound, gin.H{
-				"status":  "error",
-				"message": fmt.Sprintf("Menu with ID %d not found", item.MenuID),
-			})
-			return
-		}
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Transaction created successfully",
+		"data": gin.H{
+			"transaction_code": transaction.TransactionCode,
+			"total_amount":     transaction.TotalAmount,
+		},
+	})
+}
 
-		// Create transaction item
-		transactionItem := models.TransactionItem{
-			TransactionID: transaction.ID,
-			MenuID:        menu.ID,
-			Quantity:      item.Quantity,
-			Price:         menu.Price,
-		}
+// PreviewTransaction godoc
+// @Summary Preview Transaction
+// @Description Check an order before checkout: total, ingredients needed across all items and the ones short in stock. Nothing is saved.
+// @Tags Transactions
+// @Param transaction body dto.TransactionCreateRequest true "Order to check"
+// @Security BearerAuth
+// @Success 200 {object} dto.TransactionPreview
+// @Router /transactions/preview [post]
+func (ctrl *TransactionController) PreviewTransaction(c *gin.Context) {
+	var input dto.TransactionCreateRequest
 
-		if err := tx.Create(&transactionItem).Error; err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"status":  "error",
-				"message": err.Error(),
-			})
-			return
-		}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
 
-		totalAmount += menu.Price * float64(item.Quantity)
-
-		// Process stock reduction for each ingredient
-		for _, menuIngredient := range menu.MenuIngredients {
-			// Get current ingredient stock
-			var ingredient models.Ingredient
-			if err := tx.First(&ingredient, menuIngredient.IngredientID).Error; err != nil {
-				tx.Rollback()
-				c.JSON(http.StatusNotFound, gin.H{
-					"status":  "error",
-					"message": fmt.Sprintf("Ingredient with ID %d not found", menuIngredient.IngredientID),
-				})
-				return
-			}
+	preview, err := ctrl.service.Preview(input)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
 
-			// Calculate quantity to reduce
-			quantityToReduce := menuIngredient.Quantity * float64(item.Quantity)
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   toTransactionPreviewDTO(preview),
+	})
+}
 
-			// Check if stock is sufficient
-			if ingredient.Stock < quantityToReduce {
-				tx.Rollback()
-				c.JSON(http.StatusBadRequest, gin.H{
-					"status":  "error",
-					"message": fmt.Sprintf("Insufficient stock for ingredient: %s. Available: %.2f, Required: %.2f", ingredient.Name, ingredient.Stock, quantityToReduce),
-				})
-				return
-			}
+// DeleteTransaction godoc
+// @Summary Delete Transaction
+// @Description Delete a completed Transaction by ID (and restore stock). Prefer cancel or refund to keep the record.
+// @Tags Transactions
+// @Param id path int true "Transaction ID"
+// @Security BearerAuth
+// @Router /transactions/{id} [delete]
+func (ctrl *TransactionController) DeleteTransaction(c *gin.Context) {
+	id, ok := transactionIDParam(c)
+	if !ok {
+		return
+	}
 
-			stockBefore := ingredient.Stock
-			stockAfter := stockBefore - quantityToReduce
-
-			// Create stock reduction record
-			stockReduction := models.StockReduction{
-				TransactionItemID: transactionItem.ID,
-				IngredientID:      ingredient.ID,
-				QuantityReduced:   quantityToReduce,
-				StockBefore:       stockBefore,
-				StockAfter:        stockAfter,
-				UnitID:            menuIngredient.UnitID,
-			}
+	if _, err := ctrl.service.Delete(id, currentUserID(c)); err != nil {
+		respondTransactionError(c, err)
+		return
+	}
 
-			if err := tx.Create(&stockReduction).Error; err != nil {
-				tx.Rollback()
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"status":  "error",
-					"message": err.Error(),
-				})
-				return
-			}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Transaction deleted successfully and stock restored",
+	})
+}
 
-			// Update ingredient stock
-			ingredient.Stock = stockAfter
-			if err := tx.Save(&ingredient).Error; err != nil {
-				tx.Rollback()
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"status":  "error",
-					"message": err.Error(),
-				})
-				return
-			}
-		}
+// CancelTransaction godoc
+// @Summary Cancel Transaction
+// @Description Void a completed Transaction, keep its record and restore all ingredient stock
+// @Tags Transactions
+// @Param id path int true "Transaction ID"
+// @Param cancel body dto.TransactionCancelRequest true "Cancel transaction"
+// @Security BearerAuth
+// @Router /transactions/{id}/cancel [post]
+func (ctrl *TransactionController) CancelTransaction(c *gin.Context) {
+	id, ok := transactionIDParam(c)
+	if !ok {
+		return
 	}
 
-	// Update total amount
-	transaction.TotalAmount = totalAmount
-	if err := tx.Save(&transaction).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
+	var input dto.TransactionCancelRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
 			"message": err.Error(),
 		})
 		return
 	}
 
-	tx.Commit()
+	transaction, err := ctrl.service.Cancel(id, input.Reason, currentUserID(c))
+	if err != nil {
+		respondTransactionError(c, err)
+		return
+	}
 
-	c.JSON(http.StatusCreated, gin.H{
+	c.JSON(http.StatusOK, gin.H{
 		"status":  "success",
-		"message": "Transaction created successfully",
-		"data": gin.H{
-			"transaction_code": transactionCode,
-			"total_amount":     totalAmount,
-		},
+		"message": "Transaction cancelled successfully and stock restored",
+		"data":    toTransactionDTO(transaction),
 	})
 }
 
-// DeleteTransaction godoc
-// @Summary Delete Transaction
-// @Description Delete Transaction by ID (and restore stock)
+// RefundTransaction godoc
+// @Summary Refund Transaction
+// @Description Refund selected items or quantities of a Transaction. Stock is only restored for items marked restock (not prepared yet).
 // @Tags Transactions
 // @Param id path int true "Transaction ID"
-// @Router /transactions/{id} [delete]
-func DeleteTransaction(c *gin.Context) {
-	id := c.Param("id")
+// @Param refund body dto.TransactionRefundRequest true "Refund transaction"
+// @Security BearerAuth
+// @Router /transactions/{id}/refund [post]
+func (ctrl *TransactionController) RefundTransaction(c *gin.Context) {
+	id, ok := transactionIDParam(c)
+	if !ok {
+		return
+	}
 
-	tx := config.DB.Begin()
+	var input dto.TransactionRefundRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
 
-	var transaction models.Transaction
-	if err := tx.Preload("TransactionItems").
-		Preload("TransactionItems.StockReductions").
-		First(&transaction, id).Error; err != nil {
+	transaction, err := ctrl.service.Refund(id, input, currentUserID(c))
+	if err != nil {
+		respondTransactionError(c, err)
+		return
+	}
 
-		tx.Rollback()
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Transaction refunded successfully",
+		"data":    toTransactionDTO(transaction),
+	})
+}
+
+func transactionIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"status":  "error",
 			"message": "Transaction not found",
 		})
-		return
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func respondTransactionError(c *gin.Context, err error) {
+	message := err.Error()
+	if errors.Is(err, services.ErrNotFound) {
+		message = "Transaction not found"
+	}
+
+	c.JSON(serviceErrorStatus(err), gin.H{
+		"status":  "error",
+		"message": message,
+	})
+}
+
+func toTransactionDTO(transaction models.Transaction) dto.Transaction {
+	transactionDTO := dto.Transaction{
+		ID:              transaction.ID,
+		TransactionCode: transaction.TransactionCode,
+		TransactionDate: transaction.TransactionDate,
+		TotalAmount:     transaction.TotalAmount,
+		RefundedAmount:  transaction.RefundedAmount,
+		Notes:           transaction.Notes,
+		Status:          transaction.Status,
+		CancelReason:    transaction.CancelReason,
+		CancelledAt:     transaction.CancelledAt,
+		Refunds:         make([]dto.Refund, 0, len(transaction.Refunds)),
+		CreatedAt:       transaction.CreatedAt,
+		UpdatedAt:       transaction.UpdatedAt,
 	}
 
-	// Restore stock for each ingredient
 	for _, item := range transaction.TransactionItems {
+		itemDTO := dto.TransactionItem{
+			ID:               item.ID,
+			Quantity:         item.Quantity,
+			Price:            item.Price,
+			Cost:             item.Cost,
+			RefundedQuantity: item.RefundedQuantity,
+			Menu: dto.TransactionItemMenu{
+				ID:    item.Menu.ID,
+				Name:  item.Menu.Name,
+				Slug:  item.Menu.Slug,
+				Image: item.Menu.Image,
+			},
+		}
+
+		for _, modifier := range item.Modifiers {
+			itemDTO.Modifiers = append(itemDTO.Modifiers, toTransactionItemModifierDTO(modifier))
+		}
+
 		for _, reduction := range item.StockReductions {
-			var ingredient models.Ingredient
-			if err := tx.First(&ingredient, reduction.IngredientID).Error; err != nil {
-				tx.Rollback()
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"status":  "error",
-					"message": err.Error(),
-				})
-				return
+			reductionDTO := dto.StockReduction{
+				ID:              reduction.ID,
+				QuantityReduced: reduction.QuantityReduced,
+				StockBefore:     reduction.StockBefore,
+				StockAfter:      reduction.StockAfter,
+				Ingredient: dto.StockReductionIngredient{
+					ID:   reduction.Ingredient.ID,
+					Name: reduction.Ingredient.Name,
+					Slug: reduction.Ingredient.Slug,
+				},
+				Unit: dto.StockReductionUnit{
+					ID:   reduction.Unit.ID,
+					Name: reduction.Unit.Name,
+				},
+				Lots: make([]dto.StockReductionLot, 0, len(reduction.Lots)),
 			}
 
-			// Restore stock
-			ingredient.Stock += reduction.QuantityReduced
-			if err := tx.Save(&ingredient).Error; err != nil {
-				tx.Rollback()
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"status":  "error",
-					"message": err.Error(),
+			for _, lot := range reduction.Lots {
+				reductionDTO.Lots = append(reductionDTO.Lots, dto.StockReductionLot{
+					StockLotID: lot.StockLotID,
+					LotNumber:  lot.StockLot.LotNumber,
+					ExpiryDate: lot.StockLot.ExpiryDate,
+					Quantity:   lot.Quantity,
 				})
-				return
 			}
 
-			// Delete stock reduction record
-			if err := tx.Delete(&reduction).Error; err != nil {
-				tx.Rollback()
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"status":  "error",
-					"message": err.Error(),
-				})
-				return
-			}
+			itemDTO.StockReductions = append(itemDTO.StockReductions, reductionDTO)
+		}
+
+		transactionDTO.Items = append(transactionDTO.Items, itemDTO)
+	}
+
+	for _, refund := range transaction.Refunds {
+		refundDTO := dto.Refund{
+			ID:          refund.ID,
+			Reason:      refund.Reason,
+			TotalAmount: refund.TotalAmount,
+			UserID:      refund.UserID,
+			Items:       make([]dto.RefundItem, 0, len(refund.Items)),
+			CreatedAt:   refund.CreatedAt,
 		}
 
-		// Delete transaction item
-		if err := tx.Delete(&item).Error; err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"status":  "error",
-				"message": err.Error(),
+		for _, item := range refund.Items {
+			refundDTO.Items = append(refundDTO.Items, dto.RefundItem{
+				ID:                item.ID,
+				TransactionItemID: item.TransactionItemID,
+				Quantity:          item.Quantity,
+				Amount:            item.Amount,
+				Restocked:         item.Restocked,
 			})
-			return
 		}
+
+		transactionDTO.Refunds = append(transactionDTO.Refunds, refundDTO)
 	}
 
-	// Delete transaction
-	if err := tx.Delete(&transaction).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": err.Error(),
-		})
-		return
+	return transactionDTO
+}
+
+func toTransactionItemModifierDTO(modifier models.TransactionItemModifier) dto.TransactionItemModifier {
+	return dto.TransactionItemModifier{
+		ID:               modifier.ID,
+		ModifierOptionID: modifier.ModifierOptionID,
+		GroupName:        modifier.GroupName,
+		OptionName:       modifier.OptionName,
+		PriceDelta:       modifier.PriceDelta,
 	}
+}
 
-	tx.Commit()
+func toTransactionPreviewDTO(preview services.TransactionPreview) dto.TransactionPreview {
+	previewDTO := dto.TransactionPreview{
+		TotalAmount:  preview.TotalAmount,
+		Cost:         preview.Cost,
+		Feasible:     preview.Feasible,
+		Items:        make([]dto.TransactionPreviewItem, 0, len(preview.Items)),
+		Requirements: make([]dto.TransactionPreviewRequirement, 0, len(preview.Requirements)),
+		Shortages:    []dto.TransactionPreviewRequirement{},
+	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "success",
-		"message": "Transaction deleted successfully and stock restored",
-	})
+	for _, item := range preview.Items {
+		itemDTO := dto.TransactionPreviewItem{
+			Menu: dto.TransactionItemMenu{
+				ID:    item.Menu.ID,
+				Name:  item.Menu.Name,
+				Slug:  item.Menu.Slug,
+				Image: item.Menu.Image,
+			},
+			Quantity:  item.Quantity,
+			Price:     item.Price,
+			Subtotal:  item.Subtotal,
+			SoldOut:   item.Menu.SoldOut,
+			Modifiers: make([]dto.TransactionItemModifier, 0, len(item.Modifiers)),
+		}
+
+		for _, modifier := range item.Modifiers {
+			itemDTO.Modifiers = append(itemDTO.Modifiers, toTransactionItemModifierDTO(modifier))
+		}
+
+		previewDTO.Items = append(previewDTO.Items, itemDTO)
+	}
+
+	for _, requirement := range preview.Requirements {
+		requirementDTO := dto.TransactionPreviewRequirement{
+			Ingredient: dto.StockReductionIngredient{
+				ID:   requirement.Ingredient.ID,
+				Name: requirement.Ingredient.Name,
+				Slug: requirement.Ingredient.Slug,
+			},
+			Unit: dto.StockReductionUnit{
+				ID:   requirement.Ingredient.Unit.ID,
+				Name: requirement.Ingredient.Unit.Name,
+			},
+			Required:  requirement.Required,
+			Available: requirement.Ingredient.Stock,
+			Shortage:  requirement.Shortage,
+		}
+
+		previewDTO.Requirements = append(previewDTO.Requirements, requirementDTO)
+		if requirement.Shortage > 0 {
+			previewDTO.Shortages = append(previewDTO.Shortages, requirementDTO)
+		}
+	}
+
+	return previewDTO
 }
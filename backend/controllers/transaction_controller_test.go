@@ -0,0 +1,207 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"AwisPalace_IngredientManagement/config"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+	"AwisPalace_IngredientManagement/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupTransactionTestDB points config.DB at a fresh SQLite database with one
+// ingredient in stock and a menu using one unit of it per portion
+func setupTransactionTestDB(t *testing.T, stock float64) (models.Ingredient, models.Menu) {
+	t.Helper()
+
+	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(10000)&_txlock=immediate", filepath.Join(t.TempDir(), "test.db"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+
+	if err := db.AutoMigrate(
+		&models.User{},
+		&models.Unit{},
+		&models.Ingredient{},
+		&models.IngredientUnitConversion{},
+		&models.Menu{},
+		&models.MenuIngredient{},
+		&models.Transaction{},
+		&models.TransactionItem{},
+		&models.StockReduction{},
+		&models.StockMovement{},
+		&models.StockAlert{},
+		&models.DocumentSequence{},
+		&models.StockLot{},
+		&models.StockReductionLot{},
+		&models.MenuModifierGroup{},
+		&models.MenuModifierOption{},
+		&models.MenuModifierIngredient{},
+		&models.TransactionItemModifier{},
+	); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	previous := config.DB
+	config.DB = db
+	t.Cleanup(func() { config.DB = previous })
+
+	unit := models.Unit{Name: "Gram", Symbol: "g", Dimension: models.DimensionMass, Factor: 1}
+	ingredient := models.Ingredient{Name: "Beras", Slug: "beras", Stock: stock}
+	menu := models.Menu{Name: "Nasi", Slug: "nasi", Price: 5000}
+
+	if err := db.Create(&unit).Error; err != nil {
+		t.Fatalf("create unit: %v", err)
+	}
+	ingredient.UnitID = unit.ID
+	if err := db.Create(&ingredient).Error; err != nil {
+		t.Fatalf("create ingredient: %v", err)
+	}
+	if err := db.Create(&menu).Error; err != nil {
+		t.Fatalf("create menu: %v", err)
+	}
+	if err := db.Create(&models.MenuIngredient{MenuID: menu.ID, IngredientID: ingredient.ID, Quantity: 1, UnitID: unit.ID}).Error; err != nil {
+		t.Fatalf("create menu ingredient: %v", err)
+	}
+
+	return ingredient, menu
+}
+
+// newTestTransactionController builds the controller on top of config.DB with
+// the default transaction code format
+func newTestTransactionController(t *testing.T) *TransactionController {
+	t.Helper()
+
+	codes, err := services.NewTransactionCodeGenerator("", "")
+	if err != nil {
+		t.Fatalf("code generator: %v", err)
+	}
+
+	return NewTransactionController(services.NewTransactionService(repositories.NewGormStore(config.DB), codes))
+}
+
+// SQLite dengan _txlock=immediate menjalankan transaksi satu per satu, jadi
+// test ini memastikan order yang masuk bersamaan tidak menjual melebihi stok
+// ketika dieksekusi berurutan. Percobaan ulang setelah deadlock diuji dengan
+// error yang disuntikkan di TestPostIngredientMovementRetriesDeadlock
+func TestPostTransactionSerializedOrdersDoNotOversell(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const stock = 10
+	const orders = 25
+
+	ingredient, menu := setupTransactionTestDB(t, stock)
+
+	router := gin.New()
+	router.POST("/transactions", newTestTransactionController(t).PostTransaction)
+
+	body, _ := json.Marshal(gin.H{
+		"items": []gin.H{{"menu_id": menu.ID, "quantity": 1}},
+	})
+
+	var wg sync.WaitGroup
+	codes := make(chan int, orders)
+	for i := 0; i < orders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			codes <- w.Code
+		}()
+	}
+	wg.Wait()
+	close(codes)
+
+	created, rejected := 0, 0
+	for code := range codes {
+		switch code {
+		case http.StatusCreated:
+			created++
+		case http.StatusBadRequest:
+			rejected++
+		}
+	}
+
+	if created != stock || rejected != orders-stock {
+		t.Fatalf("created %d and rejected %d transactions, want %d and %d", created, rejected, stock, orders-stock)
+	}
+
+	var current models.Ingredient
+	if err := config.DB.First(&current, ingredient.ID).Error; err != nil {
+		t.Fatalf("load ingredient: %v", err)
+	}
+
+	if current.Stock < 0 {
+		t.Fatalf("stock went negative: %.2f", current.Stock)
+	}
+	if want := float64(stock - created); current.Stock != want {
+		t.Fatalf("stock = %.2f after %d sales, want %.2f", current.Stock, created, want)
+	}
+
+	var ledgerStock float64
+	config.DB.Model(&models.StockMovement{}).
+		Where("ingredient_id = ?", ingredient.ID).
+		Select("COALESCE(SUM(quantity), 0)").
+		Scan(&ledgerStock)
+	if want := float64(-created); ledgerStock != want {
+		t.Fatalf("ledger sum = %.2f, want %.2f", ledgerStock, want)
+	}
+}
+
+func TestPostTransactionGeneratesSequentialCodes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	_, menu := setupTransactionTestDB(t, 100)
+
+	router := gin.New()
+	router.POST("/transactions", newTestTransactionController(t).PostTransaction)
+
+	body, _ := json.Marshal(gin.H{
+		"items": []gin.H{{"menu_id": menu.ID, "quantity": 1}},
+	})
+
+	const orders = 10
+
+	var wg sync.WaitGroup
+	for i := 0; i < orders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(httptest.NewRecorder(), req)
+		}()
+	}
+	wg.Wait()
+
+	var codes []string
+	config.DB.Model(&models.Transaction{}).Order("transaction_code").Pluck("transaction_code", &codes)
+
+	if len(codes) != orders {
+		t.Fatalf("got %d transactions, want %d", len(codes), orders)
+	}
+
+	prefix := "TRX-" + time.Now().Format("20060102") + "-"
+	for i, code := range codes {
+		if want := fmt.Sprintf("%s%04d", prefix, i+1); code != want {
+			t.Fatalf("code %d = %s, want %s", i, code, want)
+		}
+	}
+}
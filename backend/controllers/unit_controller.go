@@ -2,24 +2,60 @@ package controllers
 
 import (
 	"net/http"
+	"strconv"
 
-	"AwisPalace_IngredientManagement/config"
 	"AwisPalace_IngredientManagement/dto"
-	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+	"AwisPalace_IngredientManagement/services"
+	"AwisPalace_IngredientManagement/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jinzhu/copier"
 )
 
+var unitSorts = utils.SortFields{
+	"id":         "id",
+	"name":       "name",
+	"dimension":  "dimension",
+	"created_at": "created_at",
+}
+
+// UnitController serves the unit endpoints
+type UnitController struct {
+	service *services.UnitService
+}
+
+func NewUnitController(service *services.UnitService) *UnitController {
+	return &UnitController{service: service}
+}
+
 // GetUnits godoc
 // @Summary Get Units
-// @Description Get Units
+// @Description Get Units with pagination, sorting and filters
 // @Tags Units
+// @Param page query int false "Page (default 1)"
+// @Param limit query int false "Items per page (default 20, max 100)"
+// @Param cursor query int false "ID of the last item of the previous page (sort by id only)"
+// @Param sort query string false "id, name, dimension, created_at; prefix with - for descending"
+// @Param search query string false "Search by name or symbol"
+// @Param dimension query string false "Dimension (mass, volume, count, length)"
+// @Security BearerAuth
 // @Router /units [get]
-func GetUnits(c *gin.Context) {
-	var units []models.Unit
+func (ctrl *UnitController) GetUnits(c *gin.Context) {
+	params, err := utils.ParseListParams(c.Request.URL.Query(), unitSorts, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
 
-	if err := config.DB.Find(&units).Error; err != nil {
+	units, meta, err := ctrl.service.List(repositories.UnitFilter{
+		Search:    c.Query("search"),
+		Dimension: c.Query("dimension"),
+	}, params)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -31,6 +67,7 @@ func GetUnits(c *gin.Context) {
 		"status":  "success",
 		"message": "Get Data Unit Succcess",
 		"data":    result,
+		"meta":    meta,
 	})
 }
 
@@ -39,8 +76,9 @@ func GetUnits(c *gin.Context) {
 // @Description Post Units
 // @Tags Units
 // @Param unit body dto.UnitParamRequest true "Unit data"
+// @Security BearerAuth
 // @Router /units [post]
-func PostUnits(c *gin.Context) {
+func (ctrl *UnitController) PostUnits(c *gin.Context) {
 	var input dto.UnitParamRequest
 
 	// Bind JSON ke struct
@@ -53,15 +91,9 @@ func PostUnits(c *gin.Context) {
 		return
 	}
 
-	// Mapping DTO ke model database
-	unit := models.Unit{
-		Name:   input.Name,
-		Symbol: input.Symbol,
-	}
-
-	// Simpan ke database
-	if err := config.DB.Create(&unit).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+	unit, err := ctrl.service.Create(input)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{
 			"status":  "error",
 			"message": "Failed to create unit",
 			"error":   err.Error(),
@@ -86,9 +118,13 @@ func PostUnits(c *gin.Context) {
 // @Tags Units
 // @Param id path int true "Unit ID"
 // @Param unit body dto.UnitParamRequest true "Updated unit data"
+// @Security BearerAuth
 // @Router /units/{id} [put]
-func UpdateUnit(c *gin.Context) {
-	id := c.Param("id")
+func (ctrl *UnitController) UpdateUnit(c *gin.Context) {
+	id, ok := unitIDParam(c)
+	if !ok {
+		return
+	}
 
 	var input dto.UnitParamRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -100,24 +136,9 @@ func UpdateUnit(c *gin.Context) {
 		return
 	}
 
-	var unit models.Unit
-	if err := config.DB.First(&unit, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"status":  "error",
-			"message": "Unit not found",
-		})
-		return
-	}
-
-	unit.Name = input.Name
-	unit.Symbol = input.Symbol
-
-	if err := config.DB.Save(&unit).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to update unit",
-			"error":   err.Error(),
-		})
+	unit, err := ctrl.service.Update(id, input)
+	if err != nil {
+		respondUnitError(c, err)
 		return
 	}
 
@@ -136,34 +157,47 @@ func UpdateUnit(c *gin.Context) {
 // @Description Delete a unit by ID
 // @Tags Units
 // @Param id path string true "Unit ID"
+// @Security BearerAuth
 // @Router /units/{id} [delete]
-func DeleteUnit(c *gin.Context) {
-	id := c.Param("id")
+func (ctrl *UnitController) DeleteUnit(c *gin.Context) {
+	id, ok := unitIDParam(c)
+	if !ok {
+		return
+	}
 
-	var unit models.Unit
+	if err := ctrl.service.Delete(id); err != nil {
+		respondUnitError(c, err)
+		return
+	}
 
-	// Cek apakah unit dengan ID tersebut ada
-	if err := config.DB.First(&unit, "id = ?", id).Error; err != nil {
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Unit deleted successfully",
+	})
+}
+
+// unitIDParam parses the id path parameter, responding 404 when it is invalid
+func unitIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"status":  "error",
 			"message": "Unit not found",
-			"error":   err.Error(),
 		})
-		return
+		return 0, false
 	}
+	return uint(id), true
+}
 
-	// Hapus data unit
-	if err := config.DB.Delete(&unit).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to delete unit",
-			"error":   err.Error(),
-		})
-		return
+func respondUnitError(c *gin.Context, err error) {
+	status := serviceErrorStatus(err)
+	message := err.Error()
+	if status == http.StatusNotFound {
+		message = "Unit not found"
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "success",
-		"message": "Unit deleted successfully",
+	c.JSON(status, gin.H{
+		"status":  "error",
+		"message": message,
 	})
 }
@@ -2,26 +2,137 @@ package controllers
 
 import (
 	"net/http"
+	"strconv"
 
-	"AwisPalace_IngredientManagement/config"
+	"AwisPalace_IngredientManagement/dto"
 	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+	"AwisPalace_IngredientManagement/services"
+	"AwisPalace_IngredientManagement/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
+var userSorts = utils.SortFields{
+	"id":         "id",
+	"name":       "name",
+	"email":      "email",
+	"created_at": "created_at",
+}
+
+// UserController serves the user endpoints backed by a UserService
+type UserController struct {
+	service *services.UserService
+}
+
+func NewUserController(service *services.UserService) *UserController {
+	return &UserController{service: service}
+}
+
 // GetUsers godoc
 // @Summary Get Users
-// @Description Get Users
+// @Description Get Users with pagination, sorting and filters
 // @Tags Users
-// @Success 200 {object} map[string]string
+// @Param page query int false "Page (default 1)"
+// @Param limit query int false "Items per page (default 20, max 100)"
+// @Param cursor query int false "ID of the last item of the previous page (sort by id only)"
+// @Param sort query string false "id, name, email, created_at; prefix with - for descending"
+// @Param search query string false "Search by name or email"
+// @Param role query string false "Role (owner, manager, cashier, kitchen)"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
 // @Router /users [get]
-func GetUsers(c *gin.Context) {
-	var users []models.User
+func (ctrl *UserController) GetUsers(c *gin.Context) {
+	params, err := utils.ParseListParams(c.Request.URL.Query(), userSorts, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
 
-	if err := config.DB.Find(&users).Error; err != nil {
+	users, meta, err := ctrl.service.List(repositories.UserFilter{
+		Search: c.Query("search"),
+		Role:   c.Query("role"),
+	}, params)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, users)
+	if users == nil {
+		users = make([]models.User, 0)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   users,
+		"meta":   meta,
+	})
+}
+
+// UpdateUserRole godoc
+// @Summary Update User Role
+// @Description Assign a role (owner, manager, cashier, kitchen) to a user
+// @Tags Users
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param role body dto.UserRoleUpdateRequest true "Role"
+// @Router /users/{id}/role [put]
+func (ctrl *UserController) UpdateUserRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "User not found",
+		})
+		return
+	}
+
+	var input dto.UserRoleUpdateRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid input data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	user, err := ctrl.service.UpdateRole(uint(id), input.Role)
+	if err != nil {
+		status := serviceErrorStatus(err)
+		switch status {
+		case http.StatusNotFound:
+			c.JSON(status, gin.H{
+				"status":  "error",
+				"message": "User not found",
+			})
+		case http.StatusConflict:
+			c.JSON(status, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		default:
+			c.JSON(status, gin.H{
+				"status":  "error",
+				"message": "Failed to update user role",
+				"error":   err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "User role updated successfully",
+		"data": dto.UserData{
+			ID:       user.ID,
+			Email:    user.Email,
+			Name:     user.Name,
+			PhotoURL: user.PhotoURL,
+			Role:     user.Role,
+		},
+	})
 }
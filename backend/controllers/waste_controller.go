@@ -0,0 +1,215 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+	"AwisPalace_IngredientManagement/services"
+	"AwisPalace_IngredientManagement/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var wasteSorts = utils.SortFields{
+	"id":        "id",
+	"wasted_at": "wasted_at",
+	"reason":    "reason",
+	"quantity":  "quantity_reduced",
+}
+
+// WasteController serves the waste and spoilage endpoints
+type WasteController struct {
+	service *services.WasteService
+}
+
+func NewWasteController(service *services.WasteService) *WasteController {
+	return &WasteController{service: service}
+}
+
+// GetWaste godoc
+// @Summary Get Waste Records
+// @Description Get waste records with pagination, sorting and filters
+// @Tags Waste
+// @Param page query int false "Page (default 1)"
+// @Param limit query int false "Items per page (default 20, max 100)"
+// @Param cursor query int false "ID of the last item of the previous page (sort by id only)"
+// @Param sort query string false "id, wasted_at, reason, quantity; prefix with - for descending"
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param reason query string false "Reason (expired, damaged, staff_meal, comp, other)"
+// @Param ingredient_id query int false "Ingredient ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Router /waste [get]
+func (ctrl *WasteController) GetWaste(c *gin.Context) {
+	params, err := utils.ParseListParams(c.Request.URL.Query(), wasteSorts, "-id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	filter, err := parseWasteFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	records, meta, err := ctrl.service.List(filter, params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]dto.WasteRecord, 0, len(records))
+	for _, record := range records {
+		response = append(response, toWasteRecordDTO(record))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response,
+		"meta":   meta,
+	})
+}
+
+// PostWaste godoc
+// @Summary Record Waste
+// @Description Record spoiled, damaged, staff meal or complimentary ingredients and deduct them from stock
+// @Tags Waste
+// @Param waste body dto.WasteCreateRequest true "Waste"
+// @Param Idempotency-Key header string false "Unique key per record, retries with the same key return the original response"
+// @Security BearerAuth
+// @Router /waste [post]
+func (ctrl *WasteController) PostWaste(c *gin.Context) {
+	var input dto.WasteCreateRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	record, err := ctrl.service.Record(input, currentUserID(c))
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Waste recorded successfully",
+		"data":    toWasteRecordDTO(record),
+	})
+}
+
+// GetWasteReport godoc
+// @Summary Waste Report
+// @Description Waste quantity and value by reason, ingredient and date
+// @Tags Waste
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param reason query string false "Reason (expired, damaged, staff_meal, comp, other)"
+// @Param ingredient_id query int false "Ingredient ID"
+// @Security BearerAuth
+// @Router /waste/report [get]
+func (ctrl *WasteController) GetWasteReport(c *gin.Context) {
+	filter, err := parseWasteFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	report, err := ctrl.service.Report(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   report,
+	})
+}
+
+// parseWasteFilter reads the filters shared by the waste list and report.
+// Dates are optional and inclusive.
+func parseWasteFilter(c *gin.Context) (repositories.WasteFilter, error) {
+	filter := repositories.WasteFilter{Reason: c.Query("reason")}
+
+	if startDate := c.Query("start_date"); startDate != "" {
+		date, err := time.ParseInLocation("2006-01-02", startDate, time.Local)
+		if err != nil {
+			return filter, errors.New("Invalid start_date format (YYYY-MM-DD)")
+		}
+		filter.StartDate = date
+	}
+
+	if endDate := c.Query("end_date"); endDate != "" {
+		date, err := time.ParseInLocation("2006-01-02", endDate, time.Local)
+		if err != nil {
+			return filter, errors.New("Invalid end_date format (YYYY-MM-DD)")
+		}
+		filter.EndDate = date.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
+	}
+
+	if ingredientID := c.Query("ingredient_id"); ingredientID != "" {
+		id, err := strconv.ParseUint(ingredientID, 10, 64)
+		if err != nil {
+			return filter, errors.New("ingredient_id must be a number")
+		}
+		filter.IngredientID = uint(id)
+	}
+
+	return filter, nil
+}
+
+func toWasteRecordDTO(record models.WasteRecord) dto.WasteRecord {
+	return dto.WasteRecord{
+		ID: record.ID,
+		Ingredient: dto.StockReductionIngredient{
+			ID:   record.Ingredient.ID,
+			Name: record.Ingredient.Name,
+			Slug: record.Ingredient.Slug,
+		},
+		Quantity: record.Quantity,
+		WastedUnit: dto.StockReductionUnit{
+			ID:   record.WastedUnit.ID,
+			Name: record.WastedUnit.Name,
+		},
+		QuantityReduced: record.QuantityReduced,
+		StockBefore:     record.StockBefore,
+		StockAfter:      record.StockAfter,
+		Unit: dto.StockReductionUnit{
+			ID:   record.Unit.ID,
+			Name: record.Unit.Name,
+		},
+		UnitCost:        record.UnitCost,
+		Value:           record.Value(),
+		Reason:          record.Reason,
+		Notes:           record.Notes,
+		WastedAt:        record.WastedAt,
+		UserID:          record.UserID,
+		StockMovementID: record.StockMovementID,
+	}
+}
@@ -1,27 +1,276 @@
 package migrations
 
 import (
-	"AwisPalace_IngredientManagement/config"
-	"AwisPalace_IngredientManagement/models"
+	"embed"
+	"errors"
 	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
 )
 
-func Migrate() {
-	err := config.DB.AutoMigrate(
-		&models.User{},
-		&models.Unit{},
-		&models.Ingredient{},
-		&models.Menu{},
-		models.MenuIngredient{},
-		//
-		models.Transaction{},
-		models.TransactionItem{},
-		models.StockReduction{},
-	)
+// Migration files live in sql/ as NNNN_name.up.sql and NNNN_name.down.sql
+// and are embedded in the binary.
+//
+//go:embed sql/*.sql
+var files embed.FS
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// ErrSchemaBehind is returned by EnsureUpToDate when migrations are pending
+var ErrSchemaBehind = errors.New("database schema is behind")
+
+// Lock key for pg_advisory_xact_lock so two instances never migrate at once
+const migrationLockKey = 7283461
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// SchemaMigration is a row of schema_migrations
+type SchemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"type:varchar(255);not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// Load returns the embedded migrations ordered by version
+func Load() ([]Migration, error) {
+	entries, err := fs.ReadDir(files, "sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		content, err := files.ReadFile(path.Join("sql", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
 
+	return migrations, nil
+}
+
+// Up applies all pending migrations in order and returns the applied ones
+func Up(db *gorm.DB) ([]Migration, error) {
+	migrations, err := Load()
 	if err != nil {
-		fmt.Println("❌ Migration failed:", err)
-	} else {
-		fmt.Println("✅ Migration success!")
+		return nil, err
+	}
+
+	if err := ensureSchemaMigrations(db); err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, migration := range migrations {
+		ran := false
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := lock(tx); err != nil {
+				return err
+			}
+
+			var count int64
+			if err := tx.Model(&SchemaMigration{}).Where("version = ?", migration.Version).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return nil
+			}
+
+			if err := tx.Exec(migration.Up).Error; err != nil {
+				return err
+			}
+
+			ran = true
+			return tx.Create(&SchemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return applied, fmt.Errorf("migration %04d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+
+		if ran {
+			applied = append(applied, migration)
+		}
+	}
+
+	return applied, nil
+}
+
+// Down rolls back the last steps applied migrations, newest first
+func Down(db *gorm.DB, steps int) ([]Migration, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureSchemaMigrations(db); err != nil {
+		return nil, err
 	}
+
+	byVersion := make(map[int]Migration, len(migrations))
+	for _, migration := range migrations {
+		byVersion[migration.Version] = migration
+	}
+
+	var rolledBack []Migration
+	for i := 0; i < steps; i++ {
+		var migration Migration
+		done := false
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := lock(tx); err != nil {
+				return err
+			}
+
+			var latest SchemaMigration
+			result := tx.Order("version DESC").Limit(1).Find(&latest)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				done = true
+				return nil
+			}
+
+			var ok bool
+			migration, ok = byVersion[latest.Version]
+			if !ok {
+				return fmt.Errorf("applied migration %d is not known to this binary", latest.Version)
+			}
+
+			if err := tx.Exec(migration.Down).Error; err != nil {
+				return err
+			}
+
+			return tx.Delete(&SchemaMigration{}, latest.Version).Error
+		})
+		if err != nil {
+			return rolledBack, fmt.Errorf("rollback of %04d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+
+		if done {
+			break
+		}
+		rolledBack = append(rolledBack, migration)
+	}
+
+	return rolledBack, nil
+}
+
+// Status returns every known migration with the time it was applied
+func Status(db *gorm.DB) ([]MigrationStatus, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureSchemaMigrations(db); err != nil {
+		return nil, err
+	}
+
+	var rows []SchemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	appliedAt := make(map[int]time.Time, len(rows))
+	for _, row := range rows {
+		appliedAt[row.Version] = row.AppliedAt
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := MigrationStatus{Migration: migration}
+		if at, ok := appliedAt[migration.Version]; ok {
+			status.AppliedAt = &at
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// EnsureUpToDate returns ErrSchemaBehind when any migration is not applied,
+// so the server refuses to start on an old schema
+func EnsureUpToDate(db *gorm.DB) error {
+	statuses, err := Status(db)
+	if err != nil {
+		return err
+	}
+
+	pending := 0
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			pending++
+		}
+	}
+
+	if pending > 0 {
+		return fmt.Errorf("%w: %d pending migration(s), run `migrate up`", ErrSchemaBehind, pending)
+	}
+
+	return nil
+}
+
+func ensureSchemaMigrations(db *gorm.DB) error {
+	return db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version bigint PRIMARY KEY,
+		name varchar(255) NOT NULL,
+		applied_at timestamptz NOT NULL
+	)`).Error
+}
+
+func lock(tx *gorm.DB) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockKey).Error
 }
@@ -0,0 +1,19 @@
+DROP TABLE IF EXISTS idempotency_keys;
+DROP TABLE IF EXISTS goods_receipt_items;
+DROP TABLE IF EXISTS goods_receipts;
+DROP TABLE IF EXISTS purchase_order_lines;
+DROP TABLE IF EXISTS purchase_orders;
+DROP TABLE IF EXISTS stock_alerts;
+DROP TABLE IF EXISTS stock_movements;
+DROP TABLE IF EXISTS refund_items;
+DROP TABLE IF EXISTS refunds;
+DROP TABLE IF EXISTS stock_reductions;
+DROP TABLE IF EXISTS transaction_items;
+DROP TABLE IF EXISTS transactions;
+DROP TABLE IF EXISTS document_sequences;
+DROP TABLE IF EXISTS menu_ingredients;
+DROP TABLE IF EXISTS menus;
+DROP TABLE IF EXISTS ingredient_unit_conversions;
+DROP TABLE IF EXISTS ingredients;
+DROP TABLE IF EXISTS units;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,283 @@
+-- Skema awal. Semua statement memakai IF NOT EXISTS agar database yang
+-- sebelumnya dibuat dengan AutoMigrate bisa langsung diadopsi.
+
+CREATE TABLE IF NOT EXISTS users (
+    id bigserial PRIMARY KEY,
+    google_id varchar(255),
+    email varchar(255) NOT NULL,
+    name varchar(255),
+    photo_url text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_google_id ON users (google_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role varchar(20) NOT NULL DEFAULT 'cashier';
+
+CREATE TABLE IF NOT EXISTS units (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    name varchar(50) NOT NULL,
+    symbol varchar(10)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_units_symbol ON units (symbol);
+CREATE INDEX IF NOT EXISTS idx_units_deleted_at ON units (deleted_at);
+ALTER TABLE units ADD COLUMN IF NOT EXISTS dimension varchar(20);
+ALTER TABLE units ADD COLUMN IF NOT EXISTS factor numeric(14,6) NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS ingredients (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    name varchar(100) NOT NULL,
+    slug varchar(100),
+    stock numeric(10,2),
+    unit_id bigint REFERENCES units (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ingredients_slug ON ingredients (slug);
+CREATE INDEX IF NOT EXISTS idx_ingredients_deleted_at ON ingredients (deleted_at);
+ALTER TABLE ingredients ADD COLUMN IF NOT EXISTS unit_cost numeric(14,4) NOT NULL DEFAULT 0;
+ALTER TABLE ingredients ADD COLUMN IF NOT EXISTS cost_method varchar(20) NOT NULL DEFAULT 'average';
+ALTER TABLE ingredients ADD COLUMN IF NOT EXISTS minimum_stock numeric(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE ingredients ADD COLUMN IF NOT EXISTS par_level numeric(10,2) NOT NULL DEFAULT 0;
+-- Sebelum reorder point per ingredient, stok <= 5 dianggap hampir habis
+ALTER TABLE ingredients ADD COLUMN IF NOT EXISTS reorder_point numeric(10,2) NOT NULL DEFAULT 5;
+ALTER TABLE ingredients ALTER COLUMN reorder_point SET DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS ingredient_unit_conversions (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    ingredient_id bigint REFERENCES ingredients (id),
+    unit_id bigint REFERENCES units (id),
+    quantity numeric(14,6) NOT NULL,
+    to_unit_id bigint REFERENCES units (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ingredient_conversion ON ingredient_unit_conversions (ingredient_id, unit_id);
+CREATE INDEX IF NOT EXISTS idx_ingredient_unit_conversions_deleted_at ON ingredient_unit_conversions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS menus (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    name varchar(150) NOT NULL,
+    slug varchar(150),
+    description text,
+    image text,
+    price numeric(12,2)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_menus_slug ON menus (slug);
+CREATE INDEX IF NOT EXISTS idx_menus_deleted_at ON menus (deleted_at);
+
+CREATE TABLE IF NOT EXISTS menu_ingredients (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    menu_id bigint REFERENCES menus (id),
+    ingredient_id bigint REFERENCES ingredients (id),
+    quantity numeric(10,2) NOT NULL,
+    unit_id bigint REFERENCES units (id)
+);
+CREATE INDEX IF NOT EXISTS idx_menu_ingredients_deleted_at ON menu_ingredients (deleted_at);
+
+CREATE TABLE IF NOT EXISTS document_sequences (
+    id bigserial PRIMARY KEY,
+    name varchar(100) NOT NULL,
+    value bigint NOT NULL DEFAULT 0,
+    updated_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_document_sequences_name ON document_sequences (name);
+
+CREATE TABLE IF NOT EXISTS transactions (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    transaction_code varchar(50) NOT NULL,
+    transaction_date timestamptz NOT NULL,
+    total_amount numeric(12,2),
+    notes text,
+    status varchar(20) DEFAULT 'completed'
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_transaction_code ON transactions (transaction_code);
+CREATE INDEX IF NOT EXISTS idx_transactions_deleted_at ON transactions (deleted_at);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS refunded_amount numeric(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS cancel_reason text;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS cancelled_at timestamptz;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS cancelled_by_id bigint REFERENCES users (id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS transaction_items (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    transaction_id bigint REFERENCES transactions (id),
+    menu_id bigint REFERENCES menus (id),
+    quantity bigint NOT NULL,
+    price numeric(12,2) NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_transaction_items_deleted_at ON transaction_items (deleted_at);
+ALTER TABLE transaction_items ADD COLUMN IF NOT EXISTS cost numeric(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE transaction_items ADD COLUMN IF NOT EXISTS refunded_quantity bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS stock_reductions (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    transaction_item_id bigint REFERENCES transaction_items (id),
+    ingredient_id bigint REFERENCES ingredients (id),
+    quantity_reduced numeric(10,2) NOT NULL,
+    stock_before numeric(10,2) NOT NULL,
+    stock_after numeric(10,2) NOT NULL,
+    unit_id bigint REFERENCES units (id)
+);
+CREATE INDEX IF NOT EXISTS idx_stock_reductions_deleted_at ON stock_reductions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS refunds (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    transaction_id bigint REFERENCES transactions (id),
+    reason text NOT NULL,
+    total_amount numeric(12,2),
+    user_id bigint REFERENCES users (id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_refunds_deleted_at ON refunds (deleted_at);
+
+CREATE TABLE IF NOT EXISTS refund_items (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    refund_id bigint REFERENCES refunds (id),
+    transaction_item_id bigint REFERENCES transaction_items (id),
+    quantity bigint NOT NULL,
+    amount numeric(12,2) NOT NULL,
+    restocked boolean NOT NULL DEFAULT false
+);
+CREATE INDEX IF NOT EXISTS idx_refund_items_deleted_at ON refund_items (deleted_at);
+
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    ingredient_id bigint REFERENCES ingredients (id),
+    type varchar(20) NOT NULL,
+    quantity numeric(10,2) NOT NULL,
+    stock_before numeric(10,2) NOT NULL,
+    stock_after numeric(10,2) NOT NULL,
+    unit_id bigint REFERENCES units (id),
+    user_id bigint REFERENCES users (id) ON DELETE SET NULL,
+    reference_type varchar(50),
+    reference_id bigint,
+    notes text
+);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_ingredient_id ON stock_movements (ingredient_id);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_type ON stock_movements (type);
+CREATE INDEX IF NOT EXISTS idx_stock_movement_reference ON stock_movements (reference_type, reference_id);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_deleted_at ON stock_movements (deleted_at);
+
+CREATE TABLE IF NOT EXISTS stock_alerts (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    ingredient_id bigint REFERENCES ingredients (id),
+    level varchar(30) NOT NULL,
+    stock numeric(10,2) NOT NULL,
+    threshold numeric(10,2) NOT NULL,
+    stock_movement_id bigint REFERENCES stock_movements (id),
+    acknowledged_at timestamptz,
+    acknowledged_by_id bigint REFERENCES users (id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_stock_alerts_ingredient_id ON stock_alerts (ingredient_id);
+CREATE INDEX IF NOT EXISTS idx_stock_alerts_acknowledged_at ON stock_alerts (acknowledged_at);
+CREATE INDEX IF NOT EXISTS idx_stock_alerts_deleted_at ON stock_alerts (deleted_at);
+
+CREATE TABLE IF NOT EXISTS purchase_orders (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    po_number varchar(50) NOT NULL,
+    order_date timestamptz NOT NULL,
+    expected_date timestamptz,
+    status varchar(30) DEFAULT 'draft',
+    total_amount numeric(14,2),
+    notes text
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_purchase_orders_po_number ON purchase_orders (po_number);
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_deleted_at ON purchase_orders (deleted_at);
+
+CREATE TABLE IF NOT EXISTS purchase_order_lines (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    purchase_order_id bigint REFERENCES purchase_orders (id),
+    ingredient_id bigint REFERENCES ingredients (id),
+    quantity numeric(12,2) NOT NULL,
+    received_quantity numeric(12,2) NOT NULL DEFAULT 0,
+    unit_id bigint REFERENCES units (id),
+    unit_cost numeric(12,2) NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_purchase_order_lines_deleted_at ON purchase_order_lines (deleted_at);
+
+CREATE TABLE IF NOT EXISTS goods_receipts (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    receipt_number varchar(50) NOT NULL,
+    received_date timestamptz NOT NULL,
+    notes text,
+    purchase_order_id bigint REFERENCES purchase_orders (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_goods_receipts_receipt_number ON goods_receipts (receipt_number);
+CREATE INDEX IF NOT EXISTS idx_goods_receipts_deleted_at ON goods_receipts (deleted_at);
+
+CREATE TABLE IF NOT EXISTS goods_receipt_items (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    goods_receipt_id bigint REFERENCES goods_receipts (id),
+    purchase_order_line_id bigint REFERENCES purchase_order_lines (id),
+    ingredient_id bigint REFERENCES ingredients (id),
+    quantity_received numeric(12,2) NOT NULL,
+    received_unit_id bigint REFERENCES units (id),
+    unit_cost numeric(12,2) NOT NULL,
+    quantity_added numeric(10,2) NOT NULL,
+    stock_before numeric(10,2) NOT NULL,
+    stock_after numeric(10,2) NOT NULL,
+    unit_id bigint REFERENCES units (id)
+);
+CREATE INDEX IF NOT EXISTS idx_goods_receipt_items_deleted_at ON goods_receipt_items (deleted_at);
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL,
+    key varchar(255) NOT NULL,
+    method varchar(10) NOT NULL,
+    path text NOT NULL,
+    request_hash varchar(64) NOT NULL,
+    status_code bigint,
+    content_type varchar(100),
+    response text,
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_idempotency_user_key ON idempotency_keys (user_id, key);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
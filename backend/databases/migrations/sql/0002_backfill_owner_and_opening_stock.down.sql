@@ -0,0 +1,2 @@
+-- Data backfill, tidak ada yang perlu dikembalikan
+SELECT 1;
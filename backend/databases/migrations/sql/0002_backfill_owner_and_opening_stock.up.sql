@@ -0,0 +1,13 @@
+-- User pertama menjadi owner jika belum ada owner, agar role user lain
+-- tetap bisa diatur setelah role diperkenalkan
+UPDATE users SET role = 'owner'
+WHERE id = (SELECT MIN(id) FROM users WHERE deleted_at IS NULL)
+  AND NOT EXISTS (SELECT 1 FROM users WHERE role = 'owner' AND deleted_at IS NULL);
+
+-- Saldo awal di ledger untuk ingredient yang stoknya sudah ada sebelum
+-- stock movement diperkenalkan
+INSERT INTO stock_movements (created_at, updated_at, ingredient_id, type, quantity, stock_before, stock_after, unit_id, reference_type, reference_id, notes)
+SELECT now(), now(), ingredients.id, 'adjustment', ingredients.stock, 0, ingredients.stock, ingredients.unit_id, 'ingredient', ingredients.id, 'Opening balance'
+FROM ingredients
+WHERE ingredients.stock <> 0
+  AND NOT EXISTS (SELECT 1 FROM stock_movements WHERE stock_movements.ingredient_id = ingredients.id);
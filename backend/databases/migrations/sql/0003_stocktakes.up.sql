@@ -0,0 +1,32 @@
+CREATE TABLE stocktakes (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    code varchar(50) NOT NULL,
+    status varchar(20) NOT NULL DEFAULT 'draft',
+    notes text,
+    started_at timestamptz,
+    posted_at timestamptz,
+    created_by_id bigint REFERENCES users (id) ON DELETE SET NULL,
+    posted_by_id bigint REFERENCES users (id) ON DELETE SET NULL
+);
+CREATE UNIQUE INDEX idx_stocktakes_code ON stocktakes (code);
+CREATE INDEX idx_stocktakes_status ON stocktakes (status);
+CREATE INDEX idx_stocktakes_deleted_at ON stocktakes (deleted_at);
+
+CREATE TABLE stocktake_lines (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    stocktake_id bigint REFERENCES stocktakes (id),
+    ingredient_id bigint REFERENCES ingredients (id),
+    unit_id bigint REFERENCES units (id),
+    theoretical_stock numeric(10,2) NOT NULL DEFAULT 0,
+    counted_quantity numeric(10,2),
+    unit_cost numeric(12,2) NOT NULL DEFAULT 0,
+    stock_movement_id bigint REFERENCES stock_movements (id)
+);
+CREATE UNIQUE INDEX idx_stocktake_line_ingredient ON stocktake_lines (stocktake_id, ingredient_id);
+CREATE INDEX idx_stocktake_lines_deleted_at ON stocktake_lines (deleted_at);
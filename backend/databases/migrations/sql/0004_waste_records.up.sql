@@ -0,0 +1,23 @@
+CREATE TABLE waste_records (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    ingredient_id bigint REFERENCES ingredients (id),
+    quantity numeric(12,2) NOT NULL,
+    wasted_unit_id bigint REFERENCES units (id),
+    quantity_reduced numeric(10,2) NOT NULL,
+    stock_before numeric(10,2) NOT NULL,
+    stock_after numeric(10,2) NOT NULL,
+    unit_id bigint REFERENCES units (id),
+    unit_cost numeric(12,2) NOT NULL,
+    reason varchar(20) NOT NULL,
+    notes text,
+    wasted_at timestamptz NOT NULL,
+    user_id bigint REFERENCES users (id) ON DELETE SET NULL,
+    stock_movement_id bigint REFERENCES stock_movements (id)
+);
+CREATE INDEX idx_waste_records_ingredient_id ON waste_records (ingredient_id);
+CREATE INDEX idx_waste_records_reason ON waste_records (reason);
+CREATE INDEX idx_waste_records_wasted_at ON waste_records (wasted_at);
+CREATE INDEX idx_waste_records_deleted_at ON waste_records (deleted_at);
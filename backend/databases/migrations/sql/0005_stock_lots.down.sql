@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS stock_reduction_lots;
+DROP TABLE IF EXISTS stock_lots;
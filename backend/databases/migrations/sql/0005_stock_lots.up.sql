@@ -0,0 +1,31 @@
+CREATE TABLE stock_lots (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    ingredient_id bigint REFERENCES ingredients (id),
+    lot_number varchar(50),
+    received_date timestamptz NOT NULL,
+    expiry_date timestamptz,
+    quantity numeric(10,2) NOT NULL,
+    remaining numeric(10,2) NOT NULL,
+    unit_id bigint REFERENCES units (id),
+    goods_receipt_item_id bigint REFERENCES goods_receipt_items (id)
+);
+CREATE INDEX idx_stock_lots_ingredient_id ON stock_lots (ingredient_id);
+CREATE INDEX idx_stock_lots_expiry_date ON stock_lots (expiry_date);
+CREATE INDEX idx_stock_lots_remaining ON stock_lots (remaining);
+CREATE INDEX idx_stock_lots_deleted_at ON stock_lots (deleted_at);
+
+CREATE TABLE stock_reduction_lots (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    stock_reduction_id bigint REFERENCES stock_reductions (id),
+    stock_lot_id bigint REFERENCES stock_lots (id),
+    quantity numeric(10,2) NOT NULL
+);
+CREATE INDEX idx_stock_reduction_lots_stock_reduction_id ON stock_reduction_lots (stock_reduction_id);
+CREATE INDEX idx_stock_reduction_lots_stock_lot_id ON stock_reduction_lots (stock_lot_id);
+CREATE INDEX idx_stock_reduction_lots_deleted_at ON stock_reduction_lots (deleted_at);
@@ -0,0 +1,32 @@
+CREATE TABLE suppliers (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    name varchar(100) NOT NULL,
+    contact_name varchar(100),
+    phone varchar(30),
+    email varchar(100),
+    address text,
+    lead_time_days bigint NOT NULL DEFAULT 0,
+    payment_terms varchar(100),
+    notes text
+);
+CREATE INDEX idx_suppliers_deleted_at ON suppliers (deleted_at);
+
+CREATE TABLE supplier_ingredients (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    supplier_id bigint REFERENCES suppliers (id),
+    ingredient_id bigint REFERENCES ingredients (id),
+    sku varchar(50),
+    pack_size numeric(12,2) NOT NULL,
+    pack_unit_id bigint REFERENCES units (id),
+    last_price numeric(14,2) NOT NULL DEFAULT 0,
+    last_price_at timestamptz
+);
+CREATE UNIQUE INDEX idx_supplier_ingredient ON supplier_ingredients (supplier_id, ingredient_id);
+CREATE INDEX idx_supplier_ingredients_ingredient_id ON supplier_ingredients (ingredient_id);
+CREATE INDEX idx_supplier_ingredients_deleted_at ON supplier_ingredients (deleted_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_purchase_orders_supplier_id;
+ALTER TABLE purchase_orders DROP COLUMN IF EXISTS supplier_id;
@@ -0,0 +1,2 @@
+ALTER TABLE purchase_orders ADD COLUMN supplier_id bigint REFERENCES suppliers (id) ON DELETE SET NULL;
+CREATE INDEX idx_purchase_orders_supplier_id ON purchase_orders (supplier_id);
@@ -0,0 +1 @@
+ALTER TABLE menus DROP COLUMN IF EXISTS sold_out;
@@ -0,0 +1 @@
+ALTER TABLE menus ADD COLUMN sold_out boolean NOT NULL DEFAULT false;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS transaction_item_modifiers;
+DROP TABLE IF EXISTS menu_modifier_ingredients;
+DROP TABLE IF EXISTS menu_modifier_options;
+DROP TABLE IF EXISTS menu_modifier_groups;
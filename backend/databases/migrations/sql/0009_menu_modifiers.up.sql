@@ -0,0 +1,53 @@
+CREATE TABLE menu_modifier_groups (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    menu_id bigint REFERENCES menus (id),
+    name varchar(100) NOT NULL,
+    min_select bigint NOT NULL DEFAULT 0,
+    max_select bigint NOT NULL DEFAULT 0,
+    sort_order bigint NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_menu_modifier_groups_menu_id ON menu_modifier_groups (menu_id);
+CREATE INDEX idx_menu_modifier_groups_deleted_at ON menu_modifier_groups (deleted_at);
+
+CREATE TABLE menu_modifier_options (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    group_id bigint REFERENCES menu_modifier_groups (id),
+    name varchar(100) NOT NULL,
+    price_delta numeric(12,2) NOT NULL DEFAULT 0,
+    sort_order bigint NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_menu_modifier_options_group_id ON menu_modifier_options (group_id);
+CREATE INDEX idx_menu_modifier_options_deleted_at ON menu_modifier_options (deleted_at);
+
+CREATE TABLE menu_modifier_ingredients (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    option_id bigint REFERENCES menu_modifier_options (id),
+    ingredient_id bigint REFERENCES ingredients (id),
+    quantity numeric(10,2) NOT NULL,
+    unit_id bigint REFERENCES units (id)
+);
+CREATE INDEX idx_menu_modifier_ingredients_option_id ON menu_modifier_ingredients (option_id);
+CREATE INDEX idx_menu_modifier_ingredients_deleted_at ON menu_modifier_ingredients (deleted_at);
+
+CREATE TABLE transaction_item_modifiers (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    transaction_item_id bigint REFERENCES transaction_items (id),
+    modifier_option_id bigint REFERENCES menu_modifier_options (id),
+    group_name varchar(100) NOT NULL,
+    option_name varchar(100) NOT NULL,
+    price_delta numeric(12,2) NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_transaction_item_modifiers_transaction_item_id ON transaction_item_modifiers (transaction_item_id);
+CREATE INDEX idx_transaction_item_modifiers_deleted_at ON transaction_item_modifiers (deleted_at);
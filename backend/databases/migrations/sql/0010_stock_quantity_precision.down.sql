@@ -0,0 +1,46 @@
+ALTER TABLE ingredients
+    ALTER COLUMN stock TYPE numeric(10,2),
+    ALTER COLUMN minimum_stock TYPE numeric(10,2),
+    ALTER COLUMN reorder_point TYPE numeric(10,2),
+    ALTER COLUMN par_level TYPE numeric(10,2);
+
+ALTER TABLE menu_ingredients
+    ALTER COLUMN quantity TYPE numeric(10,2);
+
+ALTER TABLE menu_modifier_ingredients
+    ALTER COLUMN quantity TYPE numeric(10,2);
+
+ALTER TABLE stock_reductions
+    ALTER COLUMN quantity_reduced TYPE numeric(10,2),
+    ALTER COLUMN stock_before TYPE numeric(10,2),
+    ALTER COLUMN stock_after TYPE numeric(10,2);
+
+ALTER TABLE stock_movements
+    ALTER COLUMN quantity TYPE numeric(10,2),
+    ALTER COLUMN stock_before TYPE numeric(10,2),
+    ALTER COLUMN stock_after TYPE numeric(10,2);
+
+ALTER TABLE stock_alerts
+    ALTER COLUMN stock TYPE numeric(10,2),
+    ALTER COLUMN threshold TYPE numeric(10,2);
+
+ALTER TABLE goods_receipt_items
+    ALTER COLUMN quantity_added TYPE numeric(10,2),
+    ALTER COLUMN stock_before TYPE numeric(10,2),
+    ALTER COLUMN stock_after TYPE numeric(10,2);
+
+ALTER TABLE stocktake_lines
+    ALTER COLUMN theoretical_stock TYPE numeric(10,2),
+    ALTER COLUMN counted_quantity TYPE numeric(10,2);
+
+ALTER TABLE waste_records
+    ALTER COLUMN quantity_reduced TYPE numeric(10,2),
+    ALTER COLUMN stock_before TYPE numeric(10,2),
+    ALTER COLUMN stock_after TYPE numeric(10,2);
+
+ALTER TABLE stock_lots
+    ALTER COLUMN quantity TYPE numeric(10,2),
+    ALTER COLUMN remaining TYPE numeric(10,2);
+
+ALTER TABLE stock_reduction_lots
+    ALTER COLUMN quantity TYPE numeric(10,2);
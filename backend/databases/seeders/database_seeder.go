@@ -2,25 +2,78 @@ package seeders
 
 import (
 	"fmt"
+	"strings"
 
 	"gorm.io/gorm"
 )
 
+// Seeder adalah satu seeder dengan nama untuk `seed --only`
+type Seeder struct {
+	Name string
+	Run  func(*gorm.DB) error
+}
+
+// Seeders berurutan sesuai dependensi data
+var Seeders = []Seeder{
+	{Name: "units", Run: SeedUnits},
+	{Name: "ingredients", Run: IngredientSeeder},
+	{Name: "menus", Run: MenuSeeder},
+}
+
 func DatabaseSeeder(db *gorm.DB) {
-	fmt.Println("🚀 Menjalankan Database Seeder...")
+	if err := Run(db, nil); err != nil {
+		fmt.Println("❌", err)
+	}
+}
+
+// Run menjalankan seeder dengan nama di only (semua seeder jika kosong)
+func Run(db *gorm.DB, only []string) error {
+	selected := make(map[string]bool, len(only))
+	for _, name := range only {
+		selected[name] = true
+	}
 
-	// Seeder List
-	seeders := []func(*gorm.DB) error{
-		SeedUnits,
-		IngredientSeeder,
-		MenuSeeder,
+	for name := range selected {
+		if !hasSeeder(name) {
+			return fmt.Errorf("unknown seeder %q, available: %s", name, seederNames())
+		}
 	}
 
-	for _, seed := range seeders {
-		if err := seed(db); err != nil {
+	fmt.Println("🚀 Menjalankan Database Seeder...")
+
+	failed := false
+	for _, seeder := range Seeders {
+		if len(selected) > 0 && !selected[seeder.Name] {
+			continue
+		}
+
+		if err := seeder.Run(db); err != nil {
 			fmt.Println("❌ Gagal menjalankan seeder:", err)
+			failed = true
 		}
 	}
 
+	if failed {
+		return fmt.Errorf("some seeders failed")
+	}
+
 	fmt.Println("✅ Semua seeder berhasil dijalankan!")
+	return nil
+}
+
+func hasSeeder(name string) bool {
+	for _, seeder := range Seeders {
+		if seeder.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func seederNames() string {
+	names := make([]string, 0, len(Seeders))
+	for _, seeder := range Seeders {
+		names = append(names, seeder.Name)
+	}
+	return strings.Join(names, ", ")
 }
@@ -3,6 +3,7 @@ package seeders
 import (
 	"AwisPalace_IngredientManagement/config"
 	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/utils"
 	"fmt"
 
 	"gorm.io/gorm"
@@ -33,7 +34,7 @@ func IngredientSeeder(db *gorm.DB) error {
 	for _, ingredient := range ingredients {
 		var existing models.Ingredient
 		if err := config.DB.Where("slug = ?", ingredient.Slug).First(&existing).Error; err != nil {
-			if err := config.DB.Create(&ingredient).Error; err != nil {
+			if err := createSeedIngredient(db, ingredient); err != nil {
 				fmt.Printf("❌ Gagal menambahkan ingredient %s: %v\n", ingredient.Name, err)
 			} else {
 				fmt.Printf("✅ Berhasil menambahkan ingredient: %s\n", ingredient.Name)
@@ -45,3 +46,24 @@ func IngredientSeeder(db *gorm.DB) error {
 
 	return nil
 }
+
+// createSeedIngredient membuat ingredient dan mencatat stok awal di ledger
+func createSeedIngredient(db *gorm.DB, ingredient models.Ingredient) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		stock := ingredient.Stock
+		ingredient.Stock = 0
+
+		if err := tx.Create(&ingredient).Error; err != nil {
+			return err
+		}
+
+		return utils.RecordStockMovement(tx, &models.StockMovement{
+			IngredientID:  ingredient.ID,
+			Type:          models.StockMovementAdjustment,
+			Quantity:      stock,
+			ReferenceType: "ingredient",
+			ReferenceID:   ingredient.ID,
+			Notes:         "Initial stock",
+		})
+	})
+}
@@ -9,12 +9,13 @@ import (
 
 func SeedUnits(db *gorm.DB) error {
 	units := []models.Unit{
-		{Name: "Kilogram", Symbol: "kg"},
-		{Name: "Gram", Symbol: "g"},
-		{Name: "Liter", Symbol: "L"},
-		{Name: "Meter", Symbol: "m"},
-		{Name: "Packages", Symbol: "pcs"},
+		{Name: "Kilogram", Symbol: "kg", Dimension: models.DimensionMass, Factor: 1000},
+		{Name: "Gram", Symbol: "g", Dimension: models.DimensionMass, Factor: 1},
+		{Name: "Liter", Symbol: "L", Dimension: models.DimensionVolume, Factor: 1000},
+		{Name: "Meter", Symbol: "m", Dimension: models.DimensionLength, Factor: 1},
+		{Name: "Packages", Symbol: "pcs", Dimension: models.DimensionCount, Factor: 1},
 		{Name: "Persen", Symbol: "%"},
+		{Name: "Mililiter", Symbol: "ml", Dimension: models.DimensionVolume, Factor: 1},
 	}
 
 	for _, unit := range units {
@@ -24,6 +25,12 @@ func SeedUnits(db *gorm.DB) error {
 				return fmt.Errorf("❌ gagal menambahkan unit %s: %v", unit.Name, err)
 			}
 			fmt.Printf("✅ Unit %s berhasil ditambahkan\n", unit.Name)
+		} else if existing.Dimension == "" && unit.Dimension != "" {
+			// Lengkapi dimensi untuk unit lama
+			if err := db.Model(&existing).Updates(models.Unit{Dimension: unit.Dimension, Factor: unit.Factor}).Error; err != nil {
+				return fmt.Errorf("❌ gagal memperbarui unit %s: %v", unit.Name, err)
+			}
+			fmt.Printf("✅ Dimensi unit %s berhasil dilengkapi\n", unit.Name)
 		} else {
 			fmt.Printf("⚠️  Unit %s sudah ada, dilewati\n", unit.Name)
 		}
@@ -78,11 +78,6 @@ const docTemplate = `{
         },
         "/auth/refresh": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
                 "description": "Generate a new JWT token from existing valid token",
                 "consumes": [
                     "application/json"
@@ -109,16 +104,16 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     }
-                }
-            }
-        },
-        "/auth/verify": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
+                ]
+            }
+        },
+        "/auth/verify": {
+            "get": {
                 "description": "Verify if the provided JWT token is valid",
                 "consumes": [
                     "application/json"
@@ -145,12 +140,17 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
         "/export/transactions": {
             "get": {
-                "description": "Export transaction data with ingredient usage to Excel file. Returns Excel file with 3 sheets: Transactions (all transaction details), Ingredient Usage (ingredients used per transaction with stock changes), and Summary (statistics and top selling items). Supports date range filtering, defaults to last 30 days if dates not specified.",
+                "description": "Export transaction data with ingredient usage to Excel file. Returns Excel file with 4 sheets: Transactions (all transaction details), Ingredient Usage (ingredients used per transaction with stock changes), Summary (statistics and top selling items) and Waste (waste records with totals by reason, ingredient and date). The CSV format only contains transactions. Supports date range filtering, defaults to last 30 days if dates not specified.",
                 "consumes": [
                     "application/json"
                 ],
@@ -175,19 +175,79 @@ const docTemplate = `{
                         "description": "End date in YYYY-MM-DD format. Defaults to today if not specified.",
                         "name": "end_date",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "xlsx (default) or csv",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
-                "responses": {}
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
         "/ingredients": {
             "get": {
-                "description": "Get Ingredients",
+                "description": "Get Ingredients with pagination, sorting and filters",
                 "tags": [
                     "Ingredients"
                 ],
                 "summary": "Get Ingredients",
-                "responses": {}
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the last item of the previous page (sort by id only)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id, name, stock, unit_cost, created_at; prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unit ID",
+                        "name": "unit_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only ingredients with stock below this value",
+                        "name": "stock_below",
+                        "in": "query"
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             },
             "post": {
                 "description": "Post Ingredients",
@@ -206,10 +266,105 @@ const docTemplate = `{
                         }
                     }
                 ],
-                "responses": {}
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/ingredients/expiring-lots": {
+            "get": {
+                "description": "Get the lots that still hold stock and expire within the given number of days, including lots that have already expired",
+                "tags": [
+                    "Ingredients"
+                ],
+                "summary": "Get Expiring Lots",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Days from today (default 7)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/ingredients/low-stock": {
+            "get": {
+                "description": "Get ingredients at or below their reorder point or minimum stock, with the shortage to the par level",
+                "tags": [
+                    "Ingredients"
+                ],
+                "summary": "Get Low Stock Ingredients",
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/ingredients/reorder-suggestions": {
+            "get": {
+                "description": "Suggest order quantities from the average daily usage of the last days of sales, the current stock, open purchase orders, the lead time of the cheapest supplier and the par level",
+                "tags": [
+                    "Ingredients"
+                ],
+                "summary": "Get Reorder Suggestions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Days of sales used for the average daily usage (default 28, max 365)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
         "/ingredients/{id}": {
+            "get": {
+                "description": "Get Ingredient by ID with its suppliers, cheapest price per stock unit first",
+                "tags": [
+                    "Ingredients"
+                ],
+                "summary": "Get Ingredient",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ingredient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.IngredientDetail"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
             "put": {
                 "description": "Update an existing ingredient by ID",
                 "tags": [
@@ -234,7 +389,12 @@ const docTemplate = `{
                         }
                     }
                 ],
-                "responses": {}
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             },
             "delete": {
                 "description": "Delete a ingredient by ID",
@@ -251,114 +411,373 @@ const docTemplate = `{
                         "required": true
                     }
                 ],
-                "responses": {}
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/menus": {
+        "/ingredients/{id}/conversions": {
             "get": {
-                "description": "Get all menus with ingredients",
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Get unit conversions of an ingredient (e.g. 1 pcs = 60 g)",
                 "tags": [
-                    "Menus"
+                    "Ingredients"
                 ],
-                "summary": "Get Menus",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
+                "summary": "Get Ingredient Conversions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ingredient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
-                }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             },
             "post": {
-                "description": "Create menu with image upload",
-                "consumes": [
-                    "multipart/form-data"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Add a unit conversion to an ingredient, 1 unit_id = quantity to_unit_id",
                 "tags": [
-                    "Menus"
+                    "Ingredients"
                 ],
-                "summary": "Create Menu",
+                "summary": "Post Ingredient Conversion",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Menu Name",
-                        "name": "name",
-                        "in": "formData",
+                        "type": "integer",
+                        "description": "Ingredient ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "number",
-                        "description": "Menu Price",
-                        "name": "price",
-                        "in": "formData",
-                        "required": true
-                    },
+                        "description": "Conversion data",
+                        "name": "conversion",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.IngredientConversionRequest"
+                        }
+                    }
+                ],
+                "responses": {},
+                "security": [
                     {
-                        "type": "string",
-                        "description": "Menu Description",
-                        "name": "description",
-                        "in": "formData"
-                    },
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/ingredients/{id}/conversions/{conversionId}": {
+            "delete": {
+                "description": "Delete a unit conversion of an ingredient",
+                "tags": [
+                    "Ingredients"
+                ],
+                "summary": "Delete Ingredient Conversion",
+                "parameters": [
                     {
-                        "type": "string",
-                        "description": "Ingredients JSON array",
-                        "name": "ingredients",
-                        "in": "formData",
+                        "type": "integer",
+                        "description": "Ingredient ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "file",
-                        "description": "Menu Image",
-                        "name": "image",
-                        "in": "formData",
+                        "type": "integer",
+                        "description": "Conversion ID",
+                        "name": "conversionId",
+                        "in": "path",
                         "required": true
                     }
                 ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
                     }
-                }
+                ]
             }
         },
-        "/menus/{id}": {
+        "/ingredients/{id}/lots": {
             "get": {
-                "description": "Get Menu by ID",
+                "description": "Get the lots of an ingredient that still hold stock, in the order sales consume them (first expiry first out)",
                 "tags": [
-                    "Menus"
+                    "Ingredients"
                 ],
-                "summary": "Get Menu",
+                "summary": "Get Ingredient Lots",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Menu ID",
+                        "description": "Ingredient ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
-                "responses": {}
-            },
-            "put": {
-                "description": "Update menu by ID with optional image upload",
-                "consumes": [
-                    "multipart/form-data"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/ingredients/{id}/movements": {
+            "get": {
+                "description": "Get the stock ledger of an ingredient with pagination, newest first. The reconciliation covers the whole ledger, not only the page.",
+                "tags": [
+                    "Ingredients"
+                ],
+                "summary": "Get Ingredient Stock Movements",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ingredient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the last item of the previous page (sort by id only)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id, type, quantity, created_at; prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Movement type (sale, sale_reversal, purchase, adjustment, waste, transfer, stocktake)",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Record a manual stock adjustment or transfer for an ingredient",
+                "tags": [
+                    "Ingredients"
+                ],
+                "summary": "Post Ingredient Stock Movement",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ingredient ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Stock movement",
+                        "name": "movement",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.StockMovementCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/menus": {
+            "get": {
+                "description": "Get menus with ingredients, with pagination, sorting and filters",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Menus"
+                ],
+                "summary": "Get Menus",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the last item of the previous page (sort by id only)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id, name, price, created_at; prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only menus using this ingredient",
+                        "name": "ingredient_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only menus that are not sold out and can be made from the current stock",
+                        "name": "available_only",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create menu with image upload",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Menus"
+                ],
+                "summary": "Create Menu",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Menu Name",
+                        "name": "name",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Menu Price",
+                        "name": "price",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Menu Description",
+                        "name": "description",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ingredients JSON array",
+                        "name": "ingredients",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Menu Image",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/menus/{id}": {
+            "get": {
+                "description": "Get Menu by ID with recipe cost, gross margin, food cost percentage and the portions that can be made from the current stock",
+                "tags": [
+                    "Menus"
+                ],
+                "summary": "Get Menu",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Menu ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Update menu by ID with optional image upload",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "Menus"
                 ],
@@ -413,7 +832,12 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             },
             "delete": {
                 "description": "Delete menu by ID",
@@ -441,241 +865,2075 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/transactions": {
-            "get": {
-                "description": "Get Transactions with optional date filter (default: this week)",
+        "/menus/{id}/modifiers": {
+            "put": {
+                "description": "Replace the modifier groups of a menu (size, add-ons, removals). Each option has a price delta and recipe changes per portion, a negative quantity removes from the recipe",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Transactions"
+                    "Menus"
                 ],
-                "summary": "Get Transactions",
+                "summary": "Set Menu Modifiers",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Start date (YYYY-MM-DD)",
-                        "name": "start_date",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "Menu ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "End date (YYYY-MM-DD)",
-                        "name": "end_date",
-                        "in": "query"
+                        "description": "Modifier groups",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MenuModifiersRequest"
+                        }
                     }
                 ],
-                "responses": {}
-            },
-            "post": {
-                "description": "Create Transaction",
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/menus/{id}/sold-out": {
+            "put": {
+                "description": "Mark a menu as sold out (86'd) so it cannot be sold, or available again",
                 "tags": [
-                    "Transactions"
+                    "Menus"
                 ],
-                "summary": "Create Transaction",
+                "summary": "Mark Menu Sold Out",
                 "parameters": [
                     {
-                        "description": "Create transaction",
-                        "name": "transaction",
+                        "type": "integer",
+                        "description": "Menu ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Sold out flag",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.TransactionCreateRequest"
+                            "$ref": "#/definitions/dto.MenuSoldOutRequest"
                         }
                     }
                 ],
-                "responses": {}
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/transactions/{id}": {
+        "/purchase-orders": {
             "get": {
-                "description": "Get Transaction by ID",
+                "description": "Get Purchase Orders with optional status filter",
                 "tags": [
-                    "Transactions"
+                    "Purchase Orders"
                 ],
-                "summary": "Get Transaction",
+                "summary": "Get Purchase Orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Status (draft, sent, partially_received, received)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a draft Purchase Order",
+                "tags": [
+                    "Purchase Orders"
+                ],
+                "summary": "Create Purchase Order",
+                "parameters": [
+                    {
+                        "description": "Create purchase order",
+                        "name": "purchase_order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PurchaseOrderCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/purchase-orders/from-suggestions": {
+            "post": {
+                "description": "Turn the reorder suggestions into draft purchase orders, one per supplier",
+                "tags": [
+                    "Purchase Orders"
+                ],
+                "summary": "Create Purchase Orders from Reorder Suggestions",
+                "parameters": [
+                    {
+                        "description": "Usage window and ingredients to order",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReorderDraftRequest"
+                        }
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/purchase-orders/{id}": {
+            "get": {
+                "description": "Get Purchase Order by ID",
+                "tags": [
+                    "Purchase Orders"
+                ],
+                "summary": "Get Purchase Order",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Transaction ID",
+                        "description": "Purchase Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Update a draft Purchase Order by ID (lines are replaced)",
+                "tags": [
+                    "Purchase Orders"
+                ],
+                "summary": "Update Purchase Order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase Order ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Updated purchase order",
+                        "name": "purchase_order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PurchaseOrderCreateRequest"
+                        }
                     }
                 ],
-                "responses": {}
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             },
             "delete": {
-                "description": "Delete Transaction by ID (and restore stock)",
+                "description": "Delete a draft Purchase Order by ID",
                 "tags": [
-                    "Transactions"
+                    "Purchase Orders"
                 ],
-                "summary": "Delete Transaction",
+                "summary": "Delete Purchase Order",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Transaction ID",
+                        "description": "Purchase Order ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
-                "responses": {}
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/units": {
+        "/purchase-orders/{id}/send": {
+            "post": {
+                "description": "Mark a draft Purchase Order as sent to the supplier",
+                "tags": [
+                    "Purchase Orders"
+                ],
+                "summary": "Send Purchase Order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/receipts": {
             "get": {
-                "description": "Get Units",
+                "description": "Get Goods Receipts with optional purchase order filter",
                 "tags": [
-                    "Units"
+                    "Goods Receipts"
                 ],
-                "summary": "Get Units",
-                "responses": {}
+                "summary": "Get Goods Receipts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase Order ID",
+                        "name": "purchase_order_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             },
             "post": {
-                "description": "Post Units",
+                "description": "Receive goods for a sent Purchase Order and raise ingredient stock. Every item becomes a stock lot with its optional expiry date.",
                 "tags": [
-                    "Units"
+                    "Goods Receipts"
                 ],
-                "summary": "Post Units",
+                "summary": "Receive Goods",
                 "parameters": [
                     {
-                        "description": "Unit data",
-                        "name": "unit",
+                        "description": "Receive goods",
+                        "name": "receipt",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UnitParamRequest"
+                            "$ref": "#/definitions/dto.GoodsReceiptCreateRequest"
                         }
                     }
                 ],
-                "responses": {}
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/units/{id}": {
-            "put": {
-                "description": "Update an existing unit by ID",
+        "/receipts/{id}": {
+            "get": {
+                "description": "Get Goods Receipt by ID",
                 "tags": [
-                    "Units"
+                    "Goods Receipts"
                 ],
-                "summary": "Update Unit",
+                "summary": "Get Goods Receipt",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Unit ID",
+                        "description": "Goods Receipt ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/stock-alerts": {
+            "get": {
+                "description": "Get low stock alerts raised by stock movements, newest first",
+                "tags": [
+                    "Stock Alerts"
+                ],
+                "summary": "Get Stock Alerts",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Filter by acknowledged state",
+                        "name": "acknowledged",
+                        "in": "query"
                     },
                     {
-                        "description": "Updated unit data",
-                        "name": "unit",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UnitParamRequest"
-                        }
+                        "type": "integer",
+                        "description": "Ingredient ID",
+                        "name": "ingredient_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Level (below_reorder_point, below_minimum)",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the last item of the previous page (sort by id only)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id, created_at; prefix with - for descending (default -id)",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
-                "responses": {}
-            },
-            "delete": {
-                "description": "Delete a unit by ID",
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/stock-alerts/{id}/acknowledge": {
+            "post": {
+                "description": "Mark a stock alert as acknowledged by the current user",
                 "tags": [
-                    "Units"
+                    "Stock Alerts"
                 ],
-                "summary": "Delete Unit",
+                "summary": "Acknowledge Stock Alert",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Unit ID",
+                        "type": "integer",
+                        "description": "Stock Alert ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
-                ],
-                "responses": {}
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/stocktakes": {
+            "get": {
+                "description": "Get Stocktakes with pagination, sorting and filters",
+                "tags": [
+                    "Stocktakes"
+                ],
+                "summary": "Get Stocktakes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the last item of the previous page (sort by id only)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id, code, status, created_at, posted_at; prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status (draft, counting, posted)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a draft Stocktake of the given ingredients, or of all ingredients when ingredient_ids is empty",
+                "tags": [
+                    "Stocktakes"
+                ],
+                "summary": "Create Stocktake",
+                "parameters": [
+                    {
+                        "description": "Create stocktake",
+                        "name": "stocktake",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.StocktakeCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/stocktakes/{id}": {
+            "get": {
+                "description": "Get Stocktake by ID with its counted lines",
+                "tags": [
+                    "Stocktakes"
+                ],
+                "summary": "Get Stocktake",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stocktake ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a Stocktake that has not been posted",
+                "tags": [
+                    "Stocktakes"
+                ],
+                "summary": "Delete Stocktake",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stocktake ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/stocktakes/{id}/counts": {
+            "put": {
+                "description": "Enter counted quantities per ingredient in the ingredient's stock unit. Counting an ingredient again replaces the earlier quantity.",
+                "tags": [
+                    "Stocktakes"
+                ],
+                "summary": "Record Stocktake Counts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stocktake ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Counted quantities",
+                        "name": "counts",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.StocktakeCountRequest"
+                        }
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/stocktakes/{id}/post": {
+            "post": {
+                "description": "Book the variance of every counted ingredient as a stocktake stock movement in one transaction",
+                "tags": [
+                    "Stocktakes"
+                ],
+                "summary": "Post Stocktake",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stocktake ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/stocktakes/{id}/start": {
+            "post": {
+                "description": "Start counting a draft Stocktake. The current stock of every ingredient is taken as its theoretical stock.",
+                "tags": [
+                    "Stocktakes"
+                ],
+                "summary": "Start Stocktake",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stocktake ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/stocktakes/{id}/variance": {
+            "get": {
+                "description": "Variance per ingredient with its value at unit cost and percentage of the theoretical stock",
+                "tags": [
+                    "Stocktakes"
+                ],
+                "summary": "Stocktake Variance Report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stocktake ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/suppliers": {
+            "get": {
+                "description": "Get Suppliers with pagination, sorting and filters",
+                "tags": [
+                    "Suppliers"
+                ],
+                "summary": "Get Suppliers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the last item of the previous page (sort by id only)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id, name, lead_time_days, created_at; prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by name or contact name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only suppliers of this ingredient",
+                        "name": "ingredient_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create Supplier",
+                "tags": [
+                    "Suppliers"
+                ],
+                "summary": "Create Supplier",
+                "parameters": [
+                    {
+                        "description": "Create supplier",
+                        "name": "supplier",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SupplierRequest"
+                        }
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/suppliers/{id}": {
+            "get": {
+                "description": "Get Supplier by ID with the ingredients it sells",
+                "tags": [
+                    "Suppliers"
+                ],
+                "summary": "Get Supplier",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Supplier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Update Supplier by ID",
+                "tags": [
+                    "Suppliers"
+                ],
+                "summary": "Update Supplier",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Supplier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update supplier",
+                        "name": "supplier",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SupplierRequest"
+                        }
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete Supplier by ID together with its ingredient links",
+                "tags": [
+                    "Suppliers"
+                ],
+                "summary": "Delete Supplier",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Supplier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/suppliers/{id}/ingredients/{ingredientId}": {
+            "put": {
+                "description": "Add or update the SKU, pack size, pack unit and last price of an ingredient sold by the supplier",
+                "tags": [
+                    "Suppliers"
+                ],
+                "summary": "Link Ingredient to Supplier",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Supplier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ingredient ID",
+                        "name": "ingredientId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Supplier ingredient",
+                        "name": "ingredient",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SupplierIngredientRequest"
+                        }
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Remove an ingredient from the supplier",
+                "tags": [
+                    "Suppliers"
+                ],
+                "summary": "Unlink Ingredient from Supplier",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Supplier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ingredient ID",
+                        "name": "ingredientId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/transactions": {
+            "get": {
+                "description": "Get Transactions with optional date filter (default: this week), pagination, sorting and filters",
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Get Transactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the last item of the previous page (sort by id only)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id, transaction_date, total_amount, created_at; prefix with - for descending (default -id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status (completed, cancelled, partially_refunded, refunded)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only transactions containing this menu",
+                        "name": "menu_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum total amount",
+                        "name": "min_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum total amount",
+                        "name": "max_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create Transaction",
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Create Transaction",
+                "parameters": [
+                    {
+                        "description": "Create transaction",
+                        "name": "transaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TransactionCreateRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key per order, retries with the same key return the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/transactions/preview": {
+            "post": {
+                "description": "Check an order before checkout: total, ingredients needed across all items and the ones short in stock. Nothing is saved.",
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Preview Transaction",
+                "parameters": [
+                    {
+                        "description": "Order to check",
+                        "name": "transaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TransactionCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TransactionPreview"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/transactions/{id}": {
+            "get": {
+                "description": "Get Transaction by ID",
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Get Transaction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a completed Transaction by ID (and restore stock). Prefer cancel or refund to keep the record.",
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Delete Transaction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/transactions/{id}/cancel": {
+            "post": {
+                "description": "Void a completed Transaction, keep its record and restore all ingredient stock",
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Cancel Transaction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Cancel transaction",
+                        "name": "cancel",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TransactionCancelRequest"
+                        }
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/transactions/{id}/refund": {
+            "post": {
+                "description": "Refund selected items or quantities of a Transaction. Stock is only restored for items marked restock (not prepared yet).",
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Refund Transaction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Refund transaction",
+                        "name": "refund",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TransactionRefundRequest"
+                        }
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/units": {
+            "get": {
+                "description": "Get Units with pagination, sorting and filters",
+                "tags": [
+                    "Units"
+                ],
+                "summary": "Get Units",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the last item of the previous page (sort by id only)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id, name, dimension, created_at; prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by name or symbol",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Dimension (mass, volume, count, length)",
+                        "name": "dimension",
+                        "in": "query"
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Post Units",
+                "tags": [
+                    "Units"
+                ],
+                "summary": "Post Units",
+                "parameters": [
+                    {
+                        "description": "Unit data",
+                        "name": "unit",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UnitParamRequest"
+                        }
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/units/{id}": {
+            "put": {
+                "description": "Update an existing unit by ID",
+                "tags": [
+                    "Units"
+                ],
+                "summary": "Update Unit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Unit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated unit data",
+                        "name": "unit",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UnitParamRequest"
+                        }
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a unit by ID",
+                "tags": [
+                    "Units"
+                ],
+                "summary": "Delete Unit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "Get Users with pagination, sorting and filters",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get Users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the last item of the previous page (sort by id only)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id, name, email, created_at; prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by name or email",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Role (owner, manager, cashier, kitchen)",
+                        "name": "role",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/role": {
+            "put": {
+                "description": "Assign a role (owner, manager, cashier, kitchen) to a user",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update User Role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserRoleUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/waste": {
+            "get": {
+                "description": "Get waste records with pagination, sorting and filters",
+                "tags": [
+                    "Waste"
+                ],
+                "summary": "Get Waste Records",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the last item of the previous page (sort by id only)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id, wasted_at, reason, quantity; prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason (expired, damaged, staff_meal, comp, other)",
+                        "name": "reason",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ingredient ID",
+                        "name": "ingredient_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Record spoiled, damaged, staff meal or complimentary ingredients and deduct them from stock",
+                "tags": [
+                    "Waste"
+                ],
+                "summary": "Record Waste",
+                "parameters": [
+                    {
+                        "description": "Waste",
+                        "name": "waste",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.WasteCreateRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key per record, retries with the same key return the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/waste/report": {
+            "get": {
+                "description": "Waste quantity and value by reason, ingredient and date",
+                "tags": [
+                    "Waste"
+                ],
+                "summary": "Waste Report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason (expired, damaged, staff_meal, comp, other)",
+                        "name": "reason",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ingredient ID",
+                        "name": "ingredient_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {},
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        }
+    },
+    "definitions": {
+        "dto.AuthData": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/dto.UserData"
+                }
+            }
+        },
+        "dto.AuthResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.AuthData"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GoodsReceiptCreateRequest": {
+            "type": "object",
+            "required": [
+                "items",
+                "purchase_order_id"
+            ],
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.GoodsReceiptItemRequest"
+                    }
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "purchase_order_id": {
+                    "type": "integer"
+                },
+                "received_date": {
+                    "description": "YYYY-MM-DD, default hari ini",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GoodsReceiptItemRequest": {
+            "type": "object",
+            "required": [
+                "purchase_order_line_id",
+                "quantity"
+            ],
+            "properties": {
+                "expiry_date": {
+                    "description": "YYYY-MM-DD, kosong = tidak kedaluwarsa",
+                    "type": "string"
+                },
+                "lot_number": {
+                    "type": "string"
+                },
+                "purchase_order_line_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.GoogleAuthRequest": {
+            "type": "object",
+            "required": [
+                "id_token"
+            ],
+            "properties": {
+                "email": {
+                    "description": "Optional, must match the token email",
+                    "type": "string"
+                },
+                "id_token": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "photo_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.IngredientConversionRequest": {
+            "type": "object",
+            "required": [
+                "quantity",
+                "to_unit_id",
+                "unit_id"
+            ],
+            "properties": {
+                "quantity": {
+                    "type": "number"
+                },
+                "to_unit_id": {
+                    "type": "integer"
+                },
+                "unit_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.IngredientDetail": {
+            "type": "object",
+            "properties": {
+                "cost_method": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "minimum_stock": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "par_level": {
+                    "type": "number"
+                },
+                "reorder_point": {
+                    "type": "number"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "number"
+                },
+                "suppliers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.SupplierIngredient"
+                    }
+                },
+                "unit": {
+                    "$ref": "#/definitions/dto.Unit"
+                },
+                "unit_cost": {
+                    "type": "number"
+                },
+                "unit_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.IngredientParamRequest": {
+            "type": "object",
+            "properties": {
+                "cost_method": {
+                    "description": "Default: average",
+                    "type": "string",
+                    "enum": [
+                        "average",
+                        "last_purchase"
+                    ]
+                },
+                "minimum_stock": {
+                    "description": "Kosongkan untuk mempertahankan nilai",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string"
+                },
+                "par_level": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "reorder_point": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "stock": {
+                    "description": "Kosongkan untuk mempertahankan stok, perubahan dicatat di ledger",
+                    "type": "number"
+                },
+                "unit_cost": {
+                    "description": "Kosongkan untuk mempertahankan harga pokok",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "unit_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.MenuIngredientRequest": {
+            "type": "object",
+            "required": [
+                "ingredient_id",
+                "quantity",
+                "unit_id"
+            ],
+            "properties": {
+                "ingredient_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "unit_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.MenuModifierGroupRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "options"
+            ],
+            "properties": {
+                "max_select": {
+                    "description": "0 = tidak dibatasi",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "min_select": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.MenuModifierOptionRequest"
+                    }
+                }
+            }
+        },
+        "dto.MenuModifierOptionRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "ingredients": {
+                    "description": "Quantity negatif = dikurangi dari resep",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.MenuIngredientRequest"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price_delta": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.MenuModifiersRequest": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.MenuModifierGroupRequest"
+                    }
+                }
+            }
+        },
+        "dto.MenuSoldOutRequest": {
+            "type": "object",
+            "required": [
+                "sold_out"
+            ],
+            "properties": {
+                "sold_out": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.PurchaseOrderCreateRequest": {
+            "type": "object",
+            "required": [
+                "lines"
+            ],
+            "properties": {
+                "expected_date": {
+                    "description": "YYYY-MM-DD (opsional)",
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.PurchaseOrderLineRequest"
+                    }
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "order_date": {
+                    "description": "YYYY-MM-DD, default hari ini",
+                    "type": "string"
+                },
+                "supplier_id": {
+                    "description": "Supplier tujuan (opsional)",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.PurchaseOrderLineRequest": {
+            "type": "object",
+            "required": [
+                "ingredient_id",
+                "quantity",
+                "unit_id"
+            ],
+            "properties": {
+                "ingredient_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "unit_cost": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "unit_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ReorderDraftRequest": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "description": "Default 28 hari",
+                    "type": "integer",
+                    "maximum": 365,
+                    "minimum": 1
+                },
+                "ingredient_ids": {
+                    "description": "Kosongkan untuk semua saran",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "dto.StockMovementCreateRequest": {
+            "type": "object",
+            "required": [
+                "quantity",
+                "type"
+            ],
+            "properties": {
+                "notes": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "description": "Positif = tambah, negatif = kurang",
+                    "type": "number"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "adjustment",
+                        "transfer"
+                    ]
+                }
+            }
+        },
+        "dto.StockReductionIngredient": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.StockReductionUnit": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.StocktakeCountItem": {
+            "type": "object",
+            "required": [
+                "counted_quantity",
+                "ingredient_id"
+            ],
+            "properties": {
+                "counted_quantity": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "ingredient_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.StocktakeCountRequest": {
+            "type": "object",
+            "required": [
+                "counts"
+            ],
+            "properties": {
+                "counts": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.StocktakeCountItem"
+                    }
+                }
             }
         },
-        "/users": {
-            "get": {
-                "description": "Get Users",
-                "tags": [
-                    "Users"
-                ],
-                "summary": "Get Users",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
+        "dto.StocktakeCreateRequest": {
+            "type": "object",
+            "properties": {
+                "ingredient_ids": {
+                    "description": "Kosong = semua ingredient",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
                     }
+                },
+                "notes": {
+                    "type": "string"
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.AuthData": {
+        },
+        "dto.SupplierIngredient": {
             "type": "object",
             "properties": {
-                "token": {
+                "id": {
+                    "type": "integer"
+                },
+                "ingredient": {
+                    "$ref": "#/definitions/dto.StockReductionIngredient"
+                },
+                "last_price": {
+                    "description": "Per kemasan",
+                    "type": "number"
+                },
+                "last_price_at": {
                     "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/dto.UserData"
+                "pack_size": {
+                    "type": "number"
+                },
+                "pack_unit": {
+                    "$ref": "#/definitions/dto.StockReductionUnit"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "supplier": {
+                    "$ref": "#/definitions/dto.SupplierSummary"
+                },
+                "unit_price": {
+                    "description": "Per unit stok ingredient",
+                    "type": "number"
                 }
             }
         },
-        "dto.AuthResponse": {
+        "dto.SupplierIngredientRequest": {
             "type": "object",
+            "required": [
+                "pack_size",
+                "pack_unit_id"
+            ],
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/dto.AuthData"
+                "last_price": {
+                    "description": "Harga per kemasan",
+                    "type": "number",
+                    "minimum": 0
                 },
-                "message": {
-                    "type": "string"
+                "pack_size": {
+                    "type": "number"
                 },
-                "status": {
+                "pack_unit_id": {
+                    "type": "integer"
+                },
+                "sku": {
                     "type": "string"
                 }
             }
         },
-        "dto.GoogleAuthRequest": {
+        "dto.SupplierRequest": {
             "type": "object",
             "required": [
-                "email",
-                "id_token"
+                "name"
             ],
             "properties": {
-                "email": {
+                "address": {
                     "type": "string"
                 },
-                "id_token": {
+                "contact_name": {
                     "type": "string"
                 },
+                "email": {
+                    "type": "string"
+                },
+                "lead_time_days": {
+                    "type": "integer",
+                    "minimum": 0
+                },
                 "name": {
                     "type": "string"
                 },
-                "photo_url": {
+                "notes": {
+                    "type": "string"
+                },
+                "payment_terms": {
+                    "type": "string"
+                },
+                "phone": {
                     "type": "string"
                 }
             }
         },
-        "dto.IngredientParamRequest": {
+        "dto.SupplierSummary": {
             "type": "object",
             "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "lead_time_days": {
+                    "type": "integer"
+                },
                 "name": {
                     "type": "string"
                 },
-                "stock": {
-                    "type": "number"
-                },
-                "unit_id": {
-                    "type": "integer"
+                "payment_terms": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TransactionCancelRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string"
                 }
             }
         },
@@ -696,6 +2954,43 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.TransactionItemMenu": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TransactionItemModifier": {
+            "type": "object",
+            "properties": {
+                "group_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "modifier_option_id": {
+                    "type": "integer"
+                },
+                "option_name": {
+                    "type": "string"
+                },
+                "price_delta": {
+                    "type": "number"
+                }
+            }
+        },
         "dto.TransactionItemRequest": {
             "type": "object",
             "required": [
@@ -706,15 +3001,185 @@ const docTemplate = `{
                 "menu_id": {
                     "type": "integer"
                 },
+                "modifier_option_ids": {
+                    "description": "Pilihan modifier per porsi",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "quantity": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "dto.TransactionPreview": {
+            "type": "object",
+            "properties": {
+                "cost": {
+                    "description": "Harga pokok seluruh pesanan",
+                    "type": "number"
+                },
+                "feasible": {
+                    "description": "Semua menu tersedia dan stok mencukupi",
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TransactionPreviewItem"
+                    }
+                },
+                "requirements": {
+                    "description": "Kebutuhan per ingredient dari seluruh item",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TransactionPreviewRequirement"
+                    }
+                },
+                "shortages": {
+                    "description": "Ingredient yang stoknya kurang",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TransactionPreviewRequirement"
+                    }
+                },
+                "total_amount": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.TransactionPreviewItem": {
+            "type": "object",
+            "properties": {
+                "menu": {
+                    "$ref": "#/definitions/dto.TransactionItemMenu"
+                },
+                "modifiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TransactionItemModifier"
+                    }
+                },
+                "price": {
+                    "description": "Harga per porsi termasuk modifier",
+                    "type": "number"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "sold_out": {
+                    "type": "boolean"
+                },
+                "subtotal": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.TransactionPreviewRequirement": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "number"
+                },
+                "ingredient": {
+                    "$ref": "#/definitions/dto.StockReductionIngredient"
+                },
+                "required": {
+                    "type": "number"
+                },
+                "shortage": {
+                    "type": "number"
+                },
+                "unit": {
+                    "$ref": "#/definitions/dto.StockReductionUnit"
+                }
+            }
+        },
+        "dto.TransactionRefundItemRequest": {
+            "type": "object",
+            "required": [
+                "quantity",
+                "transaction_item_id"
+            ],
+            "properties": {
                 "quantity": {
                     "type": "integer",
                     "minimum": 1
+                },
+                "restock": {
+                    "description": "true jika item belum dibuat sehingga bahan bisa dikembalikan ke stok",
+                    "type": "boolean"
+                },
+                "transaction_item_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.TransactionRefundRequest": {
+            "type": "object",
+            "required": [
+                "items",
+                "reason"
+            ],
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.TransactionRefundItemRequest"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Unit": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "dimension": {
+                    "type": "string"
+                },
+                "factor": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
         "dto.UnitParamRequest": {
             "type": "object",
             "properties": {
+                "dimension": {
+                    "type": "string",
+                    "enum": [
+                        "mass",
+                        "volume",
+                        "count",
+                        "length"
+                    ]
+                },
+                "factor": {
+                    "type": "number"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -737,9 +3202,70 @@ const docTemplate = `{
                 },
                 "photo_url": {
                     "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UserRoleUpdateRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "owner",
+                        "manager",
+                        "cashier",
+                        "kitchen"
+                    ]
+                }
+            }
+        },
+        "dto.WasteCreateRequest": {
+            "type": "object",
+            "required": [
+                "ingredient_id",
+                "quantity",
+                "reason",
+                "unit_id"
+            ],
+            "properties": {
+                "ingredient_id": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "reason": {
+                    "type": "string",
+                    "enum": [
+                        "expired",
+                        "damaged",
+                        "staff_meal",
+                        "comp",
+                        "other"
+                    ]
+                },
+                "unit_id": {
+                    "description": "Unit jumlah, harus bisa dikonversi ke unit ingredient",
+                    "type": "integer"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
@@ -2,7 +2,7 @@ package dto
 
 type GoogleAuthRequest struct {
 	IDToken  string `json:"id_token" binding:"required"`
-	Email    string `json:"email" binding:"required,email"`
+	Email    string `json:"email" binding:"omitempty,email"` // Optional, must match the token email
 	Name     string `json:"name"`
 	PhotoURL string `json:"photo_url"`
 }
@@ -23,4 +23,9 @@ type UserData struct {
 	Email    string `json:"email"`
 	Name     string `json:"name"`
 	PhotoURL string `json:"photo_url"`
+	Role     string `json:"role"`
+}
+
+type UserRoleUpdateRequest struct {
+	Role string `json:"role" binding:"required,oneof=owner manager cashier kitchen"`
 }
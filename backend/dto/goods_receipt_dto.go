@@ -0,0 +1,44 @@
+package dto
+
+import "time"
+
+// Goods Receipt DTOs
+type GoodsReceipt struct {
+	ID              uint               `json:"id"`
+	ReceiptNumber   string             `json:"receipt_number"`
+	ReceivedDate    time.Time          `json:"received_date"`
+	Notes           string             `json:"notes"`
+	PurchaseOrderID uint               `json:"purchase_order_id"`
+	PONumber        string             `json:"po_number"`
+	Items           []GoodsReceiptItem `json:"items"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+}
+
+type GoodsReceiptItem struct {
+	ID                  uint                        `json:"id"`
+	PurchaseOrderLineID uint                        `json:"purchase_order_line_id"`
+	Ingredient          PurchaseOrderLineIngredient `json:"ingredient"`
+	QuantityReceived    float64                     `json:"quantity_received"`
+	ReceivedUnit        PurchaseOrderLineUnit       `json:"received_unit"`
+	UnitCost            float64                     `json:"unit_cost"`
+	QuantityAdded       float64                     `json:"quantity_added"`
+	StockBefore         float64                     `json:"stock_before"`
+	StockAfter          float64                     `json:"stock_after"`
+	Unit                PurchaseOrderLineUnit       `json:"unit"`
+}
+
+// Request DTOs
+type GoodsReceiptCreateRequest struct {
+	PurchaseOrderID uint                      `json:"purchase_order_id" binding:"required"`
+	ReceivedDate    string                    `json:"received_date"` // YYYY-MM-DD, default hari ini
+	Notes           string                    `json:"notes"`
+	Items           []GoodsReceiptItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+type GoodsReceiptItemRequest struct {
+	PurchaseOrderLineID uint    `json:"purchase_order_line_id" binding:"required"`
+	Quantity            float64 `json:"quantity" binding:"required,gt=0"`
+	ExpiryDate          string  `json:"expiry_date"` // YYYY-MM-DD, kosong = tidak kedaluwarsa
+	LotNumber           string  `json:"lot_number"`
+}
@@ -5,19 +5,59 @@ import (
 )
 
 type Ingredient struct {
-	ID        uint      `json:"id"`
-	Name      string    `json:"name"`
-	Slug      string    `json:"slug"`
-	Stock     float64   `json:"stock"`
-	UnitID    uint      `json:"unit_id"`
-	Unit      Unit      `json:"unit"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	DeletedAt time.Time `json:"deleted_at"`
+	ID           uint      `json:"id"`
+	Name         string    `json:"name"`
+	Slug         string    `json:"slug"`
+	Stock        float64   `json:"stock"`
+	UnitID       uint      `json:"unit_id"`
+	Unit         Unit      `json:"unit"`
+	UnitCost     float64   `json:"unit_cost"`
+	CostMethod   string    `json:"cost_method"`
+	MinimumStock float64   `json:"minimum_stock"`
+	ReorderPoint float64   `json:"reorder_point"`
+	ParLevel     float64   `json:"par_level"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	DeletedAt    time.Time `json:"deleted_at"`
 }
 
 type IngredientParamRequest struct {
-	Name   string  `json:"name"`
-	Stock  float64 `json:"stock"`
-	UnitID uint    `json:"unit_id"`
+	Name         string   `json:"name"`
+	Stock        *float64 `json:"stock"` // Kosongkan untuk mempertahankan stok, perubahan dicatat di ledger
+	UnitID       uint     `json:"unit_id"`
+	UnitCost     *float64 `json:"unit_cost" binding:"omitempty,gte=0"`                         // Kosongkan untuk mempertahankan harga pokok
+	CostMethod   string   `json:"cost_method" binding:"omitempty,oneof=average last_purchase"` // Default: average
+	MinimumStock *float64 `json:"minimum_stock" binding:"omitempty,gte=0"`                     // Kosongkan untuk mempertahankan nilai
+	ReorderPoint *float64 `json:"reorder_point" binding:"omitempty,gte=0"`
+	ParLevel     *float64 `json:"par_level" binding:"omitempty,gte=0"`
+}
+
+type LowStockIngredient struct {
+	ID           uint    `json:"id"`
+	Name         string  `json:"name"`
+	Slug         string  `json:"slug"`
+	Stock        float64 `json:"stock"`
+	Unit         Unit    `json:"unit"`
+	MinimumStock float64 `json:"minimum_stock"`
+	ReorderPoint float64 `json:"reorder_point"`
+	ParLevel     float64 `json:"par_level"`
+	BelowMinimum bool    `json:"below_minimum"`
+	Shortage     float64 `json:"shortage"` // Jumlah yang perlu dipesan untuk kembali ke par level
+}
+
+type IngredientConversion struct {
+	ID           uint    `json:"id"`
+	IngredientID uint    `json:"ingredient_id"`
+	Quantity     float64 `json:"quantity"`
+	UnitID       uint    `json:"unit_id"`
+	Unit         Unit    `json:"unit"`
+	ToUnitID     uint    `json:"to_unit_id"`
+	ToUnit       Unit    `json:"to_unit"`
+}
+
+// 1 UnitID = Quantity ToUnitID, contoh: 1 pcs = 60 g
+type IngredientConversionRequest struct {
+	UnitID   uint    `json:"unit_id" binding:"required"`
+	Quantity float64 `json:"quantity" binding:"required,gt=0"`
+	ToUnitID uint    `json:"to_unit_id" binding:"required"`
 }
@@ -7,16 +7,23 @@ import "time"
 //
 
 type Menu struct {
-	ID          uint             `json:"id"`
-	Name        string           `json:"name"`
-	Slug        string           `json:"slug"`
-	Image       string           `json:"image"`
-	Price       float64          `json:"price"`
-	Description string           `json:"description"`
-	Ingredients []MenuIngredient `json:"ingredients"`
-	CreatedAt   time.Time        `json:"created_at"`
-	UpdatedAt   time.Time        `json:"updated_at"`
-	DeletedAt   *time.Time       `json:"deleted_at,omitempty"`
+	ID                 uint                `json:"id"`
+	Name               string              `json:"name"`
+	Slug               string              `json:"slug"`
+	Image              string              `json:"image"`
+	Price              float64             `json:"price"`
+	Cost               float64             `json:"cost"`                 // Harga pokok resep per porsi
+	GrossMargin        float64             `json:"gross_margin"`         // Price - Cost
+	FoodCostPercentage float64             `json:"food_cost_percentage"` // Cost / Price * 100
+	SoldOut            bool                `json:"sold_out"`             // Ditandai habis (86) secara manual
+	AvailablePortions  *int                `json:"available_portions"`   // Porsi yang bisa dibuat dari stok saat ini
+	Available          bool                `json:"available"`            // Tidak habis dan minimal 1 porsi
+	Description        string              `json:"description"`
+	Ingredients        []MenuIngredient    `json:"ingredients"`
+	ModifierGroups     []MenuModifierGroup `json:"modifier_groups"`
+	CreatedAt          time.Time           `json:"created_at"`
+	UpdatedAt          time.Time           `json:"updated_at"`
+	DeletedAt          *time.Time          `json:"deleted_at,omitempty"`
 }
 
 type MenuIngredient struct {
@@ -26,6 +33,27 @@ type MenuIngredient struct {
 	Unit       MenuIngredientUnit       `json:"unit"`
 }
 
+type MenuModifierGroup struct {
+	ID        uint                 `json:"id"`
+	Name      string               `json:"name"`
+	MinSelect int                  `json:"min_select"` // > 0 berarti wajib dipilih
+	MaxSelect int                  `json:"max_select"` // 0 = tidak dibatasi
+	Options   []MenuModifierOption `json:"options"`
+}
+
+type MenuModifierOption struct {
+	ID          uint                     `json:"id"`
+	Name        string                   `json:"name"`
+	PriceDelta  float64                  `json:"price_delta"`
+	Ingredients []MenuModifierIngredient `json:"ingredients"` // Perubahan resep per porsi
+}
+
+type MenuModifierIngredient struct {
+	Ingredient MenuIngredientIngredient `json:"ingredient"`
+	Quantity   float64                  `json:"quantity"` // Negatif = dikurangi dari resep
+	Unit       MenuIngredientUnit       `json:"unit"`
+}
+
 type MenuIngredientIngredient struct {
 	ID   uint   `json:"id"`
 	Name string `json:"name"`
@@ -58,8 +86,30 @@ type MenuUpdateRequest struct {
 	Ingredients []MenuIngredientRequest `json:"ingredients" binding:"required"`
 }
 
+type MenuSoldOutRequest struct {
+	SoldOut *bool `json:"sold_out" binding:"required"`
+}
+
 type MenuIngredientRequest struct {
 	IngredientID uint    `json:"ingredient_id" binding:"required"`
 	Quantity     float64 `json:"quantity" binding:"required"`
 	UnitID       uint    `json:"unit_id" binding:"required"`
 }
+
+// MenuModifiersRequest mengganti seluruh kelompok modifier menu
+type MenuModifiersRequest struct {
+	Groups []MenuModifierGroupRequest `json:"groups" binding:"dive"`
+}
+
+type MenuModifierGroupRequest struct {
+	Name      string                      `json:"name" binding:"required"`
+	MinSelect int                         `json:"min_select" binding:"gte=0"`
+	MaxSelect int                         `json:"max_select" binding:"gte=0"` // 0 = tidak dibatasi
+	Options   []MenuModifierOptionRequest `json:"options" binding:"required,min=1,dive"`
+}
+
+type MenuModifierOptionRequest struct {
+	Name        string                  `json:"name" binding:"required"`
+	PriceDelta  float64                 `json:"price_delta"`
+	Ingredients []MenuIngredientRequest `json:"ingredients" binding:"dive"` // Quantity negatif = dikurangi dari resep
+}
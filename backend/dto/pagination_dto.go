@@ -0,0 +1,12 @@
+package dto
+
+// PageMeta is returned as "meta" next to "data" by paginated list endpoints
+type PageMeta struct {
+	Page       int    `json:"page,omitempty"` // kosong jika memakai cursor
+	Limit      int    `json:"limit"`
+	Total      int64  `json:"total"`
+	TotalPages int    `json:"total_pages"`
+	Sort       string `json:"sort"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"` // hanya jika diurutkan berdasarkan id
+}
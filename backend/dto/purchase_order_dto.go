@@ -0,0 +1,55 @@
+package dto
+
+import "time"
+
+// Purchase Order DTOs
+type PurchaseOrder struct {
+	ID           uint                `json:"id"`
+	PONumber     string              `json:"po_number"`
+	Supplier     *SupplierSummary    `json:"supplier"`
+	OrderDate    time.Time           `json:"order_date"`
+	ExpectedDate *time.Time          `json:"expected_date"`
+	Status       string              `json:"status"`
+	TotalAmount  float64             `json:"total_amount"`
+	Notes        string              `json:"notes"`
+	Lines        []PurchaseOrderLine `json:"lines"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+}
+
+type PurchaseOrderLine struct {
+	ID               uint                        `json:"id"`
+	Ingredient       PurchaseOrderLineIngredient `json:"ingredient"`
+	Quantity         float64                     `json:"quantity"`
+	ReceivedQuantity float64                     `json:"received_quantity"`
+	Unit             PurchaseOrderLineUnit       `json:"unit"`
+	UnitCost         float64                     `json:"unit_cost"`
+	Subtotal         float64                     `json:"subtotal"`
+}
+
+type PurchaseOrderLineIngredient struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type PurchaseOrderLineUnit struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// Request DTOs
+type PurchaseOrderCreateRequest struct {
+	SupplierID   *uint                      `json:"supplier_id"`   // Supplier tujuan (opsional)
+	OrderDate    string                     `json:"order_date"`    // YYYY-MM-DD, default hari ini
+	ExpectedDate string                     `json:"expected_date"` // YYYY-MM-DD (opsional)
+	Notes        string                     `json:"notes"`
+	Lines        []PurchaseOrderLineRequest `json:"lines" binding:"required,min=1,dive"`
+}
+
+type PurchaseOrderLineRequest struct {
+	IngredientID uint    `json:"ingredient_id" binding:"required"`
+	Quantity     float64 `json:"quantity" binding:"required,gt=0"`
+	UnitID       uint    `json:"unit_id" binding:"required"`
+	UnitCost     float64 `json:"unit_cost" binding:"gte=0"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+// ReorderSuggestion adalah saran pemesanan ulang satu ingredient.
+// Quantity dalam unit stok ingredient kecuali OrderQuantity (dalam OrderUnit).
+type ReorderSuggestion struct {
+	Ingredient        PurchaseOrderLineIngredient `json:"ingredient"`
+	Unit              PurchaseOrderLineUnit       `json:"unit"`
+	Stock             float64                     `json:"stock"`
+	OnOrder           float64                     `json:"on_order"`
+	AverageDailyUsage float64                     `json:"average_daily_usage"`
+	LeadTimeDays      int                         `json:"lead_time_days"`
+	StockAtDelivery   float64                     `json:"stock_at_delivery"`
+	MinimumStock      float64                     `json:"minimum_stock"`
+	ReorderPoint      float64                     `json:"reorder_point"`
+	ParLevel          float64                     `json:"par_level"`
+	SuggestedQuantity float64                     `json:"suggested_quantity"`
+	Supplier          *SupplierSummary            `json:"supplier"`
+	OrderQuantity     float64                     `json:"order_quantity"`
+	OrderUnit         PurchaseOrderLineUnit       `json:"order_unit"`
+	Packs             int                         `json:"packs"`
+	UnitCost          float64                     `json:"unit_cost"` // Per OrderUnit
+	EstimatedCost     float64                     `json:"estimated_cost"`
+}
+
+// Request DTOs
+type ReorderDraftRequest struct {
+	Days          int    `json:"days" binding:"omitempty,min=1,max=365"` // Default 28 hari
+	IngredientIDs []uint `json:"ingredient_ids"`                         // Kosongkan untuk semua saran
+}
@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+type StockAlert struct {
+	ID               uint                 `json:"id"`
+	Ingredient       StockAlertIngredient `json:"ingredient"`
+	Level            string               `json:"level"`
+	Stock            float64              `json:"stock"`
+	Threshold        float64              `json:"threshold"`
+	StockMovementID  uint                 `json:"stock_movement_id"`
+	AcknowledgedAt   *time.Time           `json:"acknowledged_at"`
+	AcknowledgedByID *uint                `json:"acknowledged_by_id"`
+	CreatedAt        time.Time            `json:"created_at"`
+}
+
+type StockAlertIngredient struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+// Stock Lot DTOs
+type StockLot struct {
+	ID                 uint                     `json:"id"`
+	Ingredient         StockReductionIngredient `json:"ingredient"`
+	LotNumber          string                   `json:"lot_number"`
+	ReceivedDate       time.Time                `json:"received_date"`
+	ExpiryDate         *time.Time               `json:"expiry_date"`
+	DaysUntilExpiry    *int                     `json:"days_until_expiry"` // Negatif = sudah kedaluwarsa
+	Expired            bool                     `json:"expired"`
+	Quantity           float64                  `json:"quantity"`
+	Remaining          float64                  `json:"remaining"`
+	Unit               StockReductionUnit       `json:"unit"`
+	GoodsReceiptItemID *uint                    `json:"goods_receipt_item_id"`
+}
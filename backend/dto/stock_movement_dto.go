@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+// Stock Movement DTOs
+type StockMovement struct {
+	ID            uint              `json:"id"`
+	IngredientID  uint              `json:"ingredient_id"`
+	Type          string            `json:"type"`
+	Quantity      float64           `json:"quantity"`
+	StockBefore   float64           `json:"stock_before"`
+	StockAfter    float64           `json:"stock_after"`
+	Unit          StockMovementUnit `json:"unit"`
+	UserID        *uint             `json:"user_id"`
+	ReferenceType string            `json:"reference_type"`
+	ReferenceID   uint              `json:"reference_id"`
+	Notes         string            `json:"notes"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+type StockMovementUnit struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// Request DTOs
+type StockMovementCreateRequest struct {
+	Type     string  `json:"type" binding:"required,oneof=adjustment transfer"`
+	Quantity float64 `json:"quantity" binding:"required"` // Positif = tambah, negatif = kurang
+	Notes    string  `json:"notes"`
+}
@@ -0,0 +1,59 @@
+package dto
+
+import "time"
+
+// Stocktake DTOs
+type Stocktake struct {
+	ID          uint            `json:"id"`
+	Code        string          `json:"code"`
+	Status      string          `json:"status"`
+	Notes       string          `json:"notes"`
+	StartedAt   *time.Time      `json:"started_at"`
+	PostedAt    *time.Time      `json:"posted_at"`
+	CreatedByID *uint           `json:"created_by_id"`
+	PostedByID  *uint           `json:"posted_by_id"`
+	Lines       []StocktakeLine `json:"lines"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+type StocktakeLine struct {
+	ID                 uint                     `json:"id"`
+	Ingredient         StockReductionIngredient `json:"ingredient"`
+	Unit               StockReductionUnit       `json:"unit"`
+	TheoreticalStock   float64                  `json:"theoretical_stock"`
+	CountedQuantity    *float64                 `json:"counted_quantity"`
+	Variance           float64                  `json:"variance"`
+	VarianceValue      float64                  `json:"variance_value"`
+	VariancePercentage *float64                 `json:"variance_percentage"`
+	UnitCost           float64                  `json:"unit_cost"`
+	StockMovementID    *uint                    `json:"stock_movement_id"`
+}
+
+// StocktakeVarianceReport merangkum selisih hitungan per ingredient
+type StocktakeVarianceReport struct {
+	ID                 uint            `json:"id"`
+	Code               string          `json:"code"`
+	Status             string          `json:"status"`
+	StartedAt          *time.Time      `json:"started_at"`
+	PostedAt           *time.Time      `json:"posted_at"`
+	CountedLines       int             `json:"counted_lines"`
+	UncountedLines     int             `json:"uncounted_lines"`
+	TotalVarianceValue float64         `json:"total_variance_value"` // Negatif = stok hilang
+	Lines              []StocktakeLine `json:"lines"`
+}
+
+// Request DTOs
+type StocktakeCreateRequest struct {
+	Notes         string `json:"notes"`
+	IngredientIDs []uint `json:"ingredient_ids"` // Kosong = semua ingredient
+}
+
+type StocktakeCountRequest struct {
+	Counts []StocktakeCountItem `json:"counts" binding:"required,min=1,dive"`
+}
+
+type StocktakeCountItem struct {
+	IngredientID    uint     `json:"ingredient_id" binding:"required"`
+	CountedQuantity *float64 `json:"counted_quantity" binding:"required,gte=0"`
+}
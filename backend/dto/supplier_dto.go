@@ -0,0 +1,64 @@
+package dto
+
+import "time"
+
+// Supplier DTOs
+type Supplier struct {
+	ID           uint                 `json:"id"`
+	Name         string               `json:"name"`
+	ContactName  string               `json:"contact_name"`
+	Phone        string               `json:"phone"`
+	Email        string               `json:"email"`
+	Address      string               `json:"address"`
+	LeadTimeDays int                  `json:"lead_time_days"`
+	PaymentTerms string               `json:"payment_terms"`
+	Notes        string               `json:"notes"`
+	Ingredients  []SupplierIngredient `json:"ingredients,omitempty"`
+	CreatedAt    time.Time            `json:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at"`
+}
+
+// SupplierIngredient adalah kemasan ingredient yang dijual supplier
+type SupplierIngredient struct {
+	ID          uint                     `json:"id"`
+	Supplier    SupplierSummary          `json:"supplier"`
+	Ingredient  StockReductionIngredient `json:"ingredient"`
+	SKU         string                   `json:"sku"`
+	PackSize    float64                  `json:"pack_size"`
+	PackUnit    StockReductionUnit       `json:"pack_unit"`
+	LastPrice   float64                  `json:"last_price"` // Per kemasan
+	UnitPrice   *float64                 `json:"unit_price"` // Per unit stok ingredient
+	LastPriceAt *time.Time               `json:"last_price_at"`
+}
+
+type SupplierSummary struct {
+	ID           uint   `json:"id"`
+	Name         string `json:"name"`
+	LeadTimeDays int    `json:"lead_time_days"`
+	PaymentTerms string `json:"payment_terms"`
+}
+
+// IngredientDetail adalah ingredient beserta supplier-nya, termurah lebih dulu
+type IngredientDetail struct {
+	Ingredient
+	Suppliers []SupplierIngredient `json:"suppliers"`
+}
+
+// Request DTOs
+type SupplierRequest struct {
+	Name         string `json:"name" binding:"required"`
+	ContactName  string `json:"contact_name"`
+	Phone        string `json:"phone"`
+	Email        string `json:"email" binding:"omitempty,email"`
+	Address      string `json:"address"`
+	LeadTimeDays int    `json:"lead_time_days" binding:"gte=0"`
+	PaymentTerms string `json:"payment_terms"`
+	Notes        string `json:"notes"`
+}
+
+type SupplierIngredientRequest struct {
+	SKU        string  `json:"sku"`
+	PackSize   float64 `json:"pack_size" binding:"required,gt=0"`
+	PackUnitID uint    `json:"pack_unit_id" binding:"required"`
+	LastPrice  float64 `json:"last_price" binding:"gte=0"` // Harga per kemasan
+}
@@ -8,19 +8,34 @@ type Transaction struct {
 	TransactionCode string            `json:"transaction_code"`
 	TransactionDate time.Time         `json:"transaction_date"`
 	TotalAmount     float64           `json:"total_amount"`
+	RefundedAmount  float64           `json:"refunded_amount"`
 	Notes           string            `json:"notes"`
 	Status          string            `json:"status"`
+	CancelReason    string            `json:"cancel_reason,omitempty"`
+	CancelledAt     *time.Time        `json:"cancelled_at,omitempty"`
 	Items           []TransactionItem `json:"items"`
+	Refunds         []Refund          `json:"refunds"`
 	CreatedAt       time.Time         `json:"created_at"`
 	UpdatedAt       time.Time         `json:"updated_at"`
 }
 
 type TransactionItem struct {
-	ID              uint                `json:"id"`
-	Menu            TransactionItemMenu `json:"menu"`
-	Quantity        int                 `json:"quantity"`
-	Price           float64             `json:"price"`
-	StockReductions []StockReduction    `json:"stock_reductions"`
+	ID               uint                      `json:"id"`
+	Menu             TransactionItemMenu       `json:"menu"`
+	Quantity         int                       `json:"quantity"`
+	Price            float64                   `json:"price"` // Harga per porsi termasuk modifier
+	Cost             float64                   `json:"cost"`  // Harga pokok per porsi saat transaksi
+	RefundedQuantity int                       `json:"refunded_quantity"`
+	Modifiers        []TransactionItemModifier `json:"modifiers"`
+	StockReductions  []StockReduction          `json:"stock_reductions"`
+}
+
+type TransactionItemModifier struct {
+	ID               uint    `json:"id"`
+	ModifierOptionID uint    `json:"modifier_option_id"`
+	GroupName        string  `json:"group_name"`
+	OptionName       string  `json:"option_name"`
+	PriceDelta       float64 `json:"price_delta"`
 }
 
 type TransactionItemMenu struct {
@@ -37,6 +52,14 @@ type StockReduction struct {
 	StockBefore     float64                  `json:"stock_before"`
 	StockAfter      float64                  `json:"stock_after"`
 	Unit            StockReductionUnit       `json:"unit"`
+	Lots            []StockReductionLot      `json:"lots"` // Lot yang terpakai (FEFO)
+}
+
+type StockReductionLot struct {
+	StockLotID uint       `json:"stock_lot_id"`
+	LotNumber  string     `json:"lot_number"`
+	ExpiryDate *time.Time `json:"expiry_date"`
+	Quantity   float64    `json:"quantity"`
 }
 
 type StockReductionIngredient struct {
@@ -50,6 +73,50 @@ type StockReductionUnit struct {
 	Name string `json:"name"`
 }
 
+type Refund struct {
+	ID          uint         `json:"id"`
+	Reason      string       `json:"reason"`
+	TotalAmount float64      `json:"total_amount"`
+	UserID      *uint        `json:"user_id"`
+	Items       []RefundItem `json:"items"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+type RefundItem struct {
+	ID                uint    `json:"id"`
+	TransactionItemID uint    `json:"transaction_item_id"`
+	Quantity          int     `json:"quantity"`
+	Amount            float64 `json:"amount"`
+	Restocked         bool    `json:"restocked"`
+}
+
+// TransactionPreview adalah hasil pengecekan pesanan sebelum checkout
+type TransactionPreview struct {
+	TotalAmount  float64                         `json:"total_amount"`
+	Cost         float64                         `json:"cost"`     // Harga pokok seluruh pesanan
+	Feasible     bool                            `json:"feasible"` // Semua menu tersedia dan stok mencukupi
+	Items        []TransactionPreviewItem        `json:"items"`
+	Requirements []TransactionPreviewRequirement `json:"requirements"` // Kebutuhan per ingredient dari seluruh item
+	Shortages    []TransactionPreviewRequirement `json:"shortages"`    // Ingredient yang stoknya kurang
+}
+
+type TransactionPreviewItem struct {
+	Menu      TransactionItemMenu       `json:"menu"`
+	Quantity  int                       `json:"quantity"`
+	Price     float64                   `json:"price"` // Harga per porsi termasuk modifier
+	Subtotal  float64                   `json:"subtotal"`
+	SoldOut   bool                      `json:"sold_out"`
+	Modifiers []TransactionItemModifier `json:"modifiers"`
+}
+
+type TransactionPreviewRequirement struct {
+	Ingredient StockReductionIngredient `json:"ingredient"`
+	Unit       StockReductionUnit       `json:"unit"`
+	Required   float64                  `json:"required"`
+	Available  float64                  `json:"available"`
+	Shortage   float64                  `json:"shortage"`
+}
+
 // Request DTOs
 type TransactionCreateRequest struct {
 	Items []TransactionItemRequest `json:"items" binding:"required"`
@@ -57,6 +124,22 @@ type TransactionCreateRequest struct {
 }
 
 type TransactionItemRequest struct {
-	MenuID   uint `json:"menu_id" binding:"required"`
-	Quantity int  `json:"quantity" binding:"required,min=1"`
+	MenuID            uint   `json:"menu_id" binding:"required"`
+	Quantity          int    `json:"quantity" binding:"required,min=1"`
+	ModifierOptionIDs []uint `json:"modifier_option_ids"` // Pilihan modifier per porsi
+}
+
+type TransactionCancelRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type TransactionRefundRequest struct {
+	Reason string                         `json:"reason" binding:"required"`
+	Items  []TransactionRefundItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+type TransactionRefundItemRequest struct {
+	TransactionItemID uint `json:"transaction_item_id" binding:"required"`
+	Quantity          int  `json:"quantity" binding:"required,min=1"`
+	Restock           bool `json:"restock"` // true jika item belum dibuat sehingga bahan bisa dikembalikan ke stok
 }
@@ -3,16 +3,19 @@ package dto
 import "time"
 
 type Unit struct {
-	ID        uint `json:"id"`
-	Name      string `json:"name"`
-	Symbol    string `json:"symbol"`
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Symbol    string    `json:"symbol"`
+	Dimension string    `json:"dimension"`
+	Factor    float64   `json:"factor"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	DeletedAt time.Time `json:"deleted_at"`
 }
 
-
 type UnitParamRequest struct {
-	Name      string `json:"name"`
-	Symbol    string `json:"symbol"`
+	Name      string  `json:"name"`
+	Symbol    string  `json:"symbol"`
+	Dimension string  `json:"dimension" binding:"omitempty,oneof=mass volume count length"`
+	Factor    float64 `json:"factor" binding:"omitempty,gt=0"`
 }
@@ -0,0 +1,60 @@
+package dto
+
+import "time"
+
+// Waste DTOs
+type WasteRecord struct {
+	ID              uint                     `json:"id"`
+	Ingredient      StockReductionIngredient `json:"ingredient"`
+	Quantity        float64                  `json:"quantity"`
+	WastedUnit      StockReductionUnit       `json:"wasted_unit"`
+	QuantityReduced float64                  `json:"quantity_reduced"` // Dalam unit stok ingredient
+	StockBefore     float64                  `json:"stock_before"`
+	StockAfter      float64                  `json:"stock_after"`
+	Unit            StockReductionUnit       `json:"unit"`
+	UnitCost        float64                  `json:"unit_cost"`
+	Value           float64                  `json:"value"`
+	Reason          string                   `json:"reason"`
+	Notes           string                   `json:"notes"`
+	WastedAt        time.Time                `json:"wasted_at"`
+	UserID          *uint                    `json:"user_id"`
+	StockMovementID *uint                    `json:"stock_movement_id"`
+}
+
+// WasteReport merangkum waste per alasan, ingredient dan tanggal
+type WasteReport struct {
+	Records      int                    `json:"records"`
+	TotalValue   float64                `json:"total_value"`
+	ByReason     []WasteReasonTotal     `json:"by_reason"`
+	ByIngredient []WasteIngredientTotal `json:"by_ingredient"`
+	ByDate       []WasteDateTotal       `json:"by_date"`
+}
+
+type WasteReasonTotal struct {
+	Reason  string  `json:"reason"`
+	Records int     `json:"records"`
+	Value   float64 `json:"value"`
+}
+
+type WasteIngredientTotal struct {
+	Ingredient StockReductionIngredient `json:"ingredient"`
+	Unit       StockReductionUnit       `json:"unit"`
+	Records    int                      `json:"records"`
+	Quantity   float64                  `json:"quantity"` // Dalam unit stok ingredient
+	Value      float64                  `json:"value"`
+}
+
+type WasteDateTotal struct {
+	Date    string  `json:"date"` // YYYY-MM-DD
+	Records int     `json:"records"`
+	Value   float64 `json:"value"`
+}
+
+// Request DTOs
+type WasteCreateRequest struct {
+	IngredientID uint    `json:"ingredient_id" binding:"required"`
+	Quantity     float64 `json:"quantity" binding:"required,gt=0"`
+	UnitID       uint    `json:"unit_id" binding:"required"` // Unit jumlah, harus bisa dikonversi ke unit ingredient
+	Reason       string  `json:"reason" binding:"required,oneof=expired damaged staff_meal comp other"`
+	Notes        string  `json:"notes"`
+}
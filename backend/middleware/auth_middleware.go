@@ -1,15 +1,20 @@
 package middleware
 
 import (
-	"AwisPalace_IngredientManagement/utils"
+	"errors"
 	"net/http"
 	"strings"
 
+	"AwisPalace_IngredientManagement/services"
+	"AwisPalace_IngredientManagement/utils"
+
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware validates JWT token
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware validates the JWT token and loads the user it was issued to.
+// The role comes from the database, not from the token, so role changes and
+// deleted users take effect on the next request.
+func AuthMiddleware(users *services.UserService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 
@@ -36,9 +41,26 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		user, err := users.Get(claims.UserID)
+		if err != nil {
+			status := http.StatusInternalServerError
+			message := err.Error()
+			if errors.Is(err, services.ErrNotFound) {
+				status = http.StatusUnauthorized
+				message = "User not found"
+			}
+			c.JSON(status, gin.H{
+				"status":  "error",
+				"message": message,
+			})
+			c.Abort()
+			return
+		}
+
 		// Set user info in context
-		c.Set("user_id", claims.UserID)
-		c.Set("user_email", claims.Email)
+		c.Set("user_id", user.ID)
+		c.Set("user_email", user.Email)
+		c.Set("user_role", user.Role)
 
 		c.Next()
 	}
@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"AwisPalace_IngredientManagement/config"
+	"AwisPalace_IngredientManagement/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/clause"
+)
+
+// IdempotencyKeyHeader is the request header holding the client generated key
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotencyKeyTTL is how long a stored response can be replayed
+const IdempotencyKeyTTL = 24 * time.Hour
+
+// IdempotencyClaimTTL is how long a key blocks retries while its request is
+// processed. A claim left behind by a crashed server is freed after it.
+const IdempotencyClaimTTL = 5 * time.Minute
+
+// Idempotency makes POST, PUT, PATCH and DELETE requests carrying an
+// Idempotency-Key header safe to retry. The first request is processed and
+// its response stored; a retry with the same key and body gets the stored
+// response, and a retry with a different body is rejected with 409.
+// Keys are scoped per user, so it must run after AuthMiddleware, and after
+// RequireRoles so rejected requests are not stored.
+func Idempotency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || !isMutatingMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		if len(key) > 255 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Idempotency-Key must be at most 255 characters",
+			})
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Failed to read request body",
+			})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.New()
+		hash.Write([]byte(c.Request.Method + " " + c.Request.URL.Path + "\n"))
+		hash.Write(body)
+
+		record := models.IdempotencyKey{
+			UserID:      c.GetUint("user_id"),
+			Key:         key,
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			RequestHash: hex.EncodeToString(hash.Sum(nil)),
+			ExpiresAt:   time.Now().Add(IdempotencyClaimTTL),
+		}
+
+		// Hapus key kadaluarsa, termasuk klaim yang tertinggal, supaya bisa dipakai ulang
+		config.DB.Where("user_id = ? AND key = ? AND expires_at < ?", record.UserID, key, time.Now()).
+			Delete(&models.IdempotencyKey{})
+
+		// Klaim key, gagal jika request lain dengan key yang sama sudah ada
+		result := config.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+		if result.Error != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": result.Error.Error(),
+			})
+			c.Abort()
+			return
+		}
+
+		if result.RowsAffected == 0 {
+			replayIdempotentResponse(c, record)
+			return
+		}
+
+		writer := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		// Error server, panic dan handler yang tidak menulis response tidak
+		// disimpan agar client bisa mencoba lagi
+		stored := false
+		defer func() {
+			if !stored {
+				config.DB.Delete(&record)
+			}
+		}()
+
+		c.Next()
+
+		if !c.Writer.Written() || c.Writer.Status() >= http.StatusInternalServerError {
+			return
+		}
+
+		stored = config.DB.Model(&record).Updates(map[string]interface{}{
+			"status_code":  c.Writer.Status(),
+			"content_type": c.Writer.Header().Get("Content-Type"),
+			"response":     writer.body.String(),
+			"expires_at":   time.Now().Add(IdempotencyKeyTTL),
+		}).Error == nil
+	}
+}
+
+func replayIdempotentResponse(c *gin.Context, request models.IdempotencyKey) {
+	var stored models.IdempotencyKey
+	if err := config.DB.Where("user_id = ? AND key = ?", request.UserID, request.Key).First(&stored).Error; err != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"status":  "error",
+			"message": "A request with this Idempotency-Key is still being processed",
+		})
+		c.Abort()
+		return
+	}
+
+	if stored.RequestHash != request.RequestHash {
+		c.JSON(http.StatusConflict, gin.H{
+			"status":  "error",
+			"message": "Idempotency-Key was already used for a different request",
+		})
+		c.Abort()
+		return
+	}
+
+	if stored.StatusCode == 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"status":  "error",
+			"message": "A request with this Idempotency-Key is still being processed",
+		})
+		c.Abort()
+		return
+	}
+
+	c.Header("Idempotent-Replayed", "true")
+	c.Data(stored.StatusCode, stored.ContentType, []byte(stored.Response))
+	c.Abort()
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// responseRecorder keeps a copy of the response body
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
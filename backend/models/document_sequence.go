@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// DocumentSequence adalah counter untuk penomoran dokumen (contoh: kode
+// transaksi per hari). Value dinaikkan secara atomik di dalam transaksi
+// database sehingga aman dipakai bersamaan.
+type DocumentSequence struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"type:varchar(100);uniqueIndex;not null"` // Contoh: transaction:OUTLET1:20261017
+	Value     int64  `gorm:"not null;default:0"`                     // Nomor terakhir yang dipakai
+	UpdatedAt time.Time
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// GoodsReceipt adalah record penerimaan barang dari purchase order
+type GoodsReceipt struct {
+	gorm.Model
+	ReceiptNumber   string    `gorm:"type:varchar(50);uniqueIndex;not null"` // Nomor penerimaan unik
+	ReceivedDate    time.Time `gorm:"not null"`                              // Tanggal barang diterima
+	Notes           string    `gorm:"type:text"`
+	PurchaseOrderID uint
+	PurchaseOrder   PurchaseOrder
+
+	Items []GoodsReceiptItem // Detail barang yang diterima
+}
+
+// GoodsReceiptItem adalah record penambahan stok ingredient akibat penerimaan barang
+type GoodsReceiptItem struct {
+	gorm.Model
+	GoodsReceiptID      uint
+	GoodsReceipt        GoodsReceipt
+	PurchaseOrderLineID uint
+	PurchaseOrderLine   PurchaseOrderLine
+
+	IngredientID     uint
+	Ingredient       Ingredient
+	QuantityReceived float64 `gorm:"type:numeric(12,2);not null"` // Jumlah diterima (dalam unit PO)
+	ReceivedUnitID   uint
+	ReceivedUnit     Unit
+	UnitCost         float64 `gorm:"type:numeric(12,2);not null"` // Harga per unit PO
+	QuantityAdded    float64 `gorm:"type:numeric(14,4);not null"` // Jumlah yang ditambahkan ke stok (dalam unit ingredient)
+	StockBefore      float64 `gorm:"type:numeric(14,4);not null"` // Stok sebelum penambahan
+	StockAfter       float64 `gorm:"type:numeric(14,4);not null"` // Stok setelah penambahan
+	UnitID           uint
+	Unit             Unit
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// IdempotencyKey menyimpan hasil request yang dikirim dengan header
+// Idempotency-Key agar retry dari client tidak diproses dua kali
+type IdempotencyKey struct {
+	ID          uint      `gorm:"primaryKey"`
+	UserID      uint      `gorm:"uniqueIndex:idx_idempotency_user_key;not null"`
+	Key         string    `gorm:"type:varchar(255);uniqueIndex:idx_idempotency_user_key;not null"`
+	Method      string    `gorm:"type:varchar(10);not null"`
+	Path        string    `gorm:"type:text;not null"`
+	RequestHash string    `gorm:"type:varchar(64);not null"` // SHA-256 dari method, path dan body
+	StatusCode  int       // 0 = masih diproses
+	ContentType string    `gorm:"type:varchar(100)"`
+	Response    string    `gorm:"type:text"`
+	ExpiresAt   time.Time `gorm:"index;not null"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
@@ -2,14 +2,52 @@ package models
 
 import "gorm.io/gorm"
 
+// Metode perhitungan harga pokok ingredient
+const (
+	CostMethodAverage      = "average"       // Moving average dari setiap pembelian
+	CostMethodLastPurchase = "last_purchase" // Harga pembelian terakhir
+)
+
 type Ingredient struct {
 	gorm.Model
-	Name   string  `gorm:"type:varchar(100);not null"`
-	Slug   string  `gorm:"type:varchar(100);uniqueIndex"`
-	Stock  float64 `gorm:"type:numeric(10,2)"`
-	UnitID uint
-	Unit   Unit
+	Name       string  `gorm:"type:varchar(100);not null"`
+	Slug       string  `gorm:"type:varchar(100);uniqueIndex"`
+	Stock      float64 `gorm:"type:numeric(14,4)"`
+	UnitID     uint
+	Unit       Unit
+	UnitCost   float64 `gorm:"type:numeric(14,4);not null;default:0"`       // Harga pokok per unit stok
+	CostMethod string  `gorm:"type:varchar(20);not null;default:'average'"` // average, last_purchase
+
+	MinimumStock float64 `gorm:"type:numeric(14,4);not null;default:0"` // Stok aman minimum
+	ReorderPoint float64 `gorm:"type:numeric(14,4);not null;default:0"` // Pesan ulang jika stok <= reorder point
+	ParLevel     float64 `gorm:"type:numeric(14,4);not null;default:0"` // Target stok setelah pemesanan ulang
 
+	Conversions     []IngredientUnitConversion
 	MenuIngredients []MenuIngredient
 	StockReductions []StockReduction
+	StockMovements  []StockMovement
+	StockAlerts     []StockAlert
+	Suppliers       []SupplierIngredient
+}
+
+// IsLowStock reports whether the stock is at or below the reorder point or the minimum stock
+func (i Ingredient) IsLowStock() bool {
+	return (i.ReorderPoint > 0 && i.Stock <= i.ReorderPoint) ||
+		(i.MinimumStock > 0 && i.Stock <= i.MinimumStock)
+}
+
+// Shortage is the quantity needed to bring the stock back to the par level
+// (or the reorder point when no par level is set)
+func (i Ingredient) Shortage() float64 {
+	target := i.ParLevel
+	if target < i.ReorderPoint {
+		target = i.ReorderPoint
+	}
+	if target < i.MinimumStock {
+		target = i.MinimumStock
+	}
+	if i.Stock >= target {
+		return 0
+	}
+	return target - i.Stock
 }
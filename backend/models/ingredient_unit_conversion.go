@@ -0,0 +1,18 @@
+package models
+
+import "gorm.io/gorm"
+
+// IngredientUnitConversion adalah konversi khusus per ingredient
+// antar dimensi yang berbeda, contoh: 1 pcs telur = 60 g
+type IngredientUnitConversion struct {
+	gorm.Model
+	IngredientID uint `gorm:"uniqueIndex:idx_ingredient_conversion"`
+	Ingredient   Ingredient
+
+	UnitID uint `gorm:"uniqueIndex:idx_ingredient_conversion"` // Unit asal (contoh: pcs)
+	Unit   Unit
+
+	Quantity float64 `gorm:"type:numeric(14,6);not null"` // Jumlah dalam ToUnit untuk 1 Unit
+	ToUnitID uint    // Unit tujuan (contoh: g)
+	ToUnit   Unit
+}
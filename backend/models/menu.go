@@ -9,7 +9,9 @@ type Menu struct {
 	Description string  `gorm:"type:text"`
 	Image       string  `gorm:"type:text"`
 	Price       float64 `gorm:"type:numeric(12,2)"`
+	SoldOut     bool    `gorm:"not null;default:false"` // Ditandai habis (86) secara manual
 
 	MenuIngredients  []MenuIngredient
+	ModifierGroups   []MenuModifierGroup // Pilihan ukuran, tambahan dan pengurangan
 	TransactionItems []TransactionItem
 }
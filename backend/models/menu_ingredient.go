@@ -11,7 +11,7 @@ type MenuIngredient struct {
 	IngredientID uint
 	Ingredient   Ingredient
 
-	Quantity float64 `gorm:"type:numeric(10,2);not null"`
+	Quantity float64 `gorm:"type:numeric(14,4);not null"`
 	UnitID   uint
 	Unit     Unit
 }
@@ -0,0 +1,50 @@
+package models
+
+import "gorm.io/gorm"
+
+// MenuModifierGroup adalah kelompok pilihan menu, contoh: ukuran, tambahan, tanpa
+type MenuModifierGroup struct {
+	gorm.Model
+	MenuID    uint
+	Menu      Menu
+	Name      string `gorm:"type:varchar(100);not null"`
+	MinSelect int    `gorm:"not null;default:0"` // Minimal pilihan, > 0 berarti wajib dipilih
+	MaxSelect int    `gorm:"not null;default:0"` // Maksimal pilihan, 0 = tidak dibatasi
+	SortOrder int    `gorm:"not null;default:0"`
+
+	Options []MenuModifierOption `gorm:"foreignKey:GroupID"`
+}
+
+// MenuModifierOption adalah satu pilihan dalam kelompok modifier
+type MenuModifierOption struct {
+	gorm.Model
+	GroupID    uint
+	Group      MenuModifierGroup
+	Name       string  `gorm:"type:varchar(100);not null"`
+	PriceDelta float64 `gorm:"type:numeric(12,2);not null;default:0"` // Tambahan harga per porsi, boleh negatif
+	SortOrder  int     `gorm:"not null;default:0"`
+
+	Ingredients []MenuModifierIngredient `gorm:"foreignKey:OptionID"`
+}
+
+// MenuModifierIngredient mengubah resep per porsi jika pilihan dipesan
+type MenuModifierIngredient struct {
+	gorm.Model
+	OptionID     uint
+	IngredientID uint
+	Ingredient   Ingredient
+	Quantity     float64 `gorm:"type:numeric(14,4);not null"` // Positif = tambah, negatif = kurangi dari resep
+	UnitID       uint
+	Unit         Unit
+}
+
+// TransactionItemModifier adalah pilihan modifier yang dipesan pada item
+// transaksi. Nama dan harga disimpan agar riwayat tidak berubah saat menu diubah.
+type TransactionItemModifier struct {
+	gorm.Model
+	TransactionItemID uint
+	ModifierOptionID  uint
+	GroupName         string  `gorm:"type:varchar(100);not null"`
+	OptionName        string  `gorm:"type:varchar(100);not null"`
+	PriceDelta        float64 `gorm:"type:numeric(12,2);not null;default:0"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Status purchase order
+const (
+	PurchaseOrderStatusDraft             = "draft"
+	PurchaseOrderStatusSent              = "sent"
+	PurchaseOrderStatusPartiallyReceived = "partially_received"
+	PurchaseOrderStatusReceived          = "received"
+)
+
+// PurchaseOrder adalah pesanan pembelian ingredient ke supplier
+type PurchaseOrder struct {
+	gorm.Model
+	PONumber     string     `gorm:"type:varchar(50);uniqueIndex;not null"` // Nomor PO unik
+	OrderDate    time.Time  `gorm:"not null"`                              // Tanggal pemesanan
+	ExpectedDate *time.Time // Perkiraan tanggal barang datang (opsional)
+	Status       string     `gorm:"type:varchar(30);default:'draft'"` // draft, sent, partially_received, received
+	TotalAmount  float64    `gorm:"type:numeric(14,2)"`               // Total nilai PO
+	Notes        string     `gorm:"type:text"`
+
+	SupplierID *uint     `gorm:"index"` // Supplier tujuan (opsional)
+	Supplier   *Supplier `gorm:"constraint:OnDelete:SET NULL"`
+
+	Lines    []PurchaseOrderLine // Detail ingredient yang dipesan
+	Receipts []GoodsReceipt      // Penerimaan barang untuk PO ini
+}
+
+// PurchaseOrderLine adalah detail ingredient yang dipesan dalam satu PO
+type PurchaseOrderLine struct {
+	gorm.Model
+	PurchaseOrderID uint
+	PurchaseOrder   PurchaseOrder
+
+	IngredientID     uint
+	Ingredient       Ingredient
+	Quantity         float64 `gorm:"type:numeric(12,2);not null"`           // Jumlah dipesan (dalam UnitID)
+	ReceivedQuantity float64 `gorm:"type:numeric(12,2);not null;default:0"` // Jumlah yang sudah diterima (dalam UnitID)
+	UnitID           uint
+	Unit             Unit
+	UnitCost         float64 `gorm:"type:numeric(12,2);not null"` // Harga per unit
+}
+
+// Outstanding adalah jumlah yang belum diterima
+func (l PurchaseOrderLine) Outstanding() float64 {
+	if l.ReceivedQuantity >= l.Quantity {
+		return 0
+	}
+	return l.Quantity - l.ReceivedQuantity
+}
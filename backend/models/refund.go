@@ -0,0 +1,31 @@
+package models
+
+import "gorm.io/gorm"
+
+// Refund adalah record pengembalian dana sebagian atau seluruh item transaksi.
+// Record transaksi asli tetap disimpan.
+type Refund struct {
+	gorm.Model
+	TransactionID uint
+	Transaction   Transaction
+
+	Reason      string  `gorm:"type:text;not null"` // Alasan refund (contoh: komplain, pesanan belum dibuat)
+	TotalAmount float64 `gorm:"type:numeric(12,2)"` // Total dana yang dikembalikan
+	UserID      *uint   // User yang melakukan refund
+	User        *User   `gorm:"constraint:OnDelete:SET NULL"`
+
+	Items []RefundItem // Detail item yang direfund
+}
+
+// RefundItem adalah detail item transaksi yang direfund
+type RefundItem struct {
+	gorm.Model
+	RefundID          uint
+	Refund            Refund
+	TransactionItemID uint
+	TransactionItem   TransactionItem
+
+	Quantity  int     `gorm:"not null"`                    // Jumlah porsi yang direfund
+	Amount    float64 `gorm:"type:numeric(12,2);not null"` // Dana yang dikembalikan
+	Restocked bool    `gorm:"not null;default:false"`      // Stok dikembalikan (hanya jika belum dibuat)
+}
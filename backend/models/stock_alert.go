@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Level stock alert
+const (
+	StockAlertBelowReorderPoint = "below_reorder_point"
+	StockAlertBelowMinimum      = "below_minimum"
+)
+
+// StockAlert dibuat ketika stok ingredient turun melewati reorder point
+// atau minimum stock
+type StockAlert struct {
+	gorm.Model
+	IngredientID uint `gorm:"index"`
+	Ingredient   Ingredient
+
+	Level           string  `gorm:"type:varchar(30);not null"`   // below_reorder_point, below_minimum
+	Stock           float64 `gorm:"type:numeric(14,4);not null"` // Stok setelah movement
+	Threshold       float64 `gorm:"type:numeric(14,4);not null"` // Batas yang dilewati
+	StockMovementID uint    // Movement yang memicu alert
+	StockMovement   StockMovement
+
+	AcknowledgedAt   *time.Time `gorm:"index"` // Kosong = belum ditindaklanjuti
+	AcknowledgedByID *uint
+	AcknowledgedBy   *User `gorm:"foreignKey:AcknowledgedByID;constraint:OnDelete:SET NULL"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StockLot adalah batch ingredient yang diterima bersama, dengan tanggal
+// kedaluwarsanya. Stok ingredient yang tidak tercakup lot (stok awal atau
+// penyesuaian) tidak memiliki tanggal kedaluwarsa.
+type StockLot struct {
+	gorm.Model
+	IngredientID uint `gorm:"index"`
+	Ingredient   Ingredient
+
+	LotNumber          string     `gorm:"type:varchar(50)"`                  // Nomor batch dari supplier (opsional)
+	ReceivedDate       time.Time  `gorm:"not null"`                          // Tanggal lot diterima
+	ExpiryDate         *time.Time `gorm:"index"`                             // Kosong = tidak kedaluwarsa
+	Quantity           float64    `gorm:"type:numeric(14,4);not null"`       // Jumlah awal (dalam unit stok)
+	Remaining          float64    `gorm:"type:numeric(14,4);not null;index"` // Sisa yang belum terpakai
+	UnitID             uint       // Unit stok ingredient saat diterima
+	Unit               Unit
+	GoodsReceiptItemID *uint // Penerimaan barang asal lot
+}
+
+// DaysUntilExpiry returns the whole days from now until the lot expires,
+// negative once it has expired, or nil when the lot does not expire
+func (l StockLot) DaysUntilExpiry(now time.Time) *int {
+	if l.ExpiryDate == nil {
+		return nil
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	expiry := l.ExpiryDate.In(now.Location())
+	expiryDay := time.Date(expiry.Year(), expiry.Month(), expiry.Day(), 0, 0, 0, 0, now.Location())
+	days := int(expiryDay.Sub(today).Hours() / 24)
+	return &days
+}
+
+// LotAllocation adalah jumlah yang diambil dari satu lot oleh stock movement
+type LotAllocation struct {
+	StockLotID uint
+	Quantity   float64 // Dalam unit stok
+}
+
+// StockReductionLot mencatat lot yang dipakai oleh pengurangan stok transaksi
+type StockReductionLot struct {
+	gorm.Model
+	StockReductionID uint `gorm:"index"`
+	StockLotID       uint `gorm:"index"`
+	StockLot         StockLot
+	Quantity         float64 `gorm:"type:numeric(14,4);not null"` // Jumlah yang diambil dari lot (dalam unit stok)
+}
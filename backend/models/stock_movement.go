@@ -0,0 +1,37 @@
+package models
+
+import "gorm.io/gorm"
+
+// Tipe stock movement
+const (
+	StockMovementSale         = "sale"
+	StockMovementSaleReversal = "sale_reversal"
+	StockMovementPurchase     = "purchase"
+	StockMovementAdjustment   = "adjustment"
+	StockMovementWaste        = "waste"
+	StockMovementTransfer     = "transfer"
+	StockMovementStocktake    = "stocktake"
+)
+
+// StockMovement adalah ledger untuk setiap perubahan stok ingredient.
+// Ingredient.Stock hanya boleh berubah melalui ledger ini.
+type StockMovement struct {
+	gorm.Model
+	IngredientID uint `gorm:"index"`
+	Ingredient   Ingredient
+
+	Type        string  `gorm:"type:varchar(20);index;not null"` // sale, sale_reversal, purchase, adjustment, waste, transfer, stocktake
+	Quantity    float64 `gorm:"type:numeric(14,4);not null"`     // Positif = stok bertambah, negatif = stok berkurang
+	StockBefore float64 `gorm:"type:numeric(14,4);not null"`     // Stok sebelum perubahan
+	StockAfter  float64 `gorm:"type:numeric(14,4);not null"`     // Stok setelah perubahan
+	UnitID      uint    // Unit stok ingredient
+	Unit        Unit
+
+	UserID        *uint  // User yang melakukan perubahan (opsional)
+	User          *User  `gorm:"constraint:OnDelete:SET NULL"`
+	ReferenceType string `gorm:"type:varchar(50);index:idx_stock_movement_reference"` // Contoh: transaction_item, goods_receipt_item
+	ReferenceID   uint   `gorm:"index:idx_stock_movement_reference"`
+	Notes         string `gorm:"type:text"`
+
+	Lots []LotAllocation `gorm:"-"` // Lot yang terpakai oleh movement keluar, diisi RecordStockMovement
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Status stocktake
+const (
+	StocktakeStatusDraft    = "draft"
+	StocktakeStatusCounting = "counting"
+	StocktakeStatusPosted   = "posted"
+)
+
+// Stocktake adalah stock opname: penghitungan fisik stok ingredient.
+// Draft menentukan ingredient yang dihitung, counting mengambil stok
+// teoritis dan menerima hasil hitungan, posted membukukan selisihnya.
+type Stocktake struct {
+	gorm.Model
+	Code        string     `gorm:"type:varchar(50);uniqueIndex;not null"`           // Kode stocktake unik
+	Status      string     `gorm:"type:varchar(20);index;not null;default:'draft'"` // Status: draft, counting, posted
+	Notes       string     `gorm:"type:text"`
+	StartedAt   *time.Time // Waktu penghitungan dimulai (stok teoritis diambil)
+	PostedAt    *time.Time // Waktu selisih dibukukan
+	CreatedByID *uint
+	CreatedBy   *User `gorm:"foreignKey:CreatedByID;constraint:OnDelete:SET NULL"`
+	PostedByID  *uint
+	PostedBy    *User `gorm:"foreignKey:PostedByID;constraint:OnDelete:SET NULL"`
+
+	Lines []StocktakeLine // Ingredient yang dihitung
+}
+
+// StocktakeLine adalah hasil hitungan satu ingredient
+type StocktakeLine struct {
+	gorm.Model
+	StocktakeID  uint `gorm:"uniqueIndex:idx_stocktake_line_ingredient"`
+	IngredientID uint `gorm:"uniqueIndex:idx_stocktake_line_ingredient"`
+	Ingredient   Ingredient
+
+	UnitID           uint // Unit stok ingredient saat penghitungan dimulai
+	Unit             Unit
+	TheoreticalStock float64  `gorm:"type:numeric(14,4);not null;default:0"` // Stok menurut sistem saat penghitungan dimulai
+	CountedQuantity  *float64 `gorm:"type:numeric(14,4)"`                    // Kosong = belum dihitung
+	UnitCost         float64  `gorm:"type:numeric(12,2);not null;default:0"` // Harga pokok saat penghitungan dimulai
+	StockMovementID  *uint    // Movement penyesuaian saat posting
+}
+
+// Counted reports whether a quantity was entered for the line
+func (l StocktakeLine) Counted() bool {
+	return l.CountedQuantity != nil
+}
+
+// Variance is the counted quantity minus the theoretical stock, negative
+// when stock is missing. Uncounted lines have no variance.
+func (l StocktakeLine) Variance() float64 {
+	if l.CountedQuantity == nil {
+		return 0
+	}
+	return *l.CountedQuantity - l.TheoreticalStock
+}
+
+// VarianceValue is the variance valued at the unit cost
+func (l StocktakeLine) VarianceValue() float64 {
+	return l.Variance() * l.UnitCost
+}
+
+// VariancePercentage is the variance relative to the theoretical stock. It
+// is nil when the line is not counted or the theoretical stock is zero.
+func (l StocktakeLine) VariancePercentage() *float64 {
+	if l.CountedQuantity == nil || l.TheoreticalStock == 0 {
+		return nil
+	}
+	percentage := l.Variance() / l.TheoreticalStock * 100
+	return &percentage
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Supplier adalah pemasok ingredient
+type Supplier struct {
+	gorm.Model
+	Name         string `gorm:"type:varchar(100);not null"`
+	ContactName  string `gorm:"type:varchar(100)"` // Nama kontak
+	Phone        string `gorm:"type:varchar(30)"`
+	Email        string `gorm:"type:varchar(100)"`
+	Address      string `gorm:"type:text"`
+	LeadTimeDays int    `gorm:"not null;default:0"` // Lama pengiriman sejak PO dikirim (hari)
+	PaymentTerms string `gorm:"type:varchar(100)"`  // Contoh: COD, NET 30
+	Notes        string `gorm:"type:text"`
+
+	Ingredients []SupplierIngredient // Ingredient yang dijual supplier
+}
+
+// SupplierIngredient menghubungkan supplier dengan ingredient yang dijualnya
+type SupplierIngredient struct {
+	gorm.Model
+	SupplierID   uint `gorm:"uniqueIndex:idx_supplier_ingredient"`
+	Supplier     Supplier
+	IngredientID uint `gorm:"uniqueIndex:idx_supplier_ingredient;index"`
+	Ingredient   Ingredient
+
+	SKU         string  `gorm:"type:varchar(50)"`            // Kode barang di supplier
+	PackSize    float64 `gorm:"type:numeric(12,2);not null"` // Isi satu kemasan (dalam PackUnit)
+	PackUnitID  uint    // Unit isi kemasan
+	PackUnit    Unit
+	LastPrice   float64    `gorm:"type:numeric(14,2);not null;default:0"` // Harga terakhir per kemasan
+	LastPriceAt *time.Time // Waktu harga terakhir dicatat
+}
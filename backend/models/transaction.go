@@ -6,16 +6,38 @@ import (
 	"gorm.io/gorm"
 )
 
+// Status transaksi
+const (
+	TransactionStatusCompleted         = "completed"
+	TransactionStatusCancelled         = "cancelled"
+	TransactionStatusPartiallyRefunded = "partially_refunded"
+	TransactionStatusRefunded          = "refunded"
+)
+
 // Transaction adalah record penjualan menu
 type Transaction struct {
 	gorm.Model
-	TransactionCode string    `gorm:"type:varchar(50);uniqueIndex;not null"` // Kode transaksi unik
-	TransactionDate time.Time `gorm:"not null"`                              // Tanggal transaksi
-	TotalAmount     float64   `gorm:"type:numeric(12,2)"`                    // Total harga transaksi
-	Notes           string    `gorm:"type:text"`                             // Catatan tambahan (opsional)
-	Status          string    `gorm:"type:varchar(20);default:'completed'"`  // Status: completed, cancelled, etc.
+	TransactionCode string     `gorm:"type:varchar(50);uniqueIndex;not null"` // Kode transaksi unik
+	TransactionDate time.Time  `gorm:"not null"`                              // Tanggal transaksi
+	TotalAmount     float64    `gorm:"type:numeric(12,2)"`                    // Total harga transaksi
+	RefundedAmount  float64    `gorm:"type:numeric(12,2);not null;default:0"` // Total yang sudah direfund
+	Notes           string     `gorm:"type:text"`                             // Catatan tambahan (opsional)
+	Status          string     `gorm:"type:varchar(20);default:'completed'"`  // Status: completed, cancelled, partially_refunded, refunded
+	CancelReason    string     `gorm:"type:text"`                             // Alasan pembatalan
+	CancelledAt     *time.Time // Waktu pembatalan
+	CancelledByID   *uint      // User yang membatalkan
+	CancelledBy     *User      `gorm:"foreignKey:CancelledByID;constraint:OnDelete:SET NULL"`
 
 	TransactionItems []TransactionItem // Detail item yang terjual
+	Refunds          []Refund          // Riwayat refund
+}
+
+// NetAmount adalah total transaksi setelah dikurangi refund dan pembatalan
+func (t Transaction) NetAmount() float64 {
+	if t.Status == TransactionStatusCancelled {
+		return 0
+	}
+	return t.TotalAmount - t.RefundedAmount
 }
 
 // TransactionItem adalah detail menu yang terjual dalam satu transaksi
@@ -26,10 +48,14 @@ type TransactionItem struct {
 
 	MenuID   uint
 	Menu     Menu
-	Quantity int     `gorm:"not null"`                    // Jumlah menu yang terjual
-	Price    float64 `gorm:"type:numeric(12,2);not null"` // Harga menu saat transaksi (untuk historical data)
+	Quantity int     `gorm:"not null"`                              // Jumlah menu yang terjual
+	Price    float64 `gorm:"type:numeric(12,2);not null"`           // Harga menu saat transaksi (untuk historical data)
+	Cost     float64 `gorm:"type:numeric(12,2);not null;default:0"` // Harga pokok per porsi saat transaksi (untuk historical margin)
+
+	RefundedQuantity int `gorm:"not null;default:0"` // Jumlah yang sudah direfund
 
-	StockReductions []StockReduction // Detail pengurangan stok per ingredient
+	StockReductions []StockReduction          // Detail pengurangan stok per ingredient
+	Modifiers       []TransactionItemModifier // Pilihan modifier yang dipesan
 }
 
 // StockReduction adalah record pengurangan stok ingredient akibat transaksi
@@ -40,9 +66,11 @@ type StockReduction struct {
 
 	IngredientID    uint
 	Ingredient      Ingredient
-	QuantityReduced float64 `gorm:"type:numeric(10,2);not null"` // Jumlah yang dikurangi
-	StockBefore     float64 `gorm:"type:numeric(10,2);not null"` // Stok sebelum pengurangan
-	StockAfter      float64 `gorm:"type:numeric(10,2);not null"` // Stok setelah pengurangan
+	QuantityReduced float64 `gorm:"type:numeric(14,4);not null"` // Jumlah yang dikurangi
+	StockBefore     float64 `gorm:"type:numeric(14,4);not null"` // Stok sebelum pengurangan
+	StockAfter      float64 `gorm:"type:numeric(14,4);not null"` // Stok setelah pengurangan
 	UnitID          uint
 	Unit            Unit
+
+	Lots []StockReductionLot // Lot yang terpakai, urut kedaluwarsa terdekat
 }
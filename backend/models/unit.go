@@ -2,8 +2,18 @@ package models
 
 import "gorm.io/gorm"
 
+// Dimension unit, hanya unit dengan dimensi yang sama yang bisa dikonversi langsung
+const (
+	DimensionMass   = "mass"
+	DimensionVolume = "volume"
+	DimensionCount  = "count"
+	DimensionLength = "length"
+)
+
 type Unit struct {
 	gorm.Model
-	Name   string `gorm:"type:varchar(50);not null"`
-	Symbol string `gorm:"type:varchar(10);uniqueIndex"`
+	Name      string  `gorm:"type:varchar(50);not null"`
+	Symbol    string  `gorm:"type:varchar(10);uniqueIndex"`
+	Dimension string  `gorm:"type:varchar(20)"`                      // mass, volume, count, length
+	Factor    float64 `gorm:"type:numeric(14,6);not null;default:1"` // Pengali ke unit dasar dimensi (g, ml, pcs, m)
 }
@@ -6,12 +6,21 @@ import (
 	"gorm.io/gorm"
 )
 
+// Role user
+const (
+	RoleOwner   = "owner"
+	RoleManager = "manager"
+	RoleCashier = "cashier"
+	RoleKitchen = "kitchen"
+)
+
 type User struct {
 	ID        uint           `gorm:"primaryKey" json:"id"`
 	GoogleID  string         `gorm:"type:varchar(255);uniqueIndex" json:"google_id"`
 	Email     string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
 	Name      string         `gorm:"type:varchar(255)" json:"name"`
 	PhotoURL  string         `gorm:"type:text" json:"photo_url"`
+	Role      string         `gorm:"type:varchar(20);not null;default:'cashier'" json:"role"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Alasan waste
+const (
+	WasteReasonExpired   = "expired"
+	WasteReasonDamaged   = "damaged"
+	WasteReasonStaffMeal = "staff_meal"
+	WasteReasonComp      = "comp"
+	WasteReasonOther     = "other"
+)
+
+// WasteReasons lists the valid reason codes of a waste record
+var WasteReasons = []string{
+	WasteReasonExpired,
+	WasteReasonDamaged,
+	WasteReasonStaffMeal,
+	WasteReasonComp,
+	WasteReasonOther,
+}
+
+// WasteRecord adalah record pengurangan stok ingredient di luar penjualan:
+// basi, rusak, makan staf atau komplimen
+type WasteRecord struct {
+	gorm.Model
+	IngredientID uint `gorm:"index"`
+	Ingredient   Ingredient
+
+	Quantity        float64 `gorm:"type:numeric(12,2);not null"` // Jumlah yang dibuang (dalam unit input)
+	WastedUnitID    uint    // Unit input
+	WastedUnit      Unit
+	QuantityReduced float64 `gorm:"type:numeric(14,4);not null"` // Jumlah yang dikurangi dari stok (dalam unit ingredient)
+	StockBefore     float64 `gorm:"type:numeric(14,4);not null"` // Stok sebelum pengurangan
+	StockAfter      float64 `gorm:"type:numeric(14,4);not null"` // Stok setelah pengurangan
+	UnitID          uint    // Unit stok ingredient
+	Unit            Unit
+	UnitCost        float64   `gorm:"type:numeric(12,2);not null"`     // Harga pokok per unit stok saat dicatat
+	Reason          string    `gorm:"type:varchar(20);index;not null"` // expired, damaged, staff_meal, comp, other
+	Notes           string    `gorm:"type:text"`
+	WastedAt        time.Time `gorm:"index;not null"` // Waktu waste dicatat
+	UserID          *uint     // User yang mencatat
+	User            *User     `gorm:"constraint:OnDelete:SET NULL"`
+	StockMovementID *uint     // Movement pengurangan stok
+}
+
+// Value is the reduced quantity valued at the unit cost
+func (w WasteRecord) Value() float64 {
+	return w.QuantityReduced * w.UnitCost
+}
@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"strings"
+
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IngredientFilter struct {
+	Search     string
+	UnitID     uint     // 0 = semua unit
+	StockBelow *float64 // Kosong = tanpa batas stok
+}
+
+type IngredientRepository interface {
+	List(filter IngredientFilter, params utils.ListParams) ([]models.Ingredient, dto.PageMeta, error)
+	// LowStock loads the ingredients at or below their reorder point or
+	// minimum stock ordered by name
+	LowStock() ([]models.Ingredient, error)
+	// FindByID loads the ingredient with its stock unit and conversions
+	FindByID(id uint) (models.Ingredient, error)
+	// FindForUpdate loads the ingredient like FindByID and locks its row for
+	// the rest of the transaction
+	FindForUpdate(id uint) (models.Ingredient, error)
+	// FindByIDs loads the ingredients with the given IDs ordered by name,
+	// all ingredients when ids is empty
+	FindByIDs(ids []uint) ([]models.Ingredient, error)
+	// HasStockHistory reports whether the ingredient has stock movements or lots
+	HasStockHistory(id uint) (bool, error)
+	// Lock locks the ingredient rows for the rest of the transaction
+	Lock(ids []uint) error
+	// RecordMovement changes the stock through the ledger, failing with
+	// utils.ErrInsufficientStock when the stock would go negative
+	RecordMovement(movement *models.StockMovement) error
+	// Create saves a new ingredient, its stock is recorded through RecordMovement
+	Create(ingredient *models.Ingredient) error
+	// Update saves the ingredient without its stock and associations
+	Update(ingredient *models.Ingredient) error
+	Delete(ingredient *models.Ingredient) error
+}
+
+type gormIngredientRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormIngredientRepository) List(filter IngredientFilter, params utils.ListParams) ([]models.Ingredient, dto.PageMeta, error) {
+	var ingredients []models.Ingredient
+
+	query := r.db.Model(&models.Ingredient{})
+	if filter.Search != "" {
+		query = query.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(filter.Search)+"%")
+	}
+	if filter.UnitID != 0 {
+		query = query.Where("unit_id = ?", filter.UnitID)
+	}
+	if filter.StockBelow != nil {
+		query = query.Where("stock < ?", *filter.StockBelow)
+	}
+
+	meta, err := utils.Paginate(query, params, &ingredients, func(db *gorm.DB) *gorm.DB {
+		return db.Preload("Unit")
+	})
+	return ingredients, meta, err
+}
+
+func (r *gormIngredientRepository) LowStock() ([]models.Ingredient, error) {
+	var ingredients []models.Ingredient
+	err := r.db.Preload("Unit").
+		Where("(reorder_point > 0 AND stock <= reorder_point) OR (minimum_stock > 0 AND stock <= minimum_stock)").
+		Order("name").
+		Find(&ingredients).Error
+	return ingredients, err
+}
+
+func (r *gormIngredientRepository) FindByID(id uint) (models.Ingredient, error) {
+	var ingredient models.Ingredient
+	err := PreloadIngredientUnits(r.db).First(&ingredient, id).Error
+	if err != nil {
+		return ingredient, notFound(err, "ingredient", id)
+	}
+	return ingredient, nil
+}
+
+func (r *gormIngredientRepository) FindForUpdate(id uint) (models.Ingredient, error) {
+	var ingredient models.Ingredient
+	err := PreloadIngredientUnits(r.db).Clauses(clause.Locking{Strength: "UPDATE"}).First(&ingredient, id).Error
+	if err != nil {
+		return ingredient, notFound(err, "ingredient", id)
+	}
+	return ingredient, nil
+}
+
+func (r *gormIngredientRepository) FindByIDs(ids []uint) ([]models.Ingredient, error) {
+	var ingredients []models.Ingredient
+
+	query := PreloadIngredientUnits(r.db).Order("name")
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+
+	err := query.Find(&ingredients).Error
+	return ingredients, err
+}
+
+func (r *gormIngredientRepository) HasStockHistory(id uint) (bool, error) {
+	var movements, lots int64
+	if err := r.db.Model(&models.StockMovement{}).Where("ingredient_id = ?", id).Count(&movements).Error; err != nil {
+		return false, err
+	}
+	if err := r.db.Model(&models.StockLot{}).Where("ingredient_id = ?", id).Count(&lots).Error; err != nil {
+		return false, err
+	}
+	return movements > 0 || lots > 0, nil
+}
+
+func (r *gormIngredientRepository) Lock(ids []uint) error {
+	return utils.LockIngredients(r.db, ids)
+}
+
+func (r *gormIngredientRepository) RecordMovement(movement *models.StockMovement) error {
+	return notFound(utils.RecordStockMovement(r.db, movement), "ingredient", movement.IngredientID)
+}
+
+func (r *gormIngredientRepository) Create(ingredient *models.Ingredient) error {
+	return r.db.Omit(clause.Associations).Create(ingredient).Error
+}
+
+func (r *gormIngredientRepository) Update(ingredient *models.Ingredient) error {
+	return r.db.Omit("Stock", clause.Associations).Save(ingredient).Error
+}
+
+func (r *gormIngredientRepository) Delete(ingredient *models.Ingredient) error {
+	return r.db.Delete(ingredient).Error
+}
+
+// PreloadIngredientUnits memuat unit stok dan konversi unit ingredient
+func PreloadIngredientUnits(db *gorm.DB) *gorm.DB {
+	return db.Preload("Unit").
+		Preload("Conversions.Unit").
+		Preload("Conversions.ToUnit")
+}
@@ -0,0 +1,194 @@
+package repositories
+
+import (
+	"strings"
+
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/utils"
+
+	"gorm.io/gorm"
+)
+
+type MenuFilter struct {
+	Search        string
+	IngredientID  uint   // 0 = semua menu
+	AvailableOnly bool   // Tanpa menu yang ditandai habis, sisanya disaring service dari stok
+	IDs           []uint // nil = semua menu
+}
+
+type MenuRepository interface {
+	List(filter MenuFilter, params utils.ListParams) ([]models.Menu, dto.PageMeta, error)
+	// FindAll loads every menu matching filter with its recipe
+	FindAll(filter MenuFilter) ([]models.Menu, error)
+	// FindByID loads the menu with its recipe, units and ingredient costs
+	FindByID(id uint) (models.Menu, error)
+	// Create stores the menu and its recipe
+	Create(menu *models.Menu, recipe []models.MenuIngredient) error
+	// Update saves the menu and replaces its recipe
+	Update(menu *models.Menu, recipe []models.MenuIngredient) error
+	// Delete removes the menu, its recipe and its modifiers
+	Delete(menu *models.Menu) error
+	// ReplaceModifiers replaces the modifier groups of the menu with groups,
+	// including their options and recipe changes
+	ReplaceModifiers(menu *models.Menu, groups []models.MenuModifierGroup) error
+	// SetSoldOut changes only the sold out flag of the menu
+	SetSoldOut(id uint, soldOut bool) error
+}
+
+type gormMenuRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormMenuRepository) List(filter MenuFilter, params utils.ListParams) ([]models.Menu, dto.PageMeta, error) {
+	var menus []models.Menu
+	meta, err := utils.Paginate(r.filter(filter), params, &menus, PreloadMenuRecipe)
+	return menus, meta, err
+}
+
+func (r *gormMenuRepository) FindAll(filter MenuFilter) ([]models.Menu, error) {
+	var menus []models.Menu
+	err := PreloadMenuRecipe(r.filter(filter)).Order("id").Find(&menus).Error
+	return menus, err
+}
+
+func (r *gormMenuRepository) filter(filter MenuFilter) *gorm.DB {
+	query := r.db.Model(&models.Menu{})
+	if filter.Search != "" {
+		query = query.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(filter.Search)+"%")
+	}
+	if filter.IngredientID != 0 {
+		query = query.Where("id IN (?)", r.db.Model(&models.MenuIngredient{}).
+			Select("menu_id").
+			Where("ingredient_id = ?", filter.IngredientID))
+	}
+	if filter.AvailableOnly {
+		query = query.Where("sold_out = ?", false)
+	}
+	if filter.IDs != nil {
+		query = query.Where("id IN ?", filter.IDs)
+	}
+	return query
+}
+
+func (r *gormMenuRepository) FindByID(id uint) (models.Menu, error) {
+	var menu models.Menu
+	if err := PreloadMenuRecipe(r.db).First(&menu, id).Error; err != nil {
+		return menu, notFound(err, "menu", id)
+	}
+	return menu, nil
+}
+
+func (r *gormMenuRepository) Create(menu *models.Menu, recipe []models.MenuIngredient) error {
+	if err := r.db.Create(menu).Error; err != nil {
+		return err
+	}
+	return r.createRecipe(menu, recipe)
+}
+
+func (r *gormMenuRepository) Update(menu *models.Menu, recipe []models.MenuIngredient) error {
+	if err := r.db.Omit("MenuIngredients").Save(menu).Error; err != nil {
+		return err
+	}
+
+	if err := r.db.Where("menu_id = ?", menu.ID).Delete(&models.MenuIngredient{}).Error; err != nil {
+		return err
+	}
+
+	return r.createRecipe(menu, recipe)
+}
+
+func (r *gormMenuRepository) Delete(menu *models.Menu) error {
+	// Hapus resep dulu (foreign key constraint)
+	if err := r.db.Where("menu_id = ?", menu.ID).Delete(&models.MenuIngredient{}).Error; err != nil {
+		return err
+	}
+	if err := r.deleteModifiers(menu.ID); err != nil {
+		return err
+	}
+	return r.db.Delete(menu).Error
+}
+
+func (r *gormMenuRepository) ReplaceModifiers(menu *models.Menu, groups []models.MenuModifierGroup) error {
+	if err := r.deleteModifiers(menu.ID); err != nil {
+		return err
+	}
+
+	for i := range groups {
+		group := &groups[i]
+		group.MenuID = menu.ID
+		if err := r.db.Omit("Options").Create(group).Error; err != nil {
+			return err
+		}
+
+		for j := range group.Options {
+			option := &group.Options[j]
+			option.GroupID = group.ID
+			if err := r.db.Omit("Ingredients").Create(option).Error; err != nil {
+				return err
+			}
+
+			for k := range option.Ingredients {
+				option.Ingredients[k].OptionID = option.ID
+				if err := r.db.Create(&option.Ingredients[k]).Error; err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (r *gormMenuRepository) deleteModifiers(menuID uint) error {
+	groupIDs := r.db.Model(&models.MenuModifierGroup{}).Select("id").Where("menu_id = ?", menuID)
+	optionIDs := r.db.Model(&models.MenuModifierOption{}).Select("id").Where("group_id IN (?)", groupIDs)
+
+	if err := r.db.Where("option_id IN (?)", optionIDs).Delete(&models.MenuModifierIngredient{}).Error; err != nil {
+		return err
+	}
+	if err := r.db.Where("group_id IN (?)", groupIDs).Delete(&models.MenuModifierOption{}).Error; err != nil {
+		return err
+	}
+	return r.db.Where("menu_id = ?", menuID).Delete(&models.MenuModifierGroup{}).Error
+}
+
+func (r *gormMenuRepository) SetSoldOut(id uint, soldOut bool) error {
+	result := r.db.Model(&models.Menu{}).Where("id = ?", id).Update("sold_out", soldOut)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return notFound(gorm.ErrRecordNotFound, "menu", id)
+	}
+	return nil
+}
+
+func (r *gormMenuRepository) createRecipe(menu *models.Menu, recipe []models.MenuIngredient) error {
+	for i := range recipe {
+		recipe[i].MenuID = menu.ID
+		if err := r.db.Create(&recipe[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PreloadMenuRecipe memuat resep menu beserta unit dan harga pokok ingredient,
+// serta kelompok modifier dan perubahan resepnya
+func PreloadMenuRecipe(db *gorm.DB) *gorm.DB {
+	return db.Preload("MenuIngredients").
+		Preload("MenuIngredients.Unit").
+		Preload("MenuIngredients.Ingredient").
+		Preload("MenuIngredients.Ingredient.Unit").
+		Preload("MenuIngredients.Ingredient.Conversions.Unit").
+		Preload("MenuIngredients.Ingredient.Conversions.ToUnit").
+		Preload("ModifierGroups", orderBySortOrder).
+		Preload("ModifierGroups.Options", orderBySortOrder).
+		Preload("ModifierGroups.Options.Ingredients").
+		Preload("ModifierGroups.Options.Ingredients.Unit").
+		Preload("ModifierGroups.Options.Ingredients.Ingredient")
+}
+
+func orderBySortOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("sort_order, id")
+}
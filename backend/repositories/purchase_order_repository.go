@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"AwisPalace_IngredientManagement/models"
+
+	"gorm.io/gorm"
+)
+
+type PurchaseOrderRepository interface {
+	// FindByID loads the purchase order with its supplier and lines
+	FindByID(id uint) (models.PurchaseOrder, error)
+	// Create saves the purchase order together with its lines
+	Create(purchaseOrder *models.PurchaseOrder) error
+	// OpenLines loads the lines of draft and sent purchase orders that are
+	// not fully received yet, with their units
+	OpenLines() ([]models.PurchaseOrderLine, error)
+}
+
+type gormPurchaseOrderRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormPurchaseOrderRepository) FindByID(id uint) (models.PurchaseOrder, error) {
+	var purchaseOrder models.PurchaseOrder
+	err := PreloadPurchaseOrder(r.db).First(&purchaseOrder, id).Error
+	if err != nil {
+		return purchaseOrder, notFound(err, "purchase order", id)
+	}
+	return purchaseOrder, nil
+}
+
+func (r *gormPurchaseOrderRepository) Create(purchaseOrder *models.PurchaseOrder) error {
+	return r.db.Omit("Supplier", "Lines.Ingredient", "Lines.Unit").Create(purchaseOrder).Error
+}
+
+func (r *gormPurchaseOrderRepository) OpenLines() ([]models.PurchaseOrderLine, error) {
+	var lines []models.PurchaseOrderLine
+	err := r.db.Preload("Unit").
+		Where("purchase_order_id IN (?)", r.db.Model(&models.PurchaseOrder{}).
+			Select("id").
+			Where("status IN ?", []string{
+				models.PurchaseOrderStatusDraft,
+				models.PurchaseOrderStatusSent,
+				models.PurchaseOrderStatusPartiallyReceived,
+			})).
+		Where("received_quantity < quantity").
+		Order("id").
+		Find(&lines).Error
+	return lines, err
+}
+
+// PreloadPurchaseOrder preloads the supplier and the lines with their
+// ingredients and units
+func PreloadPurchaseOrder(db *gorm.DB) *gorm.DB {
+	return db.Preload("Supplier").
+		Preload("Lines").
+		Preload("Lines.Ingredient").
+		Preload("Lines.Unit")
+}
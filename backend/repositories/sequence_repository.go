@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"AwisPalace_IngredientManagement/utils"
+
+	"gorm.io/gorm"
+)
+
+type SequenceRepository interface {
+	// Next reserves the next value of the named document sequence
+	Next(name string) (int64, error)
+}
+
+type gormSequenceRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormSequenceRepository) Next(name string) (int64, error) {
+	return utils.NextSequence(r.db, name)
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"time"
+
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/utils"
+
+	"gorm.io/gorm"
+)
+
+type StockLotRepository interface {
+	// ListByIngredient loads the lots of an ingredient that still hold stock,
+	// in the order they are consumed
+	ListByIngredient(ingredientID uint) ([]models.StockLot, error)
+	// ExpiringBefore loads the lots that still hold stock and expire before
+	// the given time, soonest first
+	ExpiringBefore(before time.Time) ([]models.StockLot, error)
+	// Return puts fraction (0-1) of what a stock reduction drew from its lots
+	// back into them
+	Return(lots []models.StockReductionLot, fraction float64) error
+}
+
+type gormStockLotRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormStockLotRepository) ListByIngredient(ingredientID uint) ([]models.StockLot, error) {
+	var lots []models.StockLot
+	err := preloadStockLot(r.db).
+		Where("ingredient_id = ? AND remaining > 0", ingredientID).
+		Order("expiry_date IS NULL, expiry_date, received_date, id").
+		Find(&lots).Error
+	return lots, err
+}
+
+func (r *gormStockLotRepository) ExpiringBefore(before time.Time) ([]models.StockLot, error) {
+	var lots []models.StockLot
+	err := preloadStockLot(r.db).
+		Where("remaining > 0 AND expiry_date IS NOT NULL AND expiry_date < ?", before).
+		Order("expiry_date, id").
+		Find(&lots).Error
+	return lots, err
+}
+
+func (r *gormStockLotRepository) Return(lots []models.StockReductionLot, fraction float64) error {
+	return utils.ReturnToLots(r.db, lots, fraction)
+}
+
+// preloadStockLot memuat ingredient dan unit lot
+func preloadStockLot(db *gorm.DB) *gorm.DB {
+	return db.Preload("Ingredient").Preload("Unit")
+}
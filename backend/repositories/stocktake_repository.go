@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StocktakeFilter struct {
+	Status string
+}
+
+type StocktakeRepository interface {
+	List(filter StocktakeFilter, params utils.ListParams) ([]models.Stocktake, dto.PageMeta, error)
+	// FindByID loads the stocktake with its lines, ingredients and units
+	FindByID(id uint) (models.Stocktake, error)
+	// FindForUpdate loads the stocktake like FindByID and locks its row for
+	// the rest of the transaction
+	FindForUpdate(id uint) (models.Stocktake, error)
+	// Create stores the stocktake with its lines
+	Create(stocktake *models.Stocktake) error
+	// Update saves the stocktake without its lines
+	Update(stocktake *models.Stocktake) error
+	UpdateLine(line *models.StocktakeLine) error
+	// Delete removes the stocktake and its lines
+	Delete(stocktake *models.Stocktake) error
+}
+
+type gormStocktakeRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormStocktakeRepository) List(filter StocktakeFilter, params utils.ListParams) ([]models.Stocktake, dto.PageMeta, error) {
+	var stocktakes []models.Stocktake
+
+	query := r.db.Model(&models.Stocktake{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	meta, err := utils.Paginate(query, params, &stocktakes, PreloadStocktake)
+	return stocktakes, meta, err
+}
+
+func (r *gormStocktakeRepository) FindByID(id uint) (models.Stocktake, error) {
+	var stocktake models.Stocktake
+	if err := PreloadStocktake(r.db).First(&stocktake, id).Error; err != nil {
+		return stocktake, notFound(err, "stocktake", id)
+	}
+	return stocktake, nil
+}
+
+func (r *gormStocktakeRepository) FindForUpdate(id uint) (models.Stocktake, error) {
+	var stocktake models.Stocktake
+	if err := PreloadStocktake(r.db).Clauses(clause.Locking{Strength: "UPDATE"}).First(&stocktake, id).Error; err != nil {
+		return stocktake, notFound(err, "stocktake", id)
+	}
+	return stocktake, nil
+}
+
+func (r *gormStocktakeRepository) Create(stocktake *models.Stocktake) error {
+	return r.db.Create(stocktake).Error
+}
+
+func (r *gormStocktakeRepository) Update(stocktake *models.Stocktake) error {
+	return r.db.Omit("Lines", "CreatedBy", "PostedBy").Save(stocktake).Error
+}
+
+func (r *gormStocktakeRepository) UpdateLine(line *models.StocktakeLine) error {
+	return r.db.Omit("Ingredient", "Unit").Save(line).Error
+}
+
+func (r *gormStocktakeRepository) Delete(stocktake *models.Stocktake) error {
+	if err := r.db.Where("stocktake_id = ?", stocktake.ID).Delete(&models.StocktakeLine{}).Error; err != nil {
+		return err
+	}
+	return r.db.Delete(stocktake).Error
+}
+
+// PreloadStocktake memuat baris stocktake beserta ingredient dan unitnya
+func PreloadStocktake(db *gorm.DB) *gorm.DB {
+	return db.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).
+		Preload("Lines.Ingredient").
+		Preload("Lines.Unit")
+}
@@ -0,0 +1,102 @@
+// Package repositories wraps the database access of the services behind
+// interfaces, so the business rules can be tested with fakes.
+package repositories
+
+import (
+	"errors"
+	"fmt"
+
+	"AwisPalace_IngredientManagement/utils"
+
+	"gorm.io/gorm"
+)
+
+// ErrNotFound is wrapped by the errors of Find methods when no row matches
+var ErrNotFound = errors.New("not found")
+
+// Store gives access to the repositories. The repositories of the Store passed
+// to the Transaction callback share one database transaction.
+type Store interface {
+	Ingredients() IngredientRepository
+	Menus() MenuRepository
+	Units() UnitRepository
+	Transactions() TransactionRepository
+	Users() UserRepository
+	Sequences() SequenceRepository
+	Stocktakes() StocktakeRepository
+	Waste() WasteRepository
+	StockLots() StockLotRepository
+	Suppliers() SupplierRepository
+	PurchaseOrders() PurchaseOrderRepository
+
+	// Transaction runs fn in a transaction that is committed when fn returns
+	// nil. fn may run more than once when the database aborts it on a deadlock.
+	Transaction(fn func(store Store) error) error
+}
+
+type gormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore returns a Store backed by db
+func NewGormStore(db *gorm.DB) Store {
+	return &gormStore{db: db}
+}
+
+func (s *gormStore) Ingredients() IngredientRepository {
+	return &gormIngredientRepository{db: s.db}
+}
+
+func (s *gormStore) Menus() MenuRepository {
+	return &gormMenuRepository{db: s.db}
+}
+
+func (s *gormStore) Units() UnitRepository {
+	return &gormUnitRepository{db: s.db}
+}
+
+func (s *gormStore) Transactions() TransactionRepository {
+	return &gormTransactionRepository{db: s.db}
+}
+
+func (s *gormStore) Users() UserRepository {
+	return &gormUserRepository{db: s.db}
+}
+
+func (s *gormStore) Sequences() SequenceRepository {
+	return &gormSequenceRepository{db: s.db}
+}
+
+func (s *gormStore) Stocktakes() StocktakeRepository {
+	return &gormStocktakeRepository{db: s.db}
+}
+
+func (s *gormStore) Waste() WasteRepository {
+	return &gormWasteRepository{db: s.db}
+}
+
+func (s *gormStore) StockLots() StockLotRepository {
+	return &gormStockLotRepository{db: s.db}
+}
+
+func (s *gormStore) Suppliers() SupplierRepository {
+	return &gormSupplierRepository{db: s.db}
+}
+
+func (s *gormStore) PurchaseOrders() PurchaseOrderRepository {
+	return &gormPurchaseOrderRepository{db: s.db}
+}
+
+func (s *gormStore) Transaction(fn func(store Store) error) error {
+	return utils.RunInTransaction(s.db, func(tx *gorm.DB) error {
+		return fn(&gormStore{db: tx})
+	})
+}
+
+// notFound turns gorm.ErrRecordNotFound into a message wrapping ErrNotFound
+func notFound(err error, entity string, id uint) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%s with ID %d %w", entity, id, ErrNotFound)
+	}
+	return err
+}
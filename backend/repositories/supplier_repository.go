@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/utils"
+
+	"gorm.io/gorm"
+)
+
+type SupplierFilter struct {
+	Search       string
+	IngredientID uint // 0 = semua supplier
+}
+
+type SupplierRepository interface {
+	List(filter SupplierFilter, params utils.ListParams) ([]models.Supplier, dto.PageMeta, error)
+	// FindByID loads the supplier with its ingredients and their units
+	FindByID(id uint) (models.Supplier, error)
+	Create(supplier *models.Supplier) error
+	// Update saves the supplier without its ingredients
+	Update(supplier *models.Supplier) error
+	// Delete removes the supplier and its ingredient links
+	Delete(supplier *models.Supplier) error
+
+	// FindIngredient loads the link between a supplier and an ingredient
+	FindIngredient(supplierID, ingredientID uint) (models.SupplierIngredient, error)
+	// ListByIngredient loads the suppliers of an ingredient with their pack units
+	ListByIngredient(ingredientID uint) ([]models.SupplierIngredient, error)
+	SaveIngredient(link *models.SupplierIngredient) error
+	DeleteIngredient(link *models.SupplierIngredient) error
+}
+
+type gormSupplierRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormSupplierRepository) List(filter SupplierFilter, params utils.ListParams) ([]models.Supplier, dto.PageMeta, error) {
+	var suppliers []models.Supplier
+
+	query := r.db.Model(&models.Supplier{})
+	if filter.Search != "" {
+		pattern := "%" + strings.ToLower(filter.Search) + "%"
+		query = query.Where("LOWER(name) LIKE ? OR LOWER(contact_name) LIKE ?", pattern, pattern)
+	}
+	if filter.IngredientID != 0 {
+		query = query.Where("id IN (?)", r.db.Model(&models.SupplierIngredient{}).
+			Select("supplier_id").
+			Where("ingredient_id = ?", filter.IngredientID))
+	}
+
+	meta, err := utils.Paginate(query, params, &suppliers, nil)
+	return suppliers, meta, err
+}
+
+func (r *gormSupplierRepository) FindByID(id uint) (models.Supplier, error) {
+	var supplier models.Supplier
+	err := r.db.Preload("Ingredients", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).
+		Preload("Ingredients.Ingredient.Unit").
+		Preload("Ingredients.Ingredient.Conversions.Unit").
+		Preload("Ingredients.Ingredient.Conversions.ToUnit").
+		Preload("Ingredients.PackUnit").
+		First(&supplier, id).Error
+	if err != nil {
+		return supplier, notFound(err, "supplier", id)
+	}
+	return supplier, nil
+}
+
+func (r *gormSupplierRepository) Create(supplier *models.Supplier) error {
+	return r.db.Omit("Ingredients").Create(supplier).Error
+}
+
+func (r *gormSupplierRepository) Update(supplier *models.Supplier) error {
+	return r.db.Omit("Ingredients").Save(supplier).Error
+}
+
+func (r *gormSupplierRepository) Delete(supplier *models.Supplier) error {
+	if err := r.db.Unscoped().Where("supplier_id = ?", supplier.ID).Delete(&models.SupplierIngredient{}).Error; err != nil {
+		return err
+	}
+	return r.db.Delete(supplier).Error
+}
+
+func (r *gormSupplierRepository) FindIngredient(supplierID, ingredientID uint) (models.SupplierIngredient, error) {
+	var link models.SupplierIngredient
+	err := r.db.Preload("PackUnit").
+		Where("supplier_id = ? AND ingredient_id = ?", supplierID, ingredientID).
+		First(&link).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return link, fmt.Errorf("ingredient with ID %d of supplier %d %w", ingredientID, supplierID, ErrNotFound)
+	}
+	return link, err
+}
+
+func (r *gormSupplierRepository) ListByIngredient(ingredientID uint) ([]models.SupplierIngredient, error) {
+	var links []models.SupplierIngredient
+	err := r.db.Joins("Supplier").
+		Preload("PackUnit").
+		Where("supplier_ingredients.ingredient_id = ?", ingredientID).
+		Order("supplier_ingredients.id").
+		Find(&links).Error
+	return links, err
+}
+
+func (r *gormSupplierRepository) SaveIngredient(link *models.SupplierIngredient) error {
+	return r.db.Omit("Supplier", "Ingredient", "PackUnit").Save(link).Error
+}
+
+// DeleteIngredient removes the link for good so the ingredient can be linked again
+func (r *gormSupplierRepository) DeleteIngredient(link *models.SupplierIngredient) error {
+	return r.db.Unscoped().Delete(link).Error
+}
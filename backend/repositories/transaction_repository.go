@@ -0,0 +1,208 @@
+package repositories
+
+import (
+	"time"
+
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TransactionFilter struct {
+	StartDate time.Time
+	EndDate   time.Time
+	Status    string
+	MenuID    uint // 0 = semua menu
+	MinTotal  *float64
+	MaxTotal  *float64
+}
+
+// IngredientUsage is the quantity of an ingredient deducted by sales, in the
+// unit it was deducted in
+type IngredientUsage struct {
+	IngredientID uint
+	UnitID       uint
+	Quantity     float64
+}
+
+type TransactionRepository interface {
+	List(filter TransactionFilter, params utils.ListParams) ([]models.Transaction, dto.PageMeta, error)
+	// FindByID loads the transaction with its items, stock reductions and refunds
+	FindByID(id uint) (models.Transaction, error)
+	// FindForUpdate loads the transaction like FindByID and locks its row for
+	// the rest of the transaction, so concurrent cancels and refunds see the
+	// status and refunded quantities of each other
+	FindForUpdate(id uint) (models.Transaction, error)
+	Create(transaction *models.Transaction) error
+	// CreateItem stores the item with its modifiers
+	CreateItem(item *models.TransactionItem) error
+	CreateStockReduction(reduction *models.StockReduction) error
+	UpdateItemCost(itemID uint, cost float64) error
+	UpdateTotal(transactionID uint, total float64) error
+	// UpdateStatus saves the status, refunded amount and cancellation of the
+	// transaction
+	UpdateStatus(transaction *models.Transaction) error
+	UpdateRefundedQuantity(itemID uint, quantity int) error
+	// CreateRefund stores the refund with its items
+	CreateRefund(refund *models.Refund) error
+	// Delete removes the transaction with its items, modifiers and stock
+	// reductions. TransactionItems must be loaded with StockReductions.
+	Delete(transaction *models.Transaction) error
+	// Usage sums the stock reductions of the transactions between start and
+	// end that were not cancelled, per ingredient and unit, less the share of
+	// refunds that returned the stock
+	Usage(start, end time.Time) ([]IngredientUsage, error)
+}
+
+type gormTransactionRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormTransactionRepository) List(filter TransactionFilter, params utils.ListParams) ([]models.Transaction, dto.PageMeta, error) {
+	var transactions []models.Transaction
+
+	query := r.db.Model(&models.Transaction{}).
+		Where("transaction_date BETWEEN ? AND ?", filter.StartDate, filter.EndDate)
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.MenuID != 0 {
+		query = query.Where("id IN (?)", r.db.Model(&models.TransactionItem{}).
+			Select("transaction_id").
+			Where("menu_id = ?", filter.MenuID))
+	}
+	if filter.MinTotal != nil {
+		query = query.Where("total_amount >= ?", *filter.MinTotal)
+	}
+	if filter.MaxTotal != nil {
+		query = query.Where("total_amount <= ?", *filter.MaxTotal)
+	}
+
+	meta, err := utils.Paginate(query, params, &transactions, PreloadTransaction)
+	return transactions, meta, err
+}
+
+func (r *gormTransactionRepository) FindByID(id uint) (models.Transaction, error) {
+	var transaction models.Transaction
+	if err := PreloadTransaction(r.db).First(&transaction, id).Error; err != nil {
+		return transaction, notFound(err, "transaction", id)
+	}
+	return transaction, nil
+}
+
+func (r *gormTransactionRepository) FindForUpdate(id uint) (models.Transaction, error) {
+	var transaction models.Transaction
+	if err := PreloadTransaction(r.db).Clauses(clause.Locking{Strength: "UPDATE"}).First(&transaction, id).Error; err != nil {
+		return transaction, notFound(err, "transaction", id)
+	}
+	return transaction, nil
+}
+
+func (r *gormTransactionRepository) Create(transaction *models.Transaction) error {
+	return r.db.Create(transaction).Error
+}
+
+func (r *gormTransactionRepository) CreateItem(item *models.TransactionItem) error {
+	return r.db.Create(item).Error
+}
+
+func (r *gormTransactionRepository) CreateStockReduction(reduction *models.StockReduction) error {
+	return r.db.Create(reduction).Error
+}
+
+func (r *gormTransactionRepository) UpdateItemCost(itemID uint, cost float64) error {
+	return r.db.Model(&models.TransactionItem{}).Where("id = ?", itemID).Update("cost", cost).Error
+}
+
+func (r *gormTransactionRepository) UpdateTotal(transactionID uint, total float64) error {
+	return r.db.Model(&models.Transaction{}).Where("id = ?", transactionID).Update("total_amount", total).Error
+}
+
+func (r *gormTransactionRepository) UpdateStatus(transaction *models.Transaction) error {
+	return r.db.Model(&models.Transaction{}).Where("id = ?", transaction.ID).Updates(map[string]interface{}{
+		"status":          transaction.Status,
+		"refunded_amount": transaction.RefundedAmount,
+		"cancel_reason":   transaction.CancelReason,
+		"cancelled_at":    transaction.CancelledAt,
+		"cancelled_by_id": transaction.CancelledByID,
+	}).Error
+}
+
+func (r *gormTransactionRepository) UpdateRefundedQuantity(itemID uint, quantity int) error {
+	return r.db.Model(&models.TransactionItem{}).Where("id = ?", itemID).Update("refunded_quantity", quantity).Error
+}
+
+func (r *gormTransactionRepository) CreateRefund(refund *models.Refund) error {
+	if err := r.db.Omit("Items").Create(refund).Error; err != nil {
+		return err
+	}
+
+	for i := range refund.Items {
+		refund.Items[i].RefundID = refund.ID
+		if err := r.db.Create(&refund.Items[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *gormTransactionRepository) Delete(transaction *models.Transaction) error {
+	for _, item := range transaction.TransactionItems {
+		for _, reduction := range item.StockReductions {
+			if err := r.db.Where("stock_reduction_id = ?", reduction.ID).Delete(&models.StockReductionLot{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := r.db.Where("transaction_item_id = ?", item.ID).Delete(&models.StockReduction{}).Error; err != nil {
+			return err
+		}
+		if err := r.db.Where("transaction_item_id = ?", item.ID).Delete(&models.TransactionItemModifier{}).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := r.db.Where("transaction_id = ?", transaction.ID).Delete(&models.TransactionItem{}).Error; err != nil {
+		return err
+	}
+	return r.db.Delete(transaction).Error
+}
+
+// PreloadTransaction memuat item, modifier, pengurangan stok dan refund transaksi
+func PreloadTransaction(db *gorm.DB) *gorm.DB {
+	return db.Preload("TransactionItems").
+		Preload("TransactionItems.Menu").
+		Preload("TransactionItems.Modifiers").
+		Preload("TransactionItems.StockReductions").
+		Preload("TransactionItems.StockReductions.Ingredient").
+		Preload("TransactionItems.StockReductions.Unit").
+		Preload("TransactionItems.StockReductions.Lots.StockLot").
+		Preload("Refunds").
+		Preload("Refunds.Items")
+}
+
+func (r *gormTransactionRepository) Usage(start, end time.Time) ([]IngredientUsage, error) {
+	// Porsi yang direfund dengan restock dikembalikan ke stok, jadi tidak
+	// dihitung sebagai pemakaian
+	restocked := r.db.Model(&models.RefundItem{}).
+		Select("transaction_item_id, SUM(quantity) AS quantity").
+		Where("restocked = ?", true).
+		Group("transaction_item_id")
+
+	var usage []IngredientUsage
+	err := r.db.Model(&models.StockReduction{}).
+		Select("stock_reductions.ingredient_id, stock_reductions.unit_id, "+
+			"SUM(stock_reductions.quantity_reduced * (transaction_items.quantity - COALESCE(restocked.quantity, 0)) / NULLIF(transaction_items.quantity, 0)) AS quantity").
+		Joins("JOIN transaction_items ON transaction_items.id = stock_reductions.transaction_item_id AND transaction_items.deleted_at IS NULL").
+		Joins("JOIN transactions ON transactions.id = transaction_items.transaction_id AND transactions.deleted_at IS NULL").
+		Joins("LEFT JOIN (?) AS restocked ON restocked.transaction_item_id = transaction_items.id", restocked).
+		Where("transactions.transaction_date BETWEEN ? AND ?", start, end).
+		Where("transactions.status <> ?", models.TransactionStatusCancelled).
+		Group("stock_reductions.ingredient_id, stock_reductions.unit_id").
+		Scan(&usage).Error
+	return usage, err
+}
@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"strings"
+
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/utils"
+
+	"gorm.io/gorm"
+)
+
+type UnitFilter struct {
+	Search    string
+	Dimension string
+}
+
+type UnitRepository interface {
+	List(filter UnitFilter, params utils.ListParams) ([]models.Unit, dto.PageMeta, error)
+	FindByID(id uint) (models.Unit, error)
+	Create(unit *models.Unit) error
+	Update(unit *models.Unit) error
+	Delete(unit *models.Unit) error
+}
+
+type gormUnitRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormUnitRepository) List(filter UnitFilter, params utils.ListParams) ([]models.Unit, dto.PageMeta, error) {
+	var units []models.Unit
+
+	query := r.db.Model(&models.Unit{})
+	if filter.Search != "" {
+		pattern := "%" + strings.ToLower(filter.Search) + "%"
+		query = query.Where("LOWER(name) LIKE ? OR LOWER(symbol) LIKE ?", pattern, pattern)
+	}
+	if filter.Dimension != "" {
+		query = query.Where("dimension = ?", filter.Dimension)
+	}
+
+	meta, err := utils.Paginate(query, params, &units, nil)
+	return units, meta, err
+}
+
+func (r *gormUnitRepository) FindByID(id uint) (models.Unit, error) {
+	var unit models.Unit
+	if err := r.db.First(&unit, id).Error; err != nil {
+		return unit, notFound(err, "unit", id)
+	}
+	return unit, nil
+}
+
+func (r *gormUnitRepository) Create(unit *models.Unit) error {
+	return r.db.Create(unit).Error
+}
+
+func (r *gormUnitRepository) Update(unit *models.Unit) error {
+	return r.db.Save(unit).Error
+}
+
+func (r *gormUnitRepository) Delete(unit *models.Unit) error {
+	return r.db.Delete(unit).Error
+}
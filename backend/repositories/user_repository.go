@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"strings"
+
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/utils"
+
+	"gorm.io/gorm"
+)
+
+type UserFilter struct {
+	Search string
+	Role   string
+}
+
+type UserRepository interface {
+	List(filter UserFilter, params utils.ListParams) ([]models.User, dto.PageMeta, error)
+	FindByID(id uint) (models.User, error)
+	CountByRole(role string) (int64, error)
+	UpdateRole(user *models.User, role string) error
+}
+
+type gormUserRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormUserRepository) List(filter UserFilter, params utils.ListParams) ([]models.User, dto.PageMeta, error) {
+	var users []models.User
+
+	query := r.db.Model(&models.User{})
+	if filter.Search != "" {
+		pattern := "%" + strings.ToLower(filter.Search) + "%"
+		query = query.Where("LOWER(name) LIKE ? OR LOWER(email) LIKE ?", pattern, pattern)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+
+	meta, err := utils.Paginate(query, params, &users, nil)
+	return users, meta, err
+}
+
+func (r *gormUserRepository) FindByID(id uint) (models.User, error) {
+	var user models.User
+	if err := r.db.First(&user, id).Error; err != nil {
+		return user, notFound(err, "user", id)
+	}
+	return user, nil
+}
+
+func (r *gormUserRepository) CountByRole(role string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.User{}).Where("role = ?", role).Count(&count).Error
+	return count, err
+}
+
+func (r *gormUserRepository) UpdateRole(user *models.User, role string) error {
+	if err := r.db.Model(user).Update("role", role).Error; err != nil {
+		return err
+	}
+	user.Role = role
+	return nil
+}
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"time"
+
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/utils"
+
+	"gorm.io/gorm"
+)
+
+type WasteFilter struct {
+	StartDate    time.Time // Kosong = tanpa batas awal
+	EndDate      time.Time // Kosong = tanpa batas akhir
+	Reason       string
+	IngredientID uint // 0 = semua ingredient
+}
+
+type WasteRepository interface {
+	List(filter WasteFilter, params utils.ListParams) ([]models.WasteRecord, dto.PageMeta, error)
+	// FindAll loads every matching record oldest first, for reports and exports
+	FindAll(filter WasteFilter) ([]models.WasteRecord, error)
+	// FindByID loads the record with its ingredient and units
+	FindByID(id uint) (models.WasteRecord, error)
+	Create(record *models.WasteRecord) error
+	Update(record *models.WasteRecord) error
+}
+
+type gormWasteRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormWasteRepository) List(filter WasteFilter, params utils.ListParams) ([]models.WasteRecord, dto.PageMeta, error) {
+	var records []models.WasteRecord
+
+	query := r.filter(r.db.Model(&models.WasteRecord{}), filter)
+
+	meta, err := utils.Paginate(query, params, &records, PreloadWasteRecord)
+	return records, meta, err
+}
+
+func (r *gormWasteRepository) FindAll(filter WasteFilter) ([]models.WasteRecord, error) {
+	var records []models.WasteRecord
+	err := r.filter(PreloadWasteRecord(r.db), filter).
+		Order("wasted_at, id").
+		Find(&records).Error
+	return records, err
+}
+
+func (r *gormWasteRepository) FindByID(id uint) (models.WasteRecord, error) {
+	var record models.WasteRecord
+	if err := PreloadWasteRecord(r.db).First(&record, id).Error; err != nil {
+		return record, notFound(err, "waste record", id)
+	}
+	return record, nil
+}
+
+func (r *gormWasteRepository) Create(record *models.WasteRecord) error {
+	return r.db.Omit("Ingredient", "WastedUnit", "Unit", "User").Create(record).Error
+}
+
+func (r *gormWasteRepository) Update(record *models.WasteRecord) error {
+	return r.db.Omit("Ingredient", "WastedUnit", "Unit", "User").Save(record).Error
+}
+
+func (r *gormWasteRepository) filter(query *gorm.DB, filter WasteFilter) *gorm.DB {
+	if !filter.StartDate.IsZero() {
+		query = query.Where("wasted_at >= ?", filter.StartDate)
+	}
+	if !filter.EndDate.IsZero() {
+		query = query.Where("wasted_at <= ?", filter.EndDate)
+	}
+	if filter.Reason != "" {
+		query = query.Where("reason = ?", filter.Reason)
+	}
+	if filter.IngredientID != 0 {
+		query = query.Where("ingredient_id = ?", filter.IngredientID)
+	}
+	return query
+}
+
+// PreloadWasteRecord memuat ingredient dan unit record waste
+func PreloadWasteRecord(db *gorm.DB) *gorm.DB {
+	return db.Preload("Ingredient").
+		Preload("WastedUnit").
+		Preload("Unit")
+}
@@ -1,13 +1,74 @@
 package routes
 
 import (
+	"AwisPalace_IngredientManagement/config"
 	"AwisPalace_IngredientManagement/controllers"
+	"AwisPalace_IngredientManagement/middleware"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+	"AwisPalace_IngredientManagement/services"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
-func SetupRoutes(router *gin.Engine) {
+// Dependencies are the services the handlers are built from
+type Dependencies struct {
+	Transactions *services.TransactionService
+	Menus        *services.MenuService
+	Users        *services.UserService
+	Stocktakes   *services.StocktakeService
+	Waste        *services.WasteService
+	StockLots    *services.StockLotService
+	Suppliers    *services.SupplierService
+	Ingredients  *services.IngredientService
+	Units        *services.UnitService
+	Reorders     *services.ReorderService
+}
+
+// NewDependencies builds the services on top of db
+func NewDependencies(db *gorm.DB, cfg *config.Config) (Dependencies, error) {
+	store := repositories.NewGormStore(db)
+
+	codes, err := services.NewTransactionCodeGenerator(cfg.Transaction.CodeFormat, cfg.Transaction.OutletCode)
+	if err != nil {
+		return Dependencies{}, err
+	}
+
+	stocktakeCodes, err := services.NewStocktakeCodeGenerator(cfg.Transaction.OutletCode)
+	if err != nil {
+		return Dependencies{}, err
+	}
+
+	suppliers := services.NewSupplierService(store)
+
+	return Dependencies{
+		Transactions: services.NewTransactionService(store, codes),
+		Menus:        services.NewMenuService(store),
+		Users:        services.NewUserService(store),
+		Stocktakes:   services.NewStocktakeService(store, stocktakeCodes),
+		Waste:        services.NewWasteService(store),
+		StockLots:    services.NewStockLotService(store),
+		Suppliers:    suppliers,
+		Ingredients:  services.NewIngredientService(store, suppliers),
+		Units:        services.NewUnitService(store),
+		Reorders:     services.NewReorderService(store, suppliers),
+	}, nil
+}
+
+func SetupRoutes(router *gin.Engine, deps Dependencies) {
+	transactions := controllers.NewTransactionController(deps.Transactions)
+	menus := controllers.NewMenuController(deps.Menus)
+	users := controllers.NewUserController(deps.Users)
+	stocktakes := controllers.NewStocktakeController(deps.Stocktakes)
+	waste := controllers.NewWasteController(deps.Waste)
+	stockLots := controllers.NewStockLotController(deps.StockLots)
+	suppliers := controllers.NewSupplierController(deps.Suppliers)
+	ingredients := controllers.NewIngredientController(deps.Ingredients)
+	units := controllers.NewUnitController(deps.Units)
+	reorders := controllers.NewReorderController(deps.Reorders)
+
 	// route root
 	router.GET("/", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "Server running successfully!"})
@@ -20,53 +81,141 @@ func SetupRoutes(router *gin.Engine) {
 		auth.POST("/refresh", controllers.RefreshToken)
 	}
 
+	authenticate := middleware.AuthMiddleware(deps.Users)
+
+	// role access
+	anyRole := middleware.RequireRoles(models.RoleOwner, models.RoleManager, models.RoleCashier, models.RoleKitchen)
+	ownerOnly := middleware.RequireRoles(models.RoleOwner)
+	managers := middleware.RequireRoles(models.RoleOwner, models.RoleManager)
+	sales := middleware.RequireRoles(models.RoleOwner, models.RoleManager, models.RoleCashier)
+	stockKeepers := middleware.RequireRoles(models.RoleOwner, models.RoleManager, models.RoleKitchen)
+
+	// idempotent runs after the role check so rejected requests are not stored
+	idempotent := middleware.Idempotency()
+
 	// route users
-	userRoutes := router.Group("/users")
+	userRoutes := router.Group("/users", authenticate)
 	{
-		userRoutes.GET("/", controllers.GetUsers)
+		userRoutes.GET("/", managers, users.GetUsers)
+		userRoutes.PUT("/:id/role", ownerOnly, idempotent, users.UpdateUserRole)
 	}
 
 	// route units
-	unitRoutes := router.Group("/units")
+	unitRoutes := router.Group("/units", authenticate)
 	{
-		unitRoutes.GET("/", controllers.GetUnits)
-		unitRoutes.POST("/", controllers.PostUnits)
-		unitRoutes.PUT("/:id", controllers.UpdateUnit)
-		unitRoutes.DELETE("/:id", controllers.DeleteUnit)
+		unitRoutes.GET("/", anyRole, units.GetUnits)
+		unitRoutes.POST("/", managers, idempotent, units.PostUnits)
+		unitRoutes.PUT("/:id", managers, idempotent, units.UpdateUnit)
+		unitRoutes.DELETE("/:id", managers, idempotent, units.DeleteUnit)
 	}
 
 	// route ingredients
-	ingredientRoutes := router.Group("/ingredients")
+	ingredientRoutes := router.Group("/ingredients", authenticate)
+	{
+		ingredientRoutes.GET("/", anyRole, ingredients.GetIngredients)
+		ingredientRoutes.GET("/low-stock", stockKeepers, ingredients.GetLowStockIngredients)
+		ingredientRoutes.GET("/expiring-lots", stockKeepers, stockLots.GetExpiringLots)
+		ingredientRoutes.GET("/reorder-suggestions", managers, reorders.GetReorderSuggestions)
+		ingredientRoutes.POST("/", managers, idempotent, ingredients.PostIngredients)
+		ingredientRoutes.GET("/:id", anyRole, ingredients.GetIngredient)
+		ingredientRoutes.PUT("/:id", managers, idempotent, ingredients.UpdateIngredients)
+		ingredientRoutes.DELETE("/:id", managers, idempotent, ingredients.DeleteIngredients)
+		ingredientRoutes.GET("/:id/conversions", anyRole, controllers.GetIngredientConversions)
+		ingredientRoutes.POST("/:id/conversions", managers, idempotent, controllers.PostIngredientConversion)
+		ingredientRoutes.DELETE("/:id/conversions/:conversionId", managers, idempotent, controllers.DeleteIngredientConversion)
+		ingredientRoutes.GET("/:id/movements", stockKeepers, controllers.GetIngredientMovements)
+		ingredientRoutes.POST("/:id/movements", managers, idempotent, controllers.PostIngredientMovement)
+		ingredientRoutes.GET("/:id/lots", stockKeepers, stockLots.GetIngredientLots)
+	}
+
+	// route suppliers
+	supplierRoutes := router.Group("/suppliers", authenticate)
 	{
-		ingredientRoutes.GET("/", controllers.GetIngredients)
-		ingredientRoutes.POST("/", controllers.PostIngredients)
-		ingredientRoutes.PUT("/:id", controllers.UpdateIngredients)
-		ingredientRoutes.DELETE("/:id", controllers.DeleteIngredients)
+		supplierRoutes.GET("/", stockKeepers, suppliers.GetSuppliers)
+		supplierRoutes.GET("/:id", stockKeepers, suppliers.GetSupplier)
+		supplierRoutes.POST("/", managers, idempotent, suppliers.PostSupplier)
+		supplierRoutes.PUT("/:id", managers, idempotent, suppliers.UpdateSupplier)
+		supplierRoutes.DELETE("/:id", managers, idempotent, suppliers.DeleteSupplier)
+		supplierRoutes.PUT("/:id/ingredients/:ingredientId", managers, idempotent, suppliers.PutSupplierIngredient)
+		supplierRoutes.DELETE("/:id/ingredients/:ingredientId", managers, idempotent, suppliers.DeleteSupplierIngredient)
 	}
 
 	// route menus
-	menuRoutes := router.Group("/menus")
+	menuRoutes := router.Group("/menus", authenticate)
 	{
-		menuRoutes.GET("", controllers.GetMenus)
-		menuRoutes.GET("/:id", controllers.ShowMenu)
-		menuRoutes.POST("", controllers.PostMenu)
-		menuRoutes.PUT("/:id", controllers.UpdateMenu)
-		menuRoutes.DELETE("/:id", controllers.DeleteMenu)
+		menuRoutes.GET("", anyRole, menus.GetMenus)
+		menuRoutes.GET("/:id", anyRole, menus.ShowMenu)
+		menuRoutes.POST("", managers, idempotent, menus.PostMenu)
+		menuRoutes.PUT("/:id", managers, idempotent, menus.UpdateMenu)
+		menuRoutes.DELETE("/:id", managers, idempotent, menus.DeleteMenu)
+		menuRoutes.PUT("/:id/sold-out", anyRole, idempotent, menus.SetMenuSoldOut)
+		menuRoutes.PUT("/:id/modifiers", managers, idempotent, menus.SetMenuModifiers)
 	}
 
 	// route transactions
-	transactionRoutes := router.Group("/transactions")
+	transactionRoutes := router.Group("/transactions", authenticate)
+	{
+		transactionRoutes.GET("/", anyRole, transactions.GetTransactions)
+		transactionRoutes.GET("/:id", anyRole, transactions.GetTransaction)
+		transactionRoutes.POST("/", sales, idempotent, transactions.PostTransaction)
+		transactionRoutes.POST("/preview", sales, transactions.PreviewTransaction)
+		transactionRoutes.POST("/:id/cancel", managers, idempotent, transactions.CancelTransaction)
+		transactionRoutes.POST("/:id/refund", managers, idempotent, transactions.RefundTransaction)
+		transactionRoutes.DELETE("/:id", managers, idempotent, transactions.DeleteTransaction)
+	}
+
+	// route purchase orders
+	purchaseOrderRoutes := router.Group("/purchase-orders", authenticate)
+	{
+		purchaseOrderRoutes.GET("", stockKeepers, controllers.GetPurchaseOrders)
+		purchaseOrderRoutes.GET("/:id", stockKeepers, controllers.GetPurchaseOrder)
+		purchaseOrderRoutes.POST("", managers, idempotent, controllers.PostPurchaseOrder)
+		purchaseOrderRoutes.POST("/from-suggestions", managers, idempotent, reorders.PostReorderDrafts)
+		purchaseOrderRoutes.PUT("/:id", managers, idempotent, controllers.UpdatePurchaseOrder)
+		purchaseOrderRoutes.POST("/:id/send", managers, idempotent, controllers.SendPurchaseOrder)
+		purchaseOrderRoutes.DELETE("/:id", managers, idempotent, controllers.DeletePurchaseOrder)
+	}
+
+	// route goods receipts
+	receiptRoutes := router.Group("/receipts", authenticate)
+	{
+		receiptRoutes.GET("", stockKeepers, controllers.GetReceipts)
+		receiptRoutes.GET("/:id", stockKeepers, controllers.GetReceipt)
+		receiptRoutes.POST("", stockKeepers, idempotent, controllers.PostReceipt)
+	}
+
+	// route stocktakes
+	stocktakeRoutes := router.Group("/stocktakes", authenticate)
+	{
+		stocktakeRoutes.GET("", stockKeepers, stocktakes.GetStocktakes)
+		stocktakeRoutes.GET("/:id", stockKeepers, stocktakes.GetStocktake)
+		stocktakeRoutes.GET("/:id/variance", stockKeepers, stocktakes.GetStocktakeVariance)
+		stocktakeRoutes.POST("", stockKeepers, idempotent, stocktakes.PostStocktake)
+		stocktakeRoutes.POST("/:id/start", stockKeepers, idempotent, stocktakes.StartStocktake)
+		stocktakeRoutes.PUT("/:id/counts", stockKeepers, idempotent, stocktakes.CountStocktake)
+		stocktakeRoutes.POST("/:id/post", managers, idempotent, stocktakes.PostStocktakeVariance)
+		stocktakeRoutes.DELETE("/:id", managers, idempotent, stocktakes.DeleteStocktake)
+	}
+
+	// route waste
+	wasteRoutes := router.Group("/waste", authenticate)
+	{
+		wasteRoutes.GET("", stockKeepers, waste.GetWaste)
+		wasteRoutes.GET("/report", managers, waste.GetWasteReport)
+		wasteRoutes.POST("", stockKeepers, idempotent, waste.PostWaste)
+	}
+
+	// route stock alerts
+	stockAlertRoutes := router.Group("/stock-alerts", authenticate)
 	{
-		transactionRoutes.GET("/", controllers.GetTransactions)
-		transactionRoutes.GET("/:id", controllers.GetTransaction)
-		transactionRoutes.POST("/", controllers.PostTransaction)
-		transactionRoutes.DELETE("/:id", controllers.DeleteTransaction)
+		stockAlertRoutes.GET("", stockKeepers, controllers.GetStockAlerts)
+		stockAlertRoutes.POST("/:id/acknowledge", stockKeepers, idempotent, controllers.AcknowledgeStockAlert)
 	}
 
 	// route exports
-	exportRoutes := router.Group("/export")
+	exportRoutes := router.Group("/export", authenticate)
 	{
-		exportRoutes.GET("/transactions", controllers.ExportTransactions)
+		exportRoutes.GET("/transactions", managers, controllers.ExportTransactions)
 	}
 
 }
@@ -0,0 +1,865 @@
+package routes_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"AwisPalace_IngredientManagement/config"
+	"AwisPalace_IngredientManagement/middleware"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/routes"
+	"AwisPalace_IngredientManagement/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testAPI is the router of SetupRoutes on a fresh SQLite database, with a
+// token for an owner
+type testAPI struct {
+	t      *testing.T
+	router *gin.Engine
+	token  string
+}
+
+func newTestAPI(t *testing.T) *testAPI {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	dir := t.TempDir()
+	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(10000)&_txlock=immediate", filepath.Join(dir, "api.db"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+
+	if err := db.AutoMigrate(
+		&models.User{},
+		&models.Unit{},
+		&models.Ingredient{},
+		&models.IngredientUnitConversion{},
+		&models.Menu{},
+		&models.MenuIngredient{},
+		&models.Transaction{},
+		&models.TransactionItem{},
+		&models.StockReduction{},
+		&models.Refund{},
+		&models.RefundItem{},
+		&models.StockMovement{},
+		&models.StockAlert{},
+		&models.DocumentSequence{},
+		&models.IdempotencyKey{},
+		&models.Stocktake{},
+		&models.StocktakeLine{},
+		&models.WasteRecord{},
+		&models.PurchaseOrder{},
+		&models.PurchaseOrderLine{},
+		&models.GoodsReceipt{},
+		&models.GoodsReceiptItem{},
+		&models.StockLot{},
+		&models.StockReductionLot{},
+		&models.Supplier{},
+		&models.SupplierIngredient{},
+		&models.MenuModifierGroup{},
+		&models.MenuModifierOption{},
+		&models.MenuModifierIngredient{},
+		&models.TransactionItemModifier{},
+	); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	previousDB, previousApp := config.DB, config.App
+	config.DB = db
+	config.App = &config.Config{UploadDir: filepath.Join(dir, "uploads")}
+	t.Cleanup(func() {
+		config.DB, config.App = previousDB, previousApp
+	})
+
+	utils.ConfigureJWT("integration-test-secret-0123456789abcdef", time.Hour)
+
+	owner := models.User{Email: "owner@example.com", Name: "Owner", Role: models.RoleOwner}
+	if err := db.Omit("GoogleID").Create(&owner).Error; err != nil {
+		t.Fatalf("create owner: %v", err)
+	}
+	token, err := utils.GenerateToken(owner.ID, owner.Email, owner.Role)
+	if err != nil {
+		t.Fatalf("token: %v", err)
+	}
+
+	deps, err := routes.NewDependencies(db, config.App)
+	if err != nil {
+		t.Fatalf("dependencies: %v", err)
+	}
+
+	router := gin.New()
+	routes.SetupRoutes(router, deps)
+
+	return &testAPI{t: t, router: router, token: token}
+}
+
+// do sends a JSON request as the owner and decodes the JSON response
+func (api *testAPI) do(method, path string, body interface{}) (*httptest.ResponseRecorder, map[string]interface{}) {
+	api.t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			api.t.Fatalf("marshal: %v", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	return api.serve(req)
+}
+
+func (api *testAPI) serve(req *http.Request) (*httptest.ResponseRecorder, map[string]interface{}) {
+	api.t.Helper()
+
+	if api.token != "" {
+		req.Header.Set("Authorization", "Bearer "+api.token)
+	}
+
+	w := httptest.NewRecorder()
+	api.router.ServeHTTP(w, req)
+
+	var response map[string]interface{}
+	if strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			api.t.Fatalf("%s %s: decode response: %v", req.Method, req.URL, err)
+		}
+	}
+
+	return w, response
+}
+
+// mustDo is do that fails the test unless the response has status want
+func (api *testAPI) mustDo(want int, method, path string, body interface{}) map[string]interface{} {
+	api.t.Helper()
+
+	w, response := api.do(method, path, body)
+	if w.Code != want {
+		api.t.Fatalf("%s %s: status %d, want %d: %s", method, path, w.Code, want, w.Body.String())
+	}
+	return response
+}
+
+// postMenu creates a menu through the multipart form of POST /menus
+func (api *testAPI) postMenu(name string, price float64, recipe []gin.H) (*httptest.ResponseRecorder, map[string]interface{}) {
+	api.t.Helper()
+
+	ingredients, _ := json.Marshal(recipe)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	form.WriteField("name", name)
+	form.WriteField("price", fmt.Sprint(price))
+	form.WriteField("ingredients", string(ingredients))
+	image, _ := form.CreateFormFile("image", "menu.png")
+	image.Write([]byte("\x89PNG"))
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/menus", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return api.serve(req)
+}
+
+func (api *testAPI) ingredientStock(name string) float64 {
+	api.t.Helper()
+
+	response := api.mustDo(http.StatusOK, http.MethodGet, "/ingredients/?search="+name, nil)
+	data := response["data"].([]interface{})
+	if len(data) != 1 {
+		api.t.Fatalf("found %d ingredients named %s, want 1", len(data), name)
+	}
+	return data[0].(map[string]interface{})["stock"].(float64)
+}
+
+// seedRiceMenu creates kg and g units, rice in kg and a menu using 200 g of
+// rice per portion
+func (api *testAPI) seedRiceMenu(stock float64) (menuID uint) {
+	api.t.Helper()
+
+	kilogram := api.mustDo(http.StatusCreated, http.MethodPost, "/units/", gin.H{
+		"name": "Kilogram", "symbol": "kg", "dimension": "mass", "factor": 1000,
+	})["data"].(map[string]interface{})
+	gram := api.mustDo(http.StatusCreated, http.MethodPost, "/units/", gin.H{
+		"name": "Gram", "symbol": "g", "dimension": "mass", "factor": 1,
+	})["data"].(map[string]interface{})
+
+	rice := api.mustDo(http.StatusCreated, http.MethodPost, "/ingredients/", gin.H{
+		"name": "Beras", "stock": stock, "unit_id": kilogram["id"], "unit_cost": 12000,
+	})["data"].(map[string]interface{})
+
+	w, response := api.postMenu("Nasi", 5000, []gin.H{
+		{"ingredient_id": rice["id"], "quantity": 200, "unit_id": gram["id"]},
+	})
+	if w.Code != http.StatusCreated {
+		api.t.Fatalf("POST /menus: status %d: %s", w.Code, w.Body.String())
+	}
+
+	return uint(response["data"].(map[string]interface{})["id"].(float64))
+}
+
+func TestAPISaleFlow(t *testing.T) {
+	api := newTestAPI(t)
+	menuID := api.seedRiceMenu(2)
+
+	// Harga pokok resep: 0.2 kg x 12000
+	menu := api.mustDo(http.StatusOK, http.MethodGet, fmt.Sprintf("/menus/%d", menuID), nil)["data"].(map[string]interface{})
+	if menu["cost"] != 2400.0 {
+		t.Errorf("menu cost = %v, want 2400", menu["cost"])
+	}
+
+	created := api.mustDo(http.StatusCreated, http.MethodPost, "/transactions/", gin.H{
+		"items": []gin.H{{"menu_id": menuID, "quantity": 3}},
+	})["data"].(map[string]interface{})
+	if created["total_amount"] != 15000.0 {
+		t.Errorf("total = %v, want 15000", created["total_amount"])
+	}
+	code := created["transaction_code"].(string)
+
+	if stock := api.ingredientStock("beras"); stock != 1.4 {
+		t.Errorf("stock after sale = %v, want 1.4", stock)
+	}
+
+	list := api.mustDo(http.StatusOK, http.MethodGet, "/transactions/", nil)
+	transactions := list["data"].([]interface{})
+	if len(transactions) != 1 {
+		t.Fatalf("listed %d transactions, want 1", len(transactions))
+	}
+	transaction := transactions[0].(map[string]interface{})
+	transactionID := uint(transaction["id"].(float64))
+
+	detail := api.mustDo(http.StatusOK, http.MethodGet, fmt.Sprintf("/transactions/%d", transactionID), nil)["data"].(map[string]interface{})
+	item := detail["items"].([]interface{})[0].(map[string]interface{})
+	reduction := item["stock_reductions"].([]interface{})[0].(map[string]interface{})
+	if reduction["quantity_reduced"] != 0.6 || reduction["stock_before"] != 2.0 || reduction["stock_after"] != 1.4 {
+		t.Errorf("stock reduction = %v", reduction)
+	}
+
+	today := time.Now().Format("2006-01-02")
+	req := httptest.NewRequest(http.MethodGet, "/export/transactions?format=csv&start_date="+today+"&end_date="+today, nil)
+	w, _ := api.serve(req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("export: status %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "text/csv") || !strings.Contains(w.Body.String(), code) {
+		t.Errorf("export does not contain %s:\n%s", code, w.Body.String())
+	}
+
+	w, _ = api.serve(httptest.NewRequest(http.MethodGet, "/export/transactions?start_date="+today+"&end_date="+today, nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Header().Get("Content-Type"), "spreadsheetml") {
+		t.Errorf("xlsx export: status %d, content type %s", w.Code, w.Header().Get("Content-Type"))
+	}
+
+	api.mustDo(http.StatusOK, http.MethodDelete, fmt.Sprintf("/transactions/%d", transactionID), nil)
+
+	if stock := api.ingredientStock("beras"); stock != 2 {
+		t.Errorf("stock after delete = %v, want 2", stock)
+	}
+	api.mustDo(http.StatusNotFound, http.MethodGet, fmt.Sprintf("/transactions/%d", transactionID), nil)
+}
+
+func TestAPIStocktakeFlow(t *testing.T) {
+	api := newTestAPI(t)
+	menuID := api.seedRiceMenu(2)
+
+	stocktake := api.mustDo(http.StatusCreated, http.MethodPost, "/stocktakes", gin.H{})["data"].(map[string]interface{})
+	path := fmt.Sprintf("/stocktakes/%d", uint(stocktake["id"].(float64)))
+	line := stocktake["lines"].([]interface{})[0].(map[string]interface{})
+	riceID := line["ingredient"].(map[string]interface{})["id"]
+
+	api.mustDo(http.StatusConflict, http.MethodGet, path+"/variance", nil)
+	api.mustDo(http.StatusOK, http.MethodPost, path+"/start", nil)
+
+	// Penjualan selama penghitungan tetap tercatat setelah posting
+	api.mustDo(http.StatusCreated, http.MethodPost, "/transactions/", gin.H{
+		"items": []gin.H{{"menu_id": menuID, "quantity": 1}},
+	})
+
+	api.mustDo(http.StatusBadRequest, http.MethodPut, path+"/counts", gin.H{
+		"counts": []gin.H{{"ingredient_id": riceID, "counted_quantity": -1}},
+	})
+	api.mustDo(http.StatusOK, http.MethodPut, path+"/counts", gin.H{
+		"counts": []gin.H{{"ingredient_id": riceID, "counted_quantity": 1.5}},
+	})
+
+	report := api.mustDo(http.StatusOK, http.MethodGet, path+"/variance", nil)["data"].(map[string]interface{})
+	line = report["lines"].([]interface{})[0].(map[string]interface{})
+	if line["variance"] != -0.5 || line["variance_value"] != -6000.0 || line["variance_percentage"] != -25.0 {
+		t.Errorf("variance line = %v, want -0.5 valued -6000 (-25%%)", line)
+	}
+	if report["total_variance_value"] != -6000.0 || report["counted_lines"] != 1.0 {
+		t.Errorf("variance report = %v", report)
+	}
+
+	posted := api.mustDo(http.StatusOK, http.MethodPost, path+"/post", nil)["data"].(map[string]interface{})
+	if posted["status"] != "posted" {
+		t.Errorf("status = %v, want posted", posted["status"])
+	}
+	if stock := api.ingredientStock("beras"); stock != 1.3 {
+		t.Errorf("stock after posting = %v, want 1.3", stock)
+	}
+
+	api.mustDo(http.StatusConflict, http.MethodPost, path+"/post", nil)
+	api.mustDo(http.StatusConflict, http.MethodDelete, path, nil)
+}
+
+func TestAPIWasteFlow(t *testing.T) {
+	api := newTestAPI(t)
+	api.seedRiceMenu(2)
+
+	units := api.mustDo(http.StatusOK, http.MethodGet, "/units/", nil)["data"].([]interface{})
+	gram := units[1].(map[string]interface{})
+	if gram["symbol"] != "g" {
+		t.Fatalf("second unit = %v, want gram", gram)
+	}
+	rice := api.mustDo(http.StatusOK, http.MethodGet, "/ingredients/?search=beras", nil)["data"].([]interface{})[0].(map[string]interface{})
+
+	record := api.mustDo(http.StatusCreated, http.MethodPost, "/waste", gin.H{
+		"ingredient_id": rice["id"], "quantity": 500, "unit_id": gram["id"], "reason": "expired",
+	})["data"].(map[string]interface{})
+	if record["quantity_reduced"] != 0.5 || record["stock_after"] != 1.5 || record["value"] != 6000.0 {
+		t.Errorf("waste record = %v, want 0.5 kg valued 6000", record)
+	}
+	api.mustDo(http.StatusCreated, http.MethodPost, "/waste", gin.H{
+		"ingredient_id": rice["id"], "quantity": 0.25, "unit_id": rice["unit"].(map[string]interface{})["id"], "reason": "staff_meal",
+	})
+
+	if stock := api.ingredientStock("beras"); stock != 1.25 {
+		t.Errorf("stock after waste = %v, want 1.25", stock)
+	}
+
+	api.mustDo(http.StatusBadRequest, http.MethodPost, "/waste", gin.H{
+		"ingredient_id": rice["id"], "quantity": 1, "unit_id": gram["id"], "reason": "lost",
+	})
+	api.mustDo(http.StatusBadRequest, http.MethodPost, "/waste", gin.H{
+		"ingredient_id": rice["id"], "quantity": 5000, "unit_id": gram["id"], "reason": "damaged",
+	})
+
+	list := api.mustDo(http.StatusOK, http.MethodGet, "/waste?reason=expired", nil)["data"].([]interface{})
+	if len(list) != 1 {
+		t.Errorf("listed %d expired records, want 1", len(list))
+	}
+
+	report := api.mustDo(http.StatusOK, http.MethodGet, "/waste/report", nil)["data"].(map[string]interface{})
+	if report["records"] != 2.0 || report["total_value"] != 9000.0 {
+		t.Errorf("waste report = %v, want 2 records valued 9000", report)
+	}
+	byReason := report["by_reason"].([]interface{})
+	if len(byReason) != 2 || byReason[0].(map[string]interface{})["reason"] != "expired" {
+		t.Errorf("by reason = %v, want expired then staff_meal", byReason)
+	}
+	byIngredient := report["by_ingredient"].([]interface{})
+	if len(byIngredient) != 1 || byIngredient[0].(map[string]interface{})["quantity"] != 0.75 {
+		t.Errorf("by ingredient = %v, want 0.75 kg of rice", byIngredient)
+	}
+}
+
+func TestAPIStockLotsFEFO(t *testing.T) {
+	api := newTestAPI(t)
+	menuID := api.seedRiceMenu(1)
+
+	rice := api.mustDo(http.StatusOK, http.MethodGet, "/ingredients/?search=beras", nil)["data"].([]interface{})[0].(map[string]interface{})
+	kilogramID := rice["unit"].(map[string]interface{})["id"]
+
+	order := api.mustDo(http.StatusCreated, http.MethodPost, "/purchase-orders", gin.H{
+		"lines": []gin.H{{"ingredient_id": rice["id"], "quantity": 4, "unit_id": kilogramID, "unit_cost": 12000}},
+	})["data"].(map[string]interface{})
+	orderID := order["id"]
+	lineID := order["lines"].([]interface{})[0].(map[string]interface{})["id"]
+	api.mustDo(http.StatusOK, http.MethodPost, fmt.Sprintf("/purchase-orders/%v/send", orderID), nil)
+
+	// Lot B diterima lebih dulu tetapi kedaluwarsa lebih lambat dari lot A
+	today := time.Now()
+	for _, lot := range []struct {
+		number string
+		days   int
+	}{{"B", 10}, {"A", 2}} {
+		api.mustDo(http.StatusCreated, http.MethodPost, "/receipts", gin.H{
+			"purchase_order_id": orderID,
+			"items": []gin.H{{
+				"purchase_order_line_id": lineID,
+				"quantity":               2,
+				"lot_number":             lot.number,
+				"expiry_date":            today.AddDate(0, 0, lot.days).Format("2006-01-02"),
+			}},
+		})
+	}
+	api.mustDo(http.StatusNotFound, http.MethodPost, "/receipts", gin.H{
+		"purchase_order_id": 9999,
+		"items":             []gin.H{{"purchase_order_line_id": lineID, "quantity": 1}},
+	})
+
+	sale := func(quantity int) map[string]interface{} {
+		api.mustDo(http.StatusCreated, http.MethodPost, "/transactions/", gin.H{
+			"items": []gin.H{{"menu_id": menuID, "quantity": quantity}},
+		})
+		transactions := api.mustDo(http.StatusOK, http.MethodGet, "/transactions/?sort=-id", nil)["data"].([]interface{})
+		return transactions[0].(map[string]interface{})
+	}
+	reductionLots := func(transaction map[string]interface{}) []interface{} {
+		item := transaction["items"].([]interface{})[0].(map[string]interface{})
+		return item["stock_reductions"].([]interface{})[0].(map[string]interface{})["lots"].([]interface{})
+	}
+
+	// 3 porsi = 0.6 kg, seluruhnya dari lot A
+	first := sale(3)
+	lots := reductionLots(first)
+	if len(lots) != 1 || lots[0].(map[string]interface{})["lot_number"] != "A" || lots[0].(map[string]interface{})["quantity"] != 0.6 {
+		t.Errorf("first sale lots = %v, want 0.6 from lot A", lots)
+	}
+
+	expiring := api.mustDo(http.StatusOK, http.MethodGet, "/ingredients/expiring-lots?days=5", nil)["data"].([]interface{})
+	if len(expiring) != 1 || expiring[0].(map[string]interface{})["remaining"] != 1.4 || expiring[0].(map[string]interface{})["days_until_expiry"] != 2.0 {
+		t.Errorf("expiring lots = %v, want lot A with 1.4 left in 2 days", expiring)
+	}
+
+	// 10 porsi = 2 kg: sisa lot A lalu lot B
+	lots = reductionLots(sale(10))
+	// SQLite menyimpan float apa adanya, Postgres membulatkan ke numeric(14,4)
+	if len(lots) != 2 || lots[0].(map[string]interface{})["quantity"] != 1.4 || math.Abs(lots[1].(map[string]interface{})["quantity"].(float64)-0.6) > 1e-9 {
+		t.Errorf("second sale lots = %v, want 1.4 from A then 0.6 from B", lots)
+	}
+
+	api.mustDo(http.StatusOK, http.MethodPost, fmt.Sprintf("/transactions/%v/cancel", first["id"]), gin.H{"reason": "salah input"})
+
+	open := api.mustDo(http.StatusOK, http.MethodGet, fmt.Sprintf("/ingredients/%v/lots", rice["id"]), nil)["data"].([]interface{})
+	if len(open) != 2 || open[0].(map[string]interface{})["remaining"] != 0.6 || open[1].(map[string]interface{})["remaining"] != 1.4 {
+		t.Errorf("lots after cancel = %v, want A with 0.6 and B with 1.4", open)
+	}
+	if stock := api.ingredientStock("beras"); math.Abs(stock-3) > 0.005 {
+		t.Errorf("stock = %v, want 3", stock)
+	}
+}
+
+func TestAPISupplierDirectory(t *testing.T) {
+	api := newTestAPI(t)
+	api.seedRiceMenu(1)
+
+	rice := api.mustDo(http.StatusOK, http.MethodGet, "/ingredients/?search=beras", nil)["data"].([]interface{})[0].(map[string]interface{})
+	kilogramID := rice["unit"].(map[string]interface{})["id"]
+	gram := api.mustDo(http.StatusOK, http.MethodGet, "/units/?search=gram", nil)["data"].([]interface{})
+	gramID := gram[len(gram)-1].(map[string]interface{})["id"]
+
+	createSupplier := func(name string) interface{} {
+		return api.mustDo(http.StatusCreated, http.MethodPost, "/suppliers/", gin.H{
+			"name": name, "contact_name": "Budi", "lead_time_days": 2, "payment_terms": "NET 30",
+		})["data"].(map[string]interface{})["id"]
+	}
+	bulkID := createSupplier("Toko Grosir")
+	localID := createSupplier("Pasar Lokal")
+
+	// 25 kg seharga 300.000 = 12.000/kg, 500 g seharga 5.500 = 11.000/kg
+	api.mustDo(http.StatusOK, http.MethodPut, fmt.Sprintf("/suppliers/%v/ingredients/%v", bulkID, rice["id"]), gin.H{
+		"sku": "BRS-25", "pack_size": 25, "pack_unit_id": kilogramID, "last_price": 300000,
+	})
+	api.mustDo(http.StatusOK, http.MethodPut, fmt.Sprintf("/suppliers/%v/ingredients/%v", localID, rice["id"]), gin.H{
+		"pack_size": 500, "pack_unit_id": gramID, "last_price": 5500,
+	})
+
+	detail := api.mustDo(http.StatusOK, http.MethodGet, fmt.Sprintf("/ingredients/%v", rice["id"]), nil)["data"].(map[string]interface{})
+	suppliers := detail["suppliers"].([]interface{})
+	if detail["name"] != "Beras" || len(suppliers) != 2 {
+		t.Fatalf("ingredient detail = %v, want Beras with 2 suppliers", detail)
+	}
+	cheapest := suppliers[0].(map[string]interface{})
+	if cheapest["supplier"].(map[string]interface{})["id"] != localID || cheapest["unit_price"] != 11000.0 {
+		t.Errorf("cheapest supplier = %v, want Pasar Lokal at 11000/kg", cheapest)
+	}
+
+	filtered := api.mustDo(http.StatusOK, http.MethodGet, fmt.Sprintf("/suppliers/?ingredient_id=%v&search=grosir", rice["id"]), nil)["data"].([]interface{})
+	if len(filtered) != 1 || filtered[0].(map[string]interface{})["id"] != bulkID {
+		t.Errorf("filtered suppliers = %v, want Toko Grosir only", filtered)
+	}
+
+	api.mustDo(http.StatusOK, http.MethodDelete, fmt.Sprintf("/suppliers/%v", localID), nil)
+	api.mustDo(http.StatusNotFound, http.MethodGet, fmt.Sprintf("/suppliers/%v", localID), nil)
+
+	supplier := api.mustDo(http.StatusOK, http.MethodGet, fmt.Sprintf("/suppliers/%v", bulkID), nil)["data"].(map[string]interface{})
+	ingredients := supplier["ingredients"].([]interface{})
+	if len(ingredients) != 1 || ingredients[0].(map[string]interface{})["sku"] != "BRS-25" {
+		t.Errorf("supplier ingredients = %v, want BRS-25", ingredients)
+	}
+
+	api.mustDo(http.StatusBadRequest, http.MethodPut, fmt.Sprintf("/suppliers/%v/ingredients/%v", bulkID, rice["id"]), gin.H{
+		"pack_size": 0, "pack_unit_id": kilogramID,
+	})
+}
+
+func TestAPIReorderSuggestions(t *testing.T) {
+	api := newTestAPI(t)
+	menuID := api.seedRiceMenu(2)
+
+	rice := api.mustDo(http.StatusOK, http.MethodGet, "/ingredients/?search=beras", nil)["data"].([]interface{})[0].(map[string]interface{})
+	kilogramID := rice["unit"].(map[string]interface{})["id"]
+	api.mustDo(http.StatusOK, http.MethodPut, fmt.Sprintf("/ingredients/%v", rice["id"]), gin.H{
+		"name": "Beras", "stock": 2, "unit_id": kilogramID, "reorder_point": 1.5, "par_level": 5,
+	})
+
+	// 7 porsi = 1.4 kg terjual dalam 7 hari terakhir, 2 porsi yang direfund
+	// dengan restock tidak dihitung
+	api.mustDo(http.StatusCreated, http.MethodPost, "/transactions/", gin.H{
+		"items": []gin.H{{"menu_id": menuID, "quantity": 3}},
+	})
+	api.mustDo(http.StatusCreated, http.MethodPost, "/transactions/", gin.H{
+		"items": []gin.H{{"menu_id": menuID, "quantity": 6}},
+	})
+	transaction := api.mustDo(http.StatusOK, http.MethodGet, "/transactions/?sort=-id", nil)["data"].([]interface{})[0].(map[string]interface{})
+	item := transaction["items"].([]interface{})[0].(map[string]interface{})
+	api.mustDo(http.StatusCreated, http.MethodPost, fmt.Sprintf("/transactions/%v/refund", transaction["id"]), gin.H{
+		"reason": "Pesanan belum dibuat",
+		"items":  []gin.H{{"transaction_item_id": item["id"], "quantity": 2, "restock": true}},
+	})
+
+	supplierID := api.mustDo(http.StatusCreated, http.MethodPost, "/suppliers/", gin.H{
+		"name": "Toko Beras", "lead_time_days": 2,
+	})["data"].(map[string]interface{})["id"]
+	api.mustDo(http.StatusOK, http.MethodPut, fmt.Sprintf("/suppliers/%v/ingredients/%v", supplierID, rice["id"]), gin.H{
+		"pack_size": 5, "pack_unit_id": kilogramID, "last_price": 60000,
+	})
+
+	suggestions := api.mustDo(http.StatusOK, http.MethodGet, "/ingredients/reorder-suggestions?days=7", nil)["data"].([]interface{})
+	if len(suggestions) != 1 {
+		t.Fatalf("suggestions = %v, want rice only", suggestions)
+	}
+	// 0.6 - 2 hari x 0.2 kg = 0.2 kg saat barang datang, 4.8 kg ke par level = 1 kemasan 5 kg
+	suggestion := suggestions[0].(map[string]interface{})
+	if suggestion["average_daily_usage"] != 0.2 || suggestion["stock_at_delivery"] != 0.2 || suggestion["suggested_quantity"] != 4.8 {
+		t.Errorf("suggestion = %v, want 0.2 kg/day, 0.2 kg at delivery and 4.8 kg suggested", suggestion)
+	}
+	if suggestion["packs"] != 1.0 || suggestion["order_quantity"] != 5.0 || suggestion["estimated_cost"] != 60000.0 {
+		t.Errorf("suggested order = %v, want one 5 kg pack for 60000", suggestion)
+	}
+
+	drafts := api.mustDo(http.StatusCreated, http.MethodPost, "/purchase-orders/from-suggestions", gin.H{"days": 7})["data"].([]interface{})
+	if len(drafts) != 1 {
+		t.Fatalf("drafts = %v, want one purchase order", drafts)
+	}
+	draft := drafts[0].(map[string]interface{})
+	if draft["status"] != "draft" || draft["supplier"].(map[string]interface{})["id"] != supplierID || draft["total_amount"] != 60000.0 {
+		t.Errorf("draft = %v, want a 60000 draft for Toko Beras", draft)
+	}
+
+	suggestions = api.mustDo(http.StatusOK, http.MethodGet, "/ingredients/reorder-suggestions?days=7", nil)["data"].([]interface{})
+	if len(suggestions) != 0 {
+		t.Errorf("suggestions after draft = %v, want none", suggestions)
+	}
+	api.mustDo(http.StatusBadRequest, http.MethodPost, "/purchase-orders/from-suggestions", gin.H{"days": 7})
+	api.mustDo(http.StatusBadRequest, http.MethodGet, "/ingredients/reorder-suggestions?days=0", nil)
+}
+
+func TestAPIMenuAvailability(t *testing.T) {
+	api := newTestAPI(t)
+	menuID := api.seedRiceMenu(1)
+
+	// 1 kg / 200 g = 5 porsi
+	menu := api.mustDo(http.StatusOK, http.MethodGet, fmt.Sprintf("/menus/%d", menuID), nil)["data"].(map[string]interface{})
+	if menu["available_portions"] != 5.0 || menu["available"] != true {
+		t.Errorf("menu = %v, want 5 portions available", menu)
+	}
+
+	api.mustDo(http.StatusOK, http.MethodPut, fmt.Sprintf("/menus/%d/sold-out", menuID), gin.H{"sold_out": true})
+	if menus := api.mustDo(http.StatusOK, http.MethodGet, "/menus?available_only=true", nil)["data"].([]interface{}); len(menus) != 0 {
+		t.Errorf("available menus while sold out = %v, want none", menus)
+	}
+	api.mustDo(http.StatusConflict, http.MethodPost, "/transactions/", gin.H{
+		"items": []gin.H{{"menu_id": menuID, "quantity": 1}},
+	})
+
+	api.mustDo(http.StatusOK, http.MethodPut, fmt.Sprintf("/menus/%d/sold-out", menuID), gin.H{"sold_out": false})
+	if menus := api.mustDo(http.StatusOK, http.MethodGet, "/menus?available_only=true", nil)["data"].([]interface{}); len(menus) != 1 {
+		t.Errorf("available menus = %v, want the rice menu", menus)
+	}
+
+	api.mustDo(http.StatusCreated, http.MethodPost, "/transactions/", gin.H{
+		"items": []gin.H{{"menu_id": menuID, "quantity": 5}},
+	})
+	response := api.mustDo(http.StatusOK, http.MethodGet, "/menus?available_only=true", nil)
+	if menus := response["data"].([]interface{}); len(menus) != 0 || response["meta"].(map[string]interface{})["total"] != 0.0 {
+		t.Errorf("available menus without stock = %v, want none", response)
+	}
+	menu = api.mustDo(http.StatusOK, http.MethodGet, fmt.Sprintf("/menus/%d", menuID), nil)["data"].(map[string]interface{})
+	if menu["available_portions"] != 0.0 || menu["available"] != false {
+		t.Errorf("menu without stock = %v, want 0 portions", menu)
+	}
+}
+
+func TestAPIIdempotency(t *testing.T) {
+	api := newTestAPI(t)
+	menuID := api.seedRiceMenu(10)
+	sale := gin.H{"items": []gin.H{{"menu_id": menuID, "quantity": 1}}}
+
+	post := func(key string) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(sale)
+		req := httptest.NewRequest(http.MethodPost, "/transactions/", bytes.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(middleware.IdempotencyKeyHeader, key)
+		w, _ := api.serve(req)
+		return w
+	}
+
+	first := post("sale-1")
+	if first.Code != http.StatusCreated {
+		t.Fatalf("first sale: status %d: %s", first.Code, first.Body.String())
+	}
+	retry := post("sale-1")
+	if retry.Code != http.StatusCreated || retry.Header().Get("Idempotent-Replayed") != "true" || retry.Body.String() != first.Body.String() {
+		t.Errorf("retry: status %d replayed %q, want the stored 201", retry.Code, retry.Header().Get("Idempotent-Replayed"))
+	}
+	if stock := api.ingredientStock("beras"); math.Abs(stock-9.8) > 1e-9 {
+		t.Errorf("stock = %v, want 9.8 after one sale", stock)
+	}
+
+	// Request yang ditolak role check tidak menyimpan key
+	kitchen := models.User{Email: "kitchen@example.com", Name: "Kitchen", Role: models.RoleKitchen}
+	if err := config.DB.Omit("GoogleID").Create(&kitchen).Error; err != nil {
+		t.Fatalf("create kitchen user: %v", err)
+	}
+	api.token, _ = utils.GenerateToken(kitchen.ID, kitchen.Email, kitchen.Role)
+	if w := post("sale-2"); w.Code != http.StatusForbidden {
+		t.Fatalf("kitchen sale: status %d, want 403", w.Code)
+	}
+	config.DB.Model(&kitchen).Update("role", models.RoleCashier)
+	if w := post("sale-2"); w.Code != http.StatusCreated || w.Header().Get("Idempotent-Replayed") != "" {
+		t.Errorf("sale after promotion: status %d replayed %q, want a new 201", w.Code, w.Header().Get("Idempotent-Replayed"))
+	}
+
+	// Klaim yang tertinggal dari server yang crash dilepas setelah kadaluarsa
+	stuck := models.IdempotencyKey{
+		UserID:      kitchen.ID,
+		Key:         "sale-3",
+		Method:      http.MethodPost,
+		Path:        "/transactions/",
+		RequestHash: "crashed",
+		ExpiresAt:   time.Now().Add(-time.Second),
+	}
+	if err := config.DB.Create(&stuck).Error; err != nil {
+		t.Fatalf("create stuck claim: %v", err)
+	}
+	if w := post("sale-3"); w.Code != http.StatusCreated {
+		t.Errorf("sale with abandoned key: status %d, want 201: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestAPITransactionPreview(t *testing.T) {
+	api := newTestAPI(t)
+	menuID := api.seedRiceMenu(0.5)
+
+	preview := api.mustDo(http.StatusOK, http.MethodPost, "/transactions/preview", gin.H{
+		"items": []gin.H{{"menu_id": menuID, "quantity": 2}, {"menu_id": menuID, "quantity": 1}},
+	})["data"].(map[string]interface{})
+	if preview["total_amount"] != 15000.0 || preview["feasible"] != false {
+		t.Errorf("preview = %v, want 15000 and not feasible", preview)
+	}
+
+	// 3 porsi x 0.2 kg = 0.6 kg dari stok 0.5 kg
+	shortages := preview["shortages"].([]interface{})
+	if len(shortages) != 1 {
+		t.Fatalf("shortages = %v, want rice", shortages)
+	}
+	shortage := shortages[0].(map[string]interface{})
+	if math.Abs(shortage["required"].(float64)-0.6) > 0.005 || math.Abs(shortage["shortage"].(float64)-0.1) > 0.005 {
+		t.Errorf("shortage = %v, want 0.6 required and 0.1 short", shortage)
+	}
+
+	if stock := api.ingredientStock("beras"); stock != 0.5 {
+		t.Errorf("stock after preview = %v, want unchanged 0.5", stock)
+	}
+	if transactions := api.mustDo(http.StatusOK, http.MethodGet, "/transactions/", nil)["data"].([]interface{}); len(transactions) != 0 {
+		t.Errorf("transactions after preview = %v, want none", transactions)
+	}
+}
+
+func TestAPIMenuModifiers(t *testing.T) {
+	api := newTestAPI(t)
+	menuID := api.seedRiceMenu(2)
+
+	menu := api.mustDo(http.StatusOK, http.MethodGet, fmt.Sprintf("/menus/%d", menuID), nil)["data"].(map[string]interface{})
+	recipe := menu["ingredients"].([]interface{})[0].(map[string]interface{})
+	riceID := recipe["ingredient"].(map[string]interface{})["id"]
+	gramID := recipe["unit"].(map[string]interface{})["id"]
+
+	path := fmt.Sprintf("/menus/%d/modifiers", menuID)
+	api.mustDo(http.StatusBadRequest, http.MethodPut, path, gin.H{
+		"groups": []gin.H{{"name": "Ukuran", "min_select": 2, "options": []gin.H{{"name": "Besar"}}}},
+	})
+
+	menu = api.mustDo(http.StatusOK, http.MethodPut, path, gin.H{
+		"groups": []gin.H{
+			{"name": "Ukuran", "min_select": 1, "max_select": 1, "options": []gin.H{
+				{"name": "Reguler"},
+				{"name": "Besar", "price_delta": 2000, "ingredients": []gin.H{{"ingredient_id": riceID, "quantity": 100, "unit_id": gramID}}},
+			}},
+			{"name": "Tanpa", "options": []gin.H{
+				{"name": "Setengah Nasi", "price_delta": -1000, "ingredients": []gin.H{{"ingredient_id": riceID, "quantity": -100, "unit_id": gramID}}},
+			}},
+		},
+	})["data"].(map[string]interface{})
+	groups := menu["modifier_groups"].([]interface{})
+	if len(groups) != 2 {
+		t.Fatalf("modifier groups = %v, want 2", groups)
+	}
+	sizes := groups[0].(map[string]interface{})["options"].([]interface{})
+	large := sizes[1].(map[string]interface{})
+	if large["name"] != "Besar" || large["price_delta"] != 2000.0 {
+		t.Errorf("large option = %v", large)
+	}
+
+	// Ukuran wajib dipilih
+	api.mustDo(http.StatusBadRequest, http.MethodPost, "/transactions/", gin.H{
+		"items": []gin.H{{"menu_id": menuID, "quantity": 1}},
+	})
+
+	// 2 porsi besar: 2 x (200 g + 100 g) = 0.6 kg dengan harga 2 x 7000
+	created := api.mustDo(http.StatusCreated, http.MethodPost, "/transactions/", gin.H{
+		"items": []gin.H{{"menu_id": menuID, "quantity": 2, "modifier_option_ids": []interface{}{large["id"]}}},
+	})["data"].(map[string]interface{})
+	if created["total_amount"] != 14000.0 {
+		t.Errorf("total = %v, want 14000", created["total_amount"])
+	}
+	if stock := api.ingredientStock("beras"); math.Abs(stock-1.4) > 0.005 {
+		t.Errorf("stock = %v, want 1.4", stock)
+	}
+
+	listed := api.mustDo(http.StatusOK, http.MethodGet, "/transactions/", nil)["data"].([]interface{})
+	transactionPath := fmt.Sprintf("/transactions/%v", listed[0].(map[string]interface{})["id"])
+	transaction := api.mustDo(http.StatusOK, http.MethodGet, transactionPath, nil)["data"].(map[string]interface{})
+	item := transaction["items"].([]interface{})[0].(map[string]interface{})
+	modifiers := item["modifiers"].([]interface{})
+	if item["price"] != 7000.0 || len(modifiers) != 1 || modifiers[0].(map[string]interface{})["option_name"] != "Besar" {
+		t.Errorf("item = %v, want price 7000 with Besar", item)
+	}
+
+	// Ganti modifier tidak mengubah riwayat transaksi
+	api.mustDo(http.StatusOK, http.MethodPut, path, gin.H{"groups": []gin.H{}})
+	transaction = api.mustDo(http.StatusOK, http.MethodGet, transactionPath, nil)["data"].(map[string]interface{})
+	item = transaction["items"].([]interface{})[0].(map[string]interface{})
+	if modifiers := item["modifiers"].([]interface{}); len(modifiers) != 1 {
+		t.Errorf("modifiers after menu change = %v, want Besar kept", modifiers)
+	}
+
+	api.mustDo(http.StatusOK, http.MethodDelete, transactionPath, nil)
+	if stock := api.ingredientStock("beras"); math.Abs(stock-2) > 0.005 {
+		t.Errorf("stock after delete = %v, want 2", stock)
+	}
+}
+
+func TestAPIErrorPaths(t *testing.T) {
+	api := newTestAPI(t)
+	menuID := api.seedRiceMenu(0.5)
+
+	t.Run("insufficient stock", func(t *testing.T) {
+		response := api.mustDo(http.StatusBadRequest, http.MethodPost, "/transactions/", gin.H{
+			"items": []gin.H{{"menu_id": menuID, "quantity": 3}},
+		})
+		if !strings.Contains(response["message"].(string), "insufficient stock") {
+			t.Errorf("message = %v", response["message"])
+		}
+		if stock := api.ingredientStock("beras"); stock != 0.5 {
+			t.Errorf("stock = %v, want unchanged 0.5", stock)
+		}
+	})
+
+	t.Run("unknown menu", func(t *testing.T) {
+		api.mustDo(http.StatusNotFound, http.MethodPost, "/transactions/", gin.H{
+			"items": []gin.H{{"menu_id": 999, "quantity": 1}},
+		})
+		api.mustDo(http.StatusNotFound, http.MethodGet, "/menus/999", nil)
+	})
+
+	t.Run("missing items", func(t *testing.T) {
+		api.mustDo(http.StatusBadRequest, http.MethodPost, "/transactions/", gin.H{})
+	})
+
+	t.Run("bad dates", func(t *testing.T) {
+		api.mustDo(http.StatusBadRequest, http.MethodGet, "/transactions/?start_date=2024-13-01&end_date=2024-12-31", nil)
+		api.mustDo(http.StatusBadRequest, http.MethodGet, "/export/transactions?start_date=yesterday", nil)
+	})
+
+	t.Run("incompatible recipe unit", func(t *testing.T) {
+		liter := api.mustDo(http.StatusCreated, http.MethodPost, "/units/", gin.H{
+			"name": "Liter", "symbol": "l", "dimension": "volume", "factor": 1000,
+		})["data"].(map[string]interface{})
+		ingredients := api.mustDo(http.StatusOK, http.MethodGet, "/ingredients/?search=beras", nil)["data"].([]interface{})
+		rice := ingredients[0].(map[string]interface{})
+
+		w, _ := api.postMenu("Bubur", 8000, []gin.H{
+			{"ingredient_id": rice["id"], "quantity": 1, "unit_id": liter["id"]},
+		})
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("status %d, want 400: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("authentication and roles", func(t *testing.T) {
+		token := api.token
+		defer func() { api.token = token }()
+
+		api.token = ""
+		api.mustDo(http.StatusUnauthorized, http.MethodGet, "/transactions/", nil)
+
+		// Token milik user yang tidak ada di database ditolak
+		api.token, _ = utils.GenerateToken(99, "ghost@example.com", models.RoleOwner)
+		api.mustDo(http.StatusUnauthorized, http.MethodGet, "/transactions/", nil)
+
+		// Role diambil dari database, bukan dari token
+		kitchen := models.User{Email: "kitchen@example.com", Name: "Kitchen", Role: models.RoleKitchen}
+		if err := config.DB.Omit("GoogleID").Create(&kitchen).Error; err != nil {
+			t.Fatalf("create kitchen user: %v", err)
+		}
+		api.token, _ = utils.GenerateToken(kitchen.ID, kitchen.Email, models.RoleOwner)
+		api.mustDo(http.StatusForbidden, http.MethodPost, "/transactions/", gin.H{
+			"items": []gin.H{{"menu_id": menuID, "quantity": 1}},
+		})
+
+		// User yang dihapus tidak bisa memakai token lamanya
+		if err := config.DB.Delete(&kitchen).Error; err != nil {
+			t.Fatalf("delete kitchen user: %v", err)
+		}
+		api.mustDo(http.StatusUnauthorized, http.MethodGet, "/transactions/", nil)
+	})
+}
+
+func TestAPIIngredientMovementsPagination(t *testing.T) {
+	api := newTestAPI(t)
+	api.seedRiceMenu(1)
+
+	rice := api.mustDo(http.StatusOK, http.MethodGet, "/ingredients/?search=beras", nil)["data"].([]interface{})[0].(map[string]interface{})
+	for _, quantity := range []float64{2, -0.5, 1.5} {
+		api.mustDo(http.StatusCreated, http.MethodPost, fmt.Sprintf("/ingredients/%v/movements", rice["id"]), gin.H{
+			"type": "adjustment", "quantity": quantity,
+		})
+	}
+
+	// Halaman pertama hanya 2 movement, rekonsiliasi tetap dari seluruh ledger
+	response := api.mustDo(http.StatusOK, http.MethodGet, fmt.Sprintf("/ingredients/%v/movements?limit=2", rice["id"]), nil)
+	data := response["data"].(map[string]interface{})
+	movements := data["movements"].([]interface{})
+	if len(movements) != 2 || movements[0].(map[string]interface{})["quantity"] != 1.5 {
+		t.Errorf("movements = %v, want the 2 newest", movements)
+	}
+	if data["ledger_stock"] != 4.0 || data["current_stock"] != 4.0 || data["reconciled"] != true {
+		t.Errorf("reconciliation = %v, want 4 kg reconciled", data)
+	}
+	if meta := response["meta"].(map[string]interface{}); meta["total"] != 4.0 || meta["total_pages"] != 2.0 {
+		t.Errorf("meta = %v, want 4 movements on 2 pages", meta)
+	}
+
+	api.mustDo(http.StatusBadRequest, http.MethodGet, fmt.Sprintf("/ingredients/%v/movements?limit=0", rice["id"]), nil)
+}
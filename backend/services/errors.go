@@ -0,0 +1,39 @@
+// Package services holds the business rules of the API. Services work on
+// repositories and know nothing about HTTP, handlers map their errors to
+// status codes.
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"AwisPalace_IngredientManagement/repositories"
+)
+
+var (
+	ErrNotFound     = repositories.ErrNotFound
+	ErrInvalidInput = errors.New("invalid input")
+	ErrConflict     = errors.New("conflict")
+)
+
+// Error is a business rule violation with a message for the client
+type Error struct {
+	Kind    error // ErrInvalidInput atau ErrConflict
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+func invalidInput(format string, args ...interface{}) error {
+	return &Error{Kind: ErrInvalidInput, Message: fmt.Sprintf(format, args...)}
+}
+
+func conflict(format string, args ...interface{}) error {
+	return &Error{Kind: ErrConflict, Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,884 @@
+package services
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+	"AwisPalace_IngredientManagement/utils"
+)
+
+// fakeStore is an in-memory repositories.Store. Transaction works on a copy
+// of the data that replaces the original only when fn succeeds.
+type fakeStore struct {
+	data *fakeData
+}
+
+type fakeData struct {
+	ingredients  map[uint]models.Ingredient
+	units        map[uint]models.Unit
+	menus        map[uint]models.Menu
+	users        map[uint]models.User
+	transactions map[uint]models.Transaction
+	stocktakes   map[uint]models.Stocktake
+	lines        map[uint]models.StocktakeLine
+	waste        map[uint]models.WasteRecord
+	lots         map[uint]models.StockLot
+	suppliers    map[uint]models.Supplier
+	offers       map[uint]models.SupplierIngredient
+	orders       map[uint]models.PurchaseOrder
+	items        []models.TransactionItem
+	reductions   []models.StockReduction
+	movements    []models.StockMovement
+	refunds      []models.Refund
+	sequences    map[string]int64
+	lastID       uint
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{data: &fakeData{
+		ingredients:  map[uint]models.Ingredient{},
+		units:        map[uint]models.Unit{},
+		menus:        map[uint]models.Menu{},
+		users:        map[uint]models.User{},
+		transactions: map[uint]models.Transaction{},
+		stocktakes:   map[uint]models.Stocktake{},
+		lines:        map[uint]models.StocktakeLine{},
+		waste:        map[uint]models.WasteRecord{},
+		lots:         map[uint]models.StockLot{},
+		suppliers:    map[uint]models.Supplier{},
+		offers:       map[uint]models.SupplierIngredient{},
+		orders:       map[uint]models.PurchaseOrder{},
+		sequences:    map[string]int64{},
+	}}
+}
+
+func (d *fakeData) clone() *fakeData {
+	return &fakeData{
+		ingredients:  maps.Clone(d.ingredients),
+		units:        maps.Clone(d.units),
+		menus:        maps.Clone(d.menus),
+		users:        maps.Clone(d.users),
+		transactions: maps.Clone(d.transactions),
+		stocktakes:   maps.Clone(d.stocktakes),
+		lines:        maps.Clone(d.lines),
+		waste:        maps.Clone(d.waste),
+		lots:         maps.Clone(d.lots),
+		suppliers:    maps.Clone(d.suppliers),
+		offers:       maps.Clone(d.offers),
+		orders:       maps.Clone(d.orders),
+		items:        slices.Clone(d.items),
+		reductions:   slices.Clone(d.reductions),
+		movements:    slices.Clone(d.movements),
+		refunds:      slices.Clone(d.refunds),
+		sequences:    maps.Clone(d.sequences),
+		lastID:       d.lastID,
+	}
+}
+
+func (d *fakeData) nextID() uint {
+	d.lastID++
+	return d.lastID
+}
+
+func (s *fakeStore) Ingredients() repositories.IngredientRepository {
+	return fakeIngredients{s.data}
+}
+
+func (s *fakeStore) Menus() repositories.MenuRepository {
+	return fakeMenus{s.data}
+}
+
+func (s *fakeStore) Units() repositories.UnitRepository {
+	return fakeUnits{s.data}
+}
+
+func (s *fakeStore) Transactions() repositories.TransactionRepository {
+	return fakeTransactions{s.data}
+}
+
+func (s *fakeStore) Users() repositories.UserRepository {
+	return fakeUsers{s.data}
+}
+
+func (s *fakeStore) Sequences() repositories.SequenceRepository {
+	return fakeSequences{s.data}
+}
+
+func (s *fakeStore) Stocktakes() repositories.StocktakeRepository {
+	return fakeStocktakes{s.data}
+}
+
+func (s *fakeStore) Waste() repositories.WasteRepository {
+	return fakeWaste{s.data}
+}
+
+func (s *fakeStore) StockLots() repositories.StockLotRepository {
+	return fakeStockLots{s.data}
+}
+
+func (s *fakeStore) Suppliers() repositories.SupplierRepository {
+	return fakeSuppliers{s.data}
+}
+
+func (s *fakeStore) PurchaseOrders() repositories.PurchaseOrderRepository {
+	return fakePurchaseOrders{s.data}
+}
+
+func (s *fakeStore) Transaction(fn func(store repositories.Store) error) error {
+	tx := &fakeStore{data: s.data.clone()}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	*s.data = *tx.data
+	return nil
+}
+
+// addUnit, addIngredient, addMenu and addLot seed the store
+
+func (s *fakeStore) addUnit(unit models.Unit) models.Unit {
+	unit.ID = s.data.nextID()
+	s.data.units[unit.ID] = unit
+	return unit
+}
+
+func (s *fakeStore) addIngredient(ingredient models.Ingredient) models.Ingredient {
+	ingredient.ID = s.data.nextID()
+	ingredient.Unit = s.data.units[ingredient.UnitID]
+	s.data.ingredients[ingredient.ID] = ingredient
+	return ingredient
+}
+
+func (s *fakeStore) addMenu(menu models.Menu) models.Menu {
+	menu.ID = s.data.nextID()
+	for i := range menu.MenuIngredients {
+		menu.MenuIngredients[i].MenuID = menu.ID
+		menu.MenuIngredients[i].Unit = s.data.units[menu.MenuIngredients[i].UnitID]
+	}
+	s.data.menus[menu.ID] = menu
+	return menu
+}
+
+func (s *fakeStore) addLot(lot models.StockLot) models.StockLot {
+	lot.ID = s.data.nextID()
+	s.data.lots[lot.ID] = lot
+	return lot
+}
+
+func notFound(entity string, id uint) error {
+	return fmt.Errorf("%s with ID %d %w", entity, id, repositories.ErrNotFound)
+}
+
+type fakeIngredients struct{ data *fakeData }
+
+func (r fakeIngredients) List(filter repositories.IngredientFilter, params utils.ListParams) ([]models.Ingredient, dto.PageMeta, error) {
+	ingredients, err := r.FindByIDs(nil)
+	return ingredients, dto.PageMeta{}, err
+}
+
+func (r fakeIngredients) LowStock() ([]models.Ingredient, error) {
+	ingredients, err := r.FindByIDs(nil)
+	return slices.DeleteFunc(ingredients, func(ingredient models.Ingredient) bool {
+		return !ingredient.IsLowStock()
+	}), err
+}
+
+func (r fakeIngredients) FindByID(id uint) (models.Ingredient, error) {
+	ingredient, ok := r.data.ingredients[id]
+	if !ok {
+		return ingredient, notFound("ingredient", id)
+	}
+	return ingredient, nil
+}
+
+func (r fakeIngredients) FindForUpdate(id uint) (models.Ingredient, error) {
+	return r.FindByID(id)
+}
+
+func (r fakeIngredients) FindByIDs(ids []uint) ([]models.Ingredient, error) {
+	var ingredients []models.Ingredient
+	for _, ingredient := range r.data.ingredients {
+		if len(ids) == 0 || slices.Contains(ids, ingredient.ID) {
+			ingredients = append(ingredients, ingredient)
+		}
+	}
+	slices.SortFunc(ingredients, func(a, b models.Ingredient) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return ingredients, nil
+}
+
+func (r fakeIngredients) HasStockHistory(id uint) (bool, error) {
+	for _, movement := range r.data.movements {
+		if movement.IngredientID == id {
+			return true, nil
+		}
+	}
+	for _, lot := range r.data.lots {
+		if lot.IngredientID == id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r fakeIngredients) Lock(ids []uint) error {
+	return nil
+}
+
+func (r fakeIngredients) RecordMovement(movement *models.StockMovement) error {
+	ingredient, ok := r.data.ingredients[movement.IngredientID]
+	if !ok {
+		return notFound("ingredient", movement.IngredientID)
+	}
+
+	movement.StockBefore = ingredient.Stock
+	movement.StockAfter = ingredient.Stock + movement.Quantity
+	movement.UnitID = ingredient.UnitID
+	if movement.StockAfter < 0 {
+		return fmt.Errorf("%w for ingredient: %s", utils.ErrInsufficientStock, ingredient.Name)
+	}
+
+	ingredient.Stock = movement.StockAfter
+	r.data.ingredients[ingredient.ID] = ingredient
+
+	movement.ID = r.data.nextID()
+	r.data.movements = append(r.data.movements, *movement)
+	return nil
+}
+
+func (r fakeIngredients) Create(ingredient *models.Ingredient) error {
+	ingredient.ID = r.data.nextID()
+	r.data.ingredients[ingredient.ID] = *ingredient
+	return nil
+}
+
+func (r fakeIngredients) Update(ingredient *models.Ingredient) error {
+	stored := *ingredient
+	stored.Stock = r.data.ingredients[ingredient.ID].Stock
+	r.data.ingredients[ingredient.ID] = stored
+	return nil
+}
+
+func (r fakeIngredients) Delete(ingredient *models.Ingredient) error {
+	delete(r.data.ingredients, ingredient.ID)
+	return nil
+}
+
+type fakeUnits struct{ data *fakeData }
+
+func (r fakeUnits) List(filter repositories.UnitFilter, params utils.ListParams) ([]models.Unit, dto.PageMeta, error) {
+	return slices.Collect(maps.Values(r.data.units)), dto.PageMeta{}, nil
+}
+
+func (r fakeUnits) Create(unit *models.Unit) error {
+	unit.ID = r.data.nextID()
+	return r.Update(unit)
+}
+
+func (r fakeUnits) Update(unit *models.Unit) error {
+	r.data.units[unit.ID] = *unit
+	return nil
+}
+
+func (r fakeUnits) Delete(unit *models.Unit) error {
+	delete(r.data.units, unit.ID)
+	return nil
+}
+
+func (r fakeUnits) FindByID(id uint) (models.Unit, error) {
+	unit, ok := r.data.units[id]
+	if !ok {
+		return unit, notFound("unit", id)
+	}
+	return unit, nil
+}
+
+type fakeMenus struct{ data *fakeData }
+
+func (r fakeMenus) List(filter repositories.MenuFilter, params utils.ListParams) ([]models.Menu, dto.PageMeta, error) {
+	menus, err := r.FindAll(filter)
+	return menus, dto.PageMeta{}, err
+}
+
+// FindAll applies the sold out and ID filters and loads the current stock of
+// the recipe ingredients
+func (r fakeMenus) FindAll(filter repositories.MenuFilter) ([]models.Menu, error) {
+	var menus []models.Menu
+	for _, menu := range r.data.menus {
+		if (filter.AvailableOnly && menu.SoldOut) || (filter.IDs != nil && !slices.Contains(filter.IDs, menu.ID)) {
+			continue
+		}
+
+		menu.MenuIngredients = slices.Clone(menu.MenuIngredients)
+		for i := range menu.MenuIngredients {
+			menu.MenuIngredients[i].Ingredient = r.data.ingredients[menu.MenuIngredients[i].IngredientID]
+		}
+		menus = append(menus, menu)
+	}
+	slices.SortFunc(menus, func(a, b models.Menu) int {
+		return int(a.ID) - int(b.ID)
+	})
+	return menus, nil
+}
+
+func (r fakeMenus) FindByID(id uint) (models.Menu, error) {
+	menu, ok := r.data.menus[id]
+	if !ok {
+		return menu, notFound("menu", id)
+	}
+	return menu, nil
+}
+
+func (r fakeMenus) Create(menu *models.Menu, recipe []models.MenuIngredient) error {
+	menu.ID = r.data.nextID()
+	return r.Update(menu, recipe)
+}
+
+func (r fakeMenus) Update(menu *models.Menu, recipe []models.MenuIngredient) error {
+	for i := range recipe {
+		recipe[i].ID = r.data.nextID()
+		recipe[i].MenuID = menu.ID
+	}
+	menu.MenuIngredients = recipe
+	r.data.menus[menu.ID] = *menu
+	return nil
+}
+
+func (r fakeMenus) Delete(menu *models.Menu) error {
+	delete(r.data.menus, menu.ID)
+	return nil
+}
+
+func (r fakeMenus) ReplaceModifiers(menu *models.Menu, groups []models.MenuModifierGroup) error {
+	for i := range groups {
+		group := &groups[i]
+		group.ID = r.data.nextID()
+		group.MenuID = menu.ID
+
+		for j := range group.Options {
+			option := &group.Options[j]
+			option.ID = r.data.nextID()
+			option.GroupID = group.ID
+
+			for k := range option.Ingredients {
+				option.Ingredients[k].ID = r.data.nextID()
+				option.Ingredients[k].OptionID = option.ID
+				option.Ingredients[k].Unit = r.data.units[option.Ingredients[k].UnitID]
+			}
+		}
+	}
+	menu.ModifierGroups = groups
+	r.data.menus[menu.ID] = *menu
+	return nil
+}
+
+func (r fakeMenus) SetSoldOut(id uint, soldOut bool) error {
+	menu, ok := r.data.menus[id]
+	if !ok {
+		return notFound("menu", id)
+	}
+	menu.SoldOut = soldOut
+	r.data.menus[id] = menu
+	return nil
+}
+
+type fakeTransactions struct{ data *fakeData }
+
+func (r fakeTransactions) List(filter repositories.TransactionFilter, params utils.ListParams) ([]models.Transaction, dto.PageMeta, error) {
+	return slices.Collect(maps.Values(r.data.transactions)), dto.PageMeta{}, nil
+}
+
+func (r fakeTransactions) FindByID(id uint) (models.Transaction, error) {
+	transaction, ok := r.data.transactions[id]
+	if !ok {
+		return transaction, notFound("transaction", id)
+	}
+
+	transaction.TransactionItems = nil
+	for _, item := range r.data.items {
+		if item.TransactionID != id {
+			continue
+		}
+
+		item.StockReductions = nil
+		for _, reduction := range r.data.reductions {
+			if reduction.TransactionItemID == item.ID {
+				reduction.Unit = r.data.units[reduction.UnitID]
+				item.StockReductions = append(item.StockReductions, reduction)
+			}
+		}
+		transaction.TransactionItems = append(transaction.TransactionItems, item)
+	}
+
+	transaction.Refunds = nil
+	for _, refund := range r.data.refunds {
+		if refund.TransactionID == id {
+			transaction.Refunds = append(transaction.Refunds, refund)
+		}
+	}
+	return transaction, nil
+}
+
+// FindForUpdate has nothing to lock, the fake store runs one transaction at a time
+func (r fakeTransactions) FindForUpdate(id uint) (models.Transaction, error) {
+	return r.FindByID(id)
+}
+
+func (r fakeTransactions) Create(transaction *models.Transaction) error {
+	transaction.ID = r.data.nextID()
+	r.data.transactions[transaction.ID] = *transaction
+	return nil
+}
+
+func (r fakeTransactions) CreateItem(item *models.TransactionItem) error {
+	item.ID = r.data.nextID()
+	for i := range item.Modifiers {
+		item.Modifiers[i].ID = r.data.nextID()
+		item.Modifiers[i].TransactionItemID = item.ID
+	}
+	r.data.items = append(r.data.items, *item)
+	return nil
+}
+
+func (r fakeTransactions) CreateStockReduction(reduction *models.StockReduction) error {
+	reduction.ID = r.data.nextID()
+	r.data.reductions = append(r.data.reductions, *reduction)
+	return nil
+}
+
+func (r fakeTransactions) UpdateItemCost(itemID uint, cost float64) error {
+	for i := range r.data.items {
+		if r.data.items[i].ID == itemID {
+			r.data.items[i].Cost = cost
+		}
+	}
+	return nil
+}
+
+func (r fakeTransactions) UpdateTotal(transactionID uint, total float64) error {
+	transaction := r.data.transactions[transactionID]
+	transaction.TotalAmount = total
+	r.data.transactions[transactionID] = transaction
+	return nil
+}
+
+func (r fakeTransactions) UpdateStatus(transaction *models.Transaction) error {
+	stored := r.data.transactions[transaction.ID]
+	stored.Status = transaction.Status
+	stored.RefundedAmount = transaction.RefundedAmount
+	stored.CancelReason = transaction.CancelReason
+	stored.CancelledAt = transaction.CancelledAt
+	stored.CancelledByID = transaction.CancelledByID
+	r.data.transactions[transaction.ID] = stored
+	return nil
+}
+
+func (r fakeTransactions) UpdateRefundedQuantity(itemID uint, quantity int) error {
+	for i := range r.data.items {
+		if r.data.items[i].ID == itemID {
+			r.data.items[i].RefundedQuantity = quantity
+		}
+	}
+	return nil
+}
+
+func (r fakeTransactions) CreateRefund(refund *models.Refund) error {
+	refund.ID = r.data.nextID()
+	for i := range refund.Items {
+		refund.Items[i].ID = r.data.nextID()
+		refund.Items[i].RefundID = refund.ID
+	}
+	r.data.refunds = append(r.data.refunds, *refund)
+	return nil
+}
+
+func (r fakeTransactions) Delete(transaction *models.Transaction) error {
+	r.data.items = slices.DeleteFunc(r.data.items, func(item models.TransactionItem) bool {
+		return item.TransactionID == transaction.ID
+	})
+	r.data.reductions = slices.DeleteFunc(r.data.reductions, func(reduction models.StockReduction) bool {
+		return slices.ContainsFunc(transaction.TransactionItems, func(item models.TransactionItem) bool {
+			return item.ID == reduction.TransactionItemID
+		})
+	})
+	delete(r.data.transactions, transaction.ID)
+	return nil
+}
+
+func (r fakeTransactions) Usage(start, end time.Time) ([]repositories.IngredientUsage, error) {
+	type key struct{ ingredientID, unitID uint }
+	totals := map[key]float64{}
+	var keys []key
+
+	for _, reduction := range r.data.reductions {
+		i := slices.IndexFunc(r.data.items, func(item models.TransactionItem) bool {
+			return item.ID == reduction.TransactionItemID
+		})
+		if i < 0 {
+			continue
+		}
+		transaction := r.data.transactions[r.data.items[i].TransactionID]
+		if transaction.Status == models.TransactionStatusCancelled ||
+			transaction.TransactionDate.Before(start) || transaction.TransactionDate.After(end) {
+			continue
+		}
+
+		item := r.data.items[i]
+		restocked := 0
+		for _, refund := range r.data.refunds {
+			for _, refundItem := range refund.Items {
+				if refundItem.TransactionItemID == item.ID && refundItem.Restocked {
+					restocked += refundItem.Quantity
+				}
+			}
+		}
+
+		k := key{reduction.IngredientID, reduction.UnitID}
+		if _, ok := totals[k]; !ok {
+			keys = append(keys, k)
+		}
+		totals[k] += reduction.QuantityReduced * float64(item.Quantity-restocked) / float64(item.Quantity)
+	}
+
+	usage := make([]repositories.IngredientUsage, 0, len(keys))
+	for _, k := range keys {
+		usage = append(usage, repositories.IngredientUsage{IngredientID: k.ingredientID, UnitID: k.unitID, Quantity: totals[k]})
+	}
+	return usage, nil
+}
+
+type fakeSequences struct{ data *fakeData }
+
+func (r fakeSequences) Next(name string) (int64, error) {
+	r.data.sequences[name]++
+	return r.data.sequences[name], nil
+}
+
+// fakeStocktakes keeps lines apart from their stocktake so that cloning the
+// data does not share line slices between transactions
+type fakeStocktakes struct{ data *fakeData }
+
+func (r fakeStocktakes) List(filter repositories.StocktakeFilter, params utils.ListParams) ([]models.Stocktake, dto.PageMeta, error) {
+	var stocktakes []models.Stocktake
+	for id, stocktake := range r.data.stocktakes {
+		if filter.Status == "" || stocktake.Status == filter.Status {
+			stocktake, _ = r.FindByID(id)
+			stocktakes = append(stocktakes, stocktake)
+		}
+	}
+	return stocktakes, dto.PageMeta{}, nil
+}
+
+func (r fakeStocktakes) FindForUpdate(id uint) (models.Stocktake, error) {
+	return r.FindByID(id)
+}
+
+func (r fakeStocktakes) FindByID(id uint) (models.Stocktake, error) {
+	stocktake, ok := r.data.stocktakes[id]
+	if !ok {
+		return stocktake, notFound("stocktake", id)
+	}
+
+	stocktake.Lines = nil
+	for _, line := range r.data.lines {
+		if line.StocktakeID == id {
+			line.Ingredient = r.data.ingredients[line.IngredientID]
+			line.Unit = r.data.units[line.UnitID]
+			stocktake.Lines = append(stocktake.Lines, line)
+		}
+	}
+	slices.SortFunc(stocktake.Lines, func(a, b models.StocktakeLine) int {
+		return int(a.ID) - int(b.ID)
+	})
+	return stocktake, nil
+}
+
+func (r fakeStocktakes) Create(stocktake *models.Stocktake) error {
+	stocktake.ID = r.data.nextID()
+	for i := range stocktake.Lines {
+		stocktake.Lines[i].ID = r.data.nextID()
+		stocktake.Lines[i].StocktakeID = stocktake.ID
+		r.data.lines[stocktake.Lines[i].ID] = stocktake.Lines[i]
+	}
+	return r.Update(stocktake)
+}
+
+func (r fakeStocktakes) Update(stocktake *models.Stocktake) error {
+	header := *stocktake
+	header.Lines = nil
+	r.data.stocktakes[stocktake.ID] = header
+	return nil
+}
+
+func (r fakeStocktakes) UpdateLine(line *models.StocktakeLine) error {
+	r.data.lines[line.ID] = *line
+	return nil
+}
+
+func (r fakeStocktakes) Delete(stocktake *models.Stocktake) error {
+	for id, line := range r.data.lines {
+		if line.StocktakeID == stocktake.ID {
+			delete(r.data.lines, id)
+		}
+	}
+	delete(r.data.stocktakes, stocktake.ID)
+	return nil
+}
+
+type fakeUsers struct{ data *fakeData }
+
+func (r fakeUsers) List(filter repositories.UserFilter, params utils.ListParams) ([]models.User, dto.PageMeta, error) {
+	return slices.Collect(maps.Values(r.data.users)), dto.PageMeta{}, nil
+}
+
+func (r fakeUsers) FindByID(id uint) (models.User, error) {
+	user, ok := r.data.users[id]
+	if !ok {
+		return user, notFound("user", id)
+	}
+	return user, nil
+}
+
+func (r fakeUsers) CountByRole(role string) (int64, error) {
+	var count int64
+	for _, user := range r.data.users {
+		if user.Role == role {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r fakeUsers) UpdateRole(user *models.User, role string) error {
+	user.Role = role
+	r.data.users[user.ID] = *user
+	return nil
+}
+
+type fakeWaste struct{ data *fakeData }
+
+func (r fakeWaste) List(filter repositories.WasteFilter, params utils.ListParams) ([]models.WasteRecord, dto.PageMeta, error) {
+	records, err := r.FindAll(filter)
+	return records, dto.PageMeta{}, err
+}
+
+func (r fakeWaste) FindAll(filter repositories.WasteFilter) ([]models.WasteRecord, error) {
+	var records []models.WasteRecord
+	for id, record := range r.data.waste {
+		switch {
+		case !filter.StartDate.IsZero() && record.WastedAt.Before(filter.StartDate),
+			!filter.EndDate.IsZero() && record.WastedAt.After(filter.EndDate),
+			filter.Reason != "" && record.Reason != filter.Reason,
+			filter.IngredientID != 0 && record.IngredientID != filter.IngredientID:
+			continue
+		}
+		record, _ = r.FindByID(id)
+		records = append(records, record)
+	}
+	slices.SortFunc(records, func(a, b models.WasteRecord) int {
+		return a.WastedAt.Compare(b.WastedAt)
+	})
+	return records, nil
+}
+
+func (r fakeWaste) FindByID(id uint) (models.WasteRecord, error) {
+	record, ok := r.data.waste[id]
+	if !ok {
+		return record, notFound("waste record", id)
+	}
+	record.Ingredient = r.data.ingredients[record.IngredientID]
+	record.WastedUnit = r.data.units[record.WastedUnitID]
+	record.Unit = r.data.units[record.UnitID]
+	return record, nil
+}
+
+func (r fakeWaste) Create(record *models.WasteRecord) error {
+	record.ID = r.data.nextID()
+	return r.Update(record)
+}
+
+func (r fakeWaste) Update(record *models.WasteRecord) error {
+	r.data.waste[record.ID] = *record
+	return nil
+}
+
+// fakeStockLots only lists and refills lots, the fake ledger does not
+// consume them
+type fakeStockLots struct{ data *fakeData }
+
+func (r fakeStockLots) ListByIngredient(ingredientID uint) ([]models.StockLot, error) {
+	return r.find(func(lot models.StockLot) bool {
+		return lot.IngredientID == ingredientID
+	}), nil
+}
+
+func (r fakeStockLots) ExpiringBefore(before time.Time) ([]models.StockLot, error) {
+	return r.find(func(lot models.StockLot) bool {
+		return lot.ExpiryDate != nil && lot.ExpiryDate.Before(before)
+	}), nil
+}
+
+func (r fakeStockLots) Return(lots []models.StockReductionLot, fraction float64) error {
+	for _, drawn := range lots {
+		lot := r.data.lots[drawn.StockLotID]
+		lot.Remaining += drawn.Quantity * fraction
+		r.data.lots[lot.ID] = lot
+	}
+	return nil
+}
+
+func (r fakeStockLots) find(match func(models.StockLot) bool) []models.StockLot {
+	var lots []models.StockLot
+	for _, lot := range r.data.lots {
+		if lot.Remaining > 0 && match(lot) {
+			lots = append(lots, lot)
+		}
+	}
+	slices.SortFunc(lots, func(a, b models.StockLot) int {
+		return int(a.ID) - int(b.ID)
+	})
+	return lots
+}
+
+// fakeSuppliers keeps the supplier ingredient links in data.offers
+type fakeSuppliers struct{ data *fakeData }
+
+func (r fakeSuppliers) List(filter repositories.SupplierFilter, params utils.ListParams) ([]models.Supplier, dto.PageMeta, error) {
+	return slices.Collect(maps.Values(r.data.suppliers)), dto.PageMeta{}, nil
+}
+
+func (r fakeSuppliers) FindByID(id uint) (models.Supplier, error) {
+	supplier, ok := r.data.suppliers[id]
+	if !ok {
+		return supplier, notFound("supplier", id)
+	}
+
+	supplier.Ingredients = nil
+	for _, link := range r.data.offers {
+		if link.SupplierID == id {
+			link.Ingredient = r.data.ingredients[link.IngredientID]
+			supplier.Ingredients = append(supplier.Ingredients, link)
+		}
+	}
+	slices.SortFunc(supplier.Ingredients, func(a, b models.SupplierIngredient) int {
+		return int(a.ID) - int(b.ID)
+	})
+	return supplier, nil
+}
+
+func (r fakeSuppliers) Create(supplier *models.Supplier) error {
+	supplier.ID = r.data.nextID()
+	return r.Update(supplier)
+}
+
+func (r fakeSuppliers) Update(supplier *models.Supplier) error {
+	stored := *supplier
+	stored.Ingredients = nil
+	r.data.suppliers[supplier.ID] = stored
+	return nil
+}
+
+func (r fakeSuppliers) Delete(supplier *models.Supplier) error {
+	for id, link := range r.data.offers {
+		if link.SupplierID == supplier.ID {
+			delete(r.data.offers, id)
+		}
+	}
+	delete(r.data.suppliers, supplier.ID)
+	return nil
+}
+
+func (r fakeSuppliers) FindIngredient(supplierID, ingredientID uint) (models.SupplierIngredient, error) {
+	for _, link := range r.data.offers {
+		if link.SupplierID == supplierID && link.IngredientID == ingredientID {
+			return link, nil
+		}
+	}
+	return models.SupplierIngredient{}, notFound("supplier ingredient", ingredientID)
+}
+
+func (r fakeSuppliers) ListByIngredient(ingredientID uint) ([]models.SupplierIngredient, error) {
+	var links []models.SupplierIngredient
+	for _, link := range r.data.offers {
+		if link.IngredientID == ingredientID {
+			link.Supplier = r.data.suppliers[link.SupplierID]
+			links = append(links, link)
+		}
+	}
+	slices.SortFunc(links, func(a, b models.SupplierIngredient) int {
+		return int(a.ID) - int(b.ID)
+	})
+	return links, nil
+}
+
+func (r fakeSuppliers) SaveIngredient(link *models.SupplierIngredient) error {
+	if link.ID == 0 {
+		link.ID = r.data.nextID()
+	}
+	stored := *link
+	stored.Supplier = models.Supplier{}
+	stored.Ingredient = models.Ingredient{}
+	r.data.offers[link.ID] = stored
+	return nil
+}
+
+func (r fakeSuppliers) DeleteIngredient(link *models.SupplierIngredient) error {
+	delete(r.data.offers, link.ID)
+	return nil
+}
+
+// fakePurchaseOrders keeps the lines inside their purchase order
+type fakePurchaseOrders struct{ data *fakeData }
+
+func (r fakePurchaseOrders) FindByID(id uint) (models.PurchaseOrder, error) {
+	purchaseOrder, ok := r.data.orders[id]
+	if !ok {
+		return purchaseOrder, notFound("purchase order", id)
+	}
+
+	if purchaseOrder.SupplierID != nil {
+		supplier := r.data.suppliers[*purchaseOrder.SupplierID]
+		purchaseOrder.Supplier = &supplier
+	}
+	purchaseOrder.Lines = slices.Clone(purchaseOrder.Lines)
+	for i := range purchaseOrder.Lines {
+		purchaseOrder.Lines[i].Ingredient = r.data.ingredients[purchaseOrder.Lines[i].IngredientID]
+		purchaseOrder.Lines[i].Unit = r.data.units[purchaseOrder.Lines[i].UnitID]
+	}
+	return purchaseOrder, nil
+}
+
+func (r fakePurchaseOrders) Create(purchaseOrder *models.PurchaseOrder) error {
+	purchaseOrder.ID = r.data.nextID()
+	for i := range purchaseOrder.Lines {
+		purchaseOrder.Lines[i].ID = r.data.nextID()
+		purchaseOrder.Lines[i].PurchaseOrderID = purchaseOrder.ID
+	}
+
+	stored := *purchaseOrder
+	stored.Lines = slices.Clone(purchaseOrder.Lines)
+	r.data.orders[purchaseOrder.ID] = stored
+	return nil
+}
+
+func (r fakePurchaseOrders) OpenLines() ([]models.PurchaseOrderLine, error) {
+	var lines []models.PurchaseOrderLine
+	for _, purchaseOrder := range r.data.orders {
+		if purchaseOrder.Status == models.PurchaseOrderStatusReceived {
+			continue
+		}
+		for _, line := range purchaseOrder.Lines {
+			if line.Outstanding() > 0 {
+				line.Unit = r.data.units[line.UnitID]
+				lines = append(lines, line)
+			}
+		}
+	}
+	return lines, nil
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+	"AwisPalace_IngredientManagement/utils"
+)
+
+type IngredientService struct {
+	store     repositories.Store
+	suppliers *SupplierService
+}
+
+func NewIngredientService(store repositories.Store, suppliers *SupplierService) *IngredientService {
+	return &IngredientService{store: store, suppliers: suppliers}
+}
+
+func (s *IngredientService) List(filter repositories.IngredientFilter, params utils.ListParams) ([]models.Ingredient, dto.PageMeta, error) {
+	return s.store.Ingredients().List(filter, params)
+}
+
+// LowStock returns the ingredients that should be reordered
+func (s *IngredientService) LowStock() ([]models.Ingredient, error) {
+	return s.store.Ingredients().LowStock()
+}
+
+// Get returns the ingredient with its suppliers, cheapest first
+func (s *IngredientService) Get(id uint) (models.Ingredient, []SupplierOffer, error) {
+	ingredient, err := s.store.Ingredients().FindByID(id)
+	if err != nil {
+		return ingredient, nil, err
+	}
+
+	offers, err := s.suppliers.IngredientOffers(ingredient)
+	return ingredient, offers, err
+}
+
+// Create saves the ingredient and records its initial stock in the ledger
+func (s *IngredientService) Create(input dto.IngredientParamRequest, userID *uint) (models.Ingredient, error) {
+	ingredient := models.Ingredient{CostMethod: models.CostMethodAverage}
+
+	err := s.store.Transaction(func(store repositories.Store) error {
+		if err := applyIngredientInput(store, &ingredient, input); err != nil {
+			return err
+		}
+		if err := store.Ingredients().Create(&ingredient); err != nil {
+			return err
+		}
+		return adjustIngredientStock(store, &ingredient, input.Stock, "Initial stock", userID)
+	})
+	if err != nil {
+		return ingredient, err
+	}
+
+	return s.store.Ingredients().FindByID(ingredient.ID)
+}
+
+// Update saves the ingredient. A given stock different from the current one
+// is recorded in the ledger as an adjustment.
+func (s *IngredientService) Update(id uint, input dto.IngredientParamRequest, userID *uint) (models.Ingredient, error) {
+	err := s.store.Transaction(func(store repositories.Store) error {
+		// Kunci baris ingredient agar selisih stok dihitung dari stok terbaru
+		ingredient, err := store.Ingredients().FindForUpdate(id)
+		if err != nil {
+			return err
+		}
+
+		if err := applyIngredientInput(store, &ingredient, input); err != nil {
+			return err
+		}
+		if err := store.Ingredients().Update(&ingredient); err != nil {
+			return err
+		}
+		return adjustIngredientStock(store, &ingredient, input.Stock, "Manual stock update", userID)
+	})
+	if err != nil {
+		return models.Ingredient{}, err
+	}
+
+	return s.store.Ingredients().FindByID(id)
+}
+
+func (s *IngredientService) Delete(id uint) error {
+	return s.store.Transaction(func(store repositories.Store) error {
+		ingredient, err := store.Ingredients().FindByID(id)
+		if err != nil {
+			return err
+		}
+		return store.Ingredients().Delete(&ingredient)
+	})
+}
+
+// applyIngredientInput copies input to the ingredient, keeping the current
+// cost and stock levels for omitted fields
+func applyIngredientInput(store repositories.Store, ingredient *models.Ingredient, input dto.IngredientParamRequest) error {
+	if input.UnitID != ingredient.UnitID {
+		// Stok, lot, harga pokok dan ambang stok tersimpan dalam unit lama
+		if ingredient.ID != 0 {
+			hasHistory, err := store.Ingredients().HasStockHistory(ingredient.ID)
+			if err != nil {
+				return err
+			}
+			if hasHistory || ingredient.Stock != 0 {
+				return invalidInput("Cannot change the unit of ingredient %s once it has stock, lots or movements", ingredient.Name)
+			}
+		}
+
+		unit, err := store.Units().FindByID(input.UnitID)
+		if err != nil {
+			return invalidInput("unit with ID %d does not exist", input.UnitID)
+		}
+		ingredient.Unit = unit
+	}
+
+	ingredient.Name = input.Name
+	ingredient.Slug = utils.GenerateSlug(input.Name)
+	ingredient.UnitID = input.UnitID
+	if input.UnitCost != nil {
+		ingredient.UnitCost = *input.UnitCost
+	}
+	if input.CostMethod != "" {
+		ingredient.CostMethod = input.CostMethod
+	}
+	if input.MinimumStock != nil {
+		ingredient.MinimumStock = *input.MinimumStock
+	}
+	if input.ReorderPoint != nil {
+		ingredient.ReorderPoint = *input.ReorderPoint
+	}
+	if input.ParLevel != nil {
+		ingredient.ParLevel = *input.ParLevel
+	}
+	return nil
+}
+
+// adjustIngredientStock mencatat adjustment di ledger agar stok menjadi
+// target, tanpa target stok tidak berubah
+func adjustIngredientStock(store repositories.Store, ingredient *models.Ingredient, target *float64, notes string, userID *uint) error {
+	if target == nil || *target == ingredient.Stock {
+		return nil
+	}
+
+	movement := models.StockMovement{
+		IngredientID:  ingredient.ID,
+		Type:          models.StockMovementAdjustment,
+		Quantity:      *target - ingredient.Stock,
+		UserID:        userID,
+		ReferenceType: "ingredient",
+		ReferenceID:   ingredient.ID,
+		Notes:         notes,
+	}
+
+	if err := store.Ingredients().RecordMovement(&movement); err != nil {
+		return err
+	}
+
+	ingredient.Stock = movement.StockAfter
+	return nil
+}
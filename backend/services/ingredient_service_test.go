@@ -0,0 +1,79 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+)
+
+func TestIngredientServiceRecordsStockChangesInLedger(t *testing.T) {
+	store := newFakeStore()
+	kilogram := store.addUnit(models.Unit{Name: "Kilogram", Symbol: "kg", Dimension: models.DimensionMass, Factor: 1000})
+	service := NewIngredientService(store, NewSupplierService(store))
+
+	rice, err := service.Create(dto.IngredientParamRequest{Name: "Beras", Stock: float64Ptr(10), UnitID: kilogram.ID}, nil)
+	if err != nil {
+		t.Fatalf("create ingredient: %v", err)
+	}
+	if rice.Stock != 10 || rice.Slug != "beras" || rice.CostMethod != models.CostMethodAverage {
+		t.Errorf("created = %+v, want stock 10, slug beras and average cost", rice)
+	}
+
+	updated, err := service.Update(rice.ID, dto.IngredientParamRequest{Name: "Beras Pandan", Stock: float64Ptr(7.5), UnitID: kilogram.ID}, nil)
+	if err != nil {
+		t.Fatalf("update ingredient: %v", err)
+	}
+	if updated.Stock != 7.5 || updated.Name != "Beras Pandan" {
+		t.Errorf("updated = %+v, want Beras Pandan with stock 7.5", updated)
+	}
+
+	movements := store.data.movements
+	if len(movements) != 2 || movements[0].Quantity != 10 || movements[1].Quantity != -2.5 {
+		t.Fatalf("movements = %+v, want +10 then -2.5", movements)
+	}
+	if movements[1].Type != models.StockMovementAdjustment || movements[1].Notes != "Manual stock update" {
+		t.Errorf("update movement = %+v, want manual adjustment", movements[1])
+	}
+
+	if _, err := service.Update(rice.ID, dto.IngredientParamRequest{Name: "Beras", Stock: float64Ptr(-1), UnitID: kilogram.ID}, nil); err == nil {
+		t.Error("negative stock was accepted")
+	}
+	// Update tanpa stok hanya mengubah data ingredient
+	reorderPoint := 3.0
+	kept, err := service.Update(rice.ID, dto.IngredientParamRequest{Name: "Beras Pandan", UnitID: kilogram.ID, ReorderPoint: &reorderPoint}, nil)
+	if err != nil {
+		t.Fatalf("update without stock: %v", err)
+	}
+	if kept.Stock != 7.5 || kept.ReorderPoint != 3 {
+		t.Errorf("kept = %+v, want stock 7.5 and reorder point 3", kept)
+	}
+	if len(store.data.movements) != 2 {
+		t.Errorf("movements = %d, want 2 after an update without stock", len(store.data.movements))
+	}
+
+	// Unit tidak bisa diganti setelah ada stok atau ledger
+	gram := store.addUnit(models.Unit{Name: "Gram", Symbol: "g", Dimension: models.DimensionMass, Factor: 1})
+	if _, err := service.Update(rice.ID, dto.IngredientParamRequest{Name: "Beras Pandan", UnitID: gram.ID}, nil); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("unit change error = %v, want invalid input", err)
+	}
+	salt, err := service.Create(dto.IngredientParamRequest{Name: "Garam", UnitID: kilogram.ID}, nil)
+	if err != nil {
+		t.Fatalf("create salt: %v", err)
+	}
+	if salt, err = service.Update(salt.ID, dto.IngredientParamRequest{Name: "Garam", UnitID: gram.ID}, nil); err != nil || salt.UnitID != gram.ID {
+		t.Errorf("unit change of an unused ingredient = %+v, %v, want gram", salt, err)
+	}
+
+	if _, err := service.Create(dto.IngredientParamRequest{Name: "Gula", UnitID: 999}, nil); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("unknown unit error = %v, want invalid input", err)
+	}
+	if err := service.Delete(999); !errors.Is(err, ErrNotFound) {
+		t.Errorf("delete missing error = %v, want not found", err)
+	}
+}
+
+func float64Ptr(value float64) *float64 {
+	return &value
+}
@@ -0,0 +1,285 @@
+package services
+
+import (
+	"fmt"
+
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+	"AwisPalace_IngredientManagement/utils"
+)
+
+type MenuService struct {
+	store repositories.Store
+}
+
+func NewMenuService(store repositories.Store) *MenuService {
+	return &MenuService{store: store}
+}
+
+// MenuInput are the fields of a created or updated menu. An empty Image keeps
+// the current image on update.
+type MenuInput struct {
+	Name        string
+	Description string
+	Price       float64
+	Image       string
+	Ingredients []dto.MenuIngredientRequest
+}
+
+// List returns a page of menus. With filter.AvailableOnly only the menus that
+// are not sold out and can be made at least once from the current stock are
+// listed.
+func (s *MenuService) List(filter repositories.MenuFilter, params utils.ListParams) ([]models.Menu, dto.PageMeta, error) {
+	if filter.AvailableOnly {
+		menus, err := s.store.Menus().FindAll(filter)
+		if err != nil {
+			return nil, dto.PageMeta{}, err
+		}
+
+		filter.IDs = []uint{}
+		for _, menu := range menus {
+			if portions, err := utils.AvailablePortions(menu.MenuIngredients); err == nil && portions > 0 {
+				filter.IDs = append(filter.IDs, menu.ID)
+			}
+		}
+	}
+
+	return s.store.Menus().List(filter, params)
+}
+
+func (s *MenuService) Get(id uint) (models.Menu, error) {
+	return s.store.Menus().FindByID(id)
+}
+
+// ValidateRecipe checks that every recipe line has a positive quantity and a
+// unit that converts to the stock unit of its ingredient
+func (s *MenuService) ValidateRecipe(items []dto.MenuIngredientRequest) error {
+	return validateRecipe(s.store, items)
+}
+
+func (s *MenuService) Create(input MenuInput) (models.Menu, error) {
+	menu := models.Menu{
+		Name:        input.Name,
+		Slug:        utils.GenerateSlug(input.Name),
+		Description: input.Description,
+		Image:       input.Image,
+		Price:       input.Price,
+	}
+
+	err := s.store.Transaction(func(store repositories.Store) error {
+		if err := validateRecipe(store, input.Ingredients); err != nil {
+			return err
+		}
+		return store.Menus().Create(&menu, toRecipe(input.Ingredients))
+	})
+
+	return menu, err
+}
+
+// Update changes the menu and replaces its recipe. It returns the menu and
+// the image it had before the update.
+func (s *MenuService) Update(id uint, input MenuInput) (models.Menu, string, error) {
+	var menu models.Menu
+	var oldImage string
+
+	err := s.store.Transaction(func(store repositories.Store) error {
+		if err := validateRecipe(store, input.Ingredients); err != nil {
+			return err
+		}
+
+		var err error
+		menu, err = store.Menus().FindByID(id)
+		if err != nil {
+			return err
+		}
+		oldImage = menu.Image
+
+		menu.Name = input.Name
+		menu.Slug = utils.GenerateSlug(input.Name)
+		menu.Description = input.Description
+		menu.Price = input.Price
+
+		// Update image only if new image uploaded
+		if input.Image != "" {
+			menu.Image = input.Image
+		}
+
+		return store.Menus().Update(&menu, toRecipe(input.Ingredients))
+	})
+
+	return menu, oldImage, err
+}
+
+// SetSoldOut marks the menu as sold out (86'd) or available again
+func (s *MenuService) SetSoldOut(id uint, soldOut bool) (models.Menu, error) {
+	if err := s.store.Menus().SetSoldOut(id, soldOut); err != nil {
+		return models.Menu{}, err
+	}
+	return s.store.Menus().FindByID(id)
+}
+
+// SetModifiers replaces the modifier groups of the menu. The options keep
+// their order in groups.
+func (s *MenuService) SetModifiers(id uint, groups []dto.MenuModifierGroupRequest) (models.Menu, error) {
+	var menu models.Menu
+
+	err := s.store.Transaction(func(store repositories.Store) error {
+		var err error
+		menu, err = store.Menus().FindByID(id)
+		if err != nil {
+			return err
+		}
+
+		if err := validateModifiers(store, groups); err != nil {
+			return err
+		}
+
+		if err := store.Menus().ReplaceModifiers(&menu, toModifierGroups(groups)); err != nil {
+			return err
+		}
+
+		menu, err = store.Menus().FindByID(id)
+		return err
+	})
+
+	return menu, err
+}
+
+// Delete removes the menu and its recipe and returns the deleted menu
+func (s *MenuService) Delete(id uint) (models.Menu, error) {
+	var menu models.Menu
+
+	err := s.store.Transaction(func(store repositories.Store) error {
+		var err error
+		menu, err = store.Menus().FindByID(id)
+		if err != nil {
+			return err
+		}
+		return store.Menus().Delete(&menu)
+	})
+
+	return menu, err
+}
+
+func validateRecipe(store repositories.Store, items []dto.MenuIngredientRequest) error {
+	if len(items) == 0 {
+		return invalidInput("At least one ingredient is required")
+	}
+
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return invalidInput("quantity of ingredient %d must be positive", item.IngredientID)
+		}
+
+		if err := validateRecipeUnit(store, item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateRecipeUnit checks the unit of item converts to the stock unit of
+// its ingredient
+func validateRecipeUnit(store repositories.Store, item dto.MenuIngredientRequest) error {
+	ingredient, err := store.Ingredients().FindByID(item.IngredientID)
+	if err != nil {
+		return err
+	}
+
+	unit, err := store.Units().FindByID(item.UnitID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := utils.ConvertQuantity(1, unit, ingredient.Unit, ingredient.Conversions); err != nil {
+		return fmt.Errorf("ingredient %s: %w", ingredient.Name, err)
+	}
+	return nil
+}
+
+func validateModifiers(store repositories.Store, groups []dto.MenuModifierGroupRequest) error {
+	for _, group := range groups {
+		if group.Name == "" {
+			return invalidInput("modifier group name is required")
+		}
+		if len(group.Options) == 0 {
+			return invalidInput("modifier group %s needs at least one option", group.Name)
+		}
+		if group.MinSelect < 0 || group.MaxSelect < 0 {
+			return invalidInput("selection limits of modifier group %s cannot be negative", group.Name)
+		}
+		if group.MinSelect > len(group.Options) {
+			return invalidInput("modifier group %s requires %d options but has %d", group.Name, group.MinSelect, len(group.Options))
+		}
+		if group.MaxSelect > 0 && group.MinSelect > group.MaxSelect {
+			return invalidInput("min_select of modifier group %s cannot exceed max_select", group.Name)
+		}
+
+		for _, option := range group.Options {
+			if option.Name == "" {
+				return invalidInput("modifier option name in group %s is required", group.Name)
+			}
+
+			for _, item := range option.Ingredients {
+				if item.Quantity == 0 {
+					return invalidInput("quantity of ingredient %d in modifier %s cannot be zero", item.IngredientID, option.Name)
+				}
+				if err := validateRecipeUnit(store, item); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func toModifierGroups(groups []dto.MenuModifierGroupRequest) []models.MenuModifierGroup {
+	modifierGroups := make([]models.MenuModifierGroup, 0, len(groups))
+	for i, group := range groups {
+		modifierGroup := models.MenuModifierGroup{
+			Name:      group.Name,
+			MinSelect: group.MinSelect,
+			MaxSelect: group.MaxSelect,
+			SortOrder: i,
+		}
+
+		for j, option := range group.Options {
+			modifierGroup.Options = append(modifierGroup.Options, models.MenuModifierOption{
+				Name:        option.Name,
+				PriceDelta:  option.PriceDelta,
+				SortOrder:   j,
+				Ingredients: toModifierIngredients(option.Ingredients),
+			})
+		}
+
+		modifierGroups = append(modifierGroups, modifierGroup)
+	}
+	return modifierGroups
+}
+
+func toModifierIngredients(items []dto.MenuIngredientRequest) []models.MenuModifierIngredient {
+	ingredients := make([]models.MenuModifierIngredient, 0, len(items))
+	for _, item := range items {
+		ingredients = append(ingredients, models.MenuModifierIngredient{
+			IngredientID: item.IngredientID,
+			Quantity:     item.Quantity,
+			UnitID:       item.UnitID,
+		})
+	}
+	return ingredients
+}
+
+func toRecipe(items []dto.MenuIngredientRequest) []models.MenuIngredient {
+	recipe := make([]models.MenuIngredient, 0, len(items))
+	for _, item := range items {
+		recipe = append(recipe, models.MenuIngredient{
+			IngredientID: item.IngredientID,
+			Quantity:     item.Quantity,
+			UnitID:       item.UnitID,
+		})
+	}
+	return recipe
+}
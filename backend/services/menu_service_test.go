@@ -0,0 +1,133 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+	"AwisPalace_IngredientManagement/utils"
+)
+
+func TestMenuServiceValidateRecipe(t *testing.T) {
+	store := newFakeStore()
+	kilogram := store.addUnit(models.Unit{Name: "Kilogram", Symbol: "kg", Dimension: models.DimensionMass, Factor: 1000})
+	gram := store.addUnit(models.Unit{Name: "Gram", Symbol: "g", Dimension: models.DimensionMass, Factor: 1})
+	liter := store.addUnit(models.Unit{Name: "Liter", Symbol: "l", Dimension: models.DimensionVolume, Factor: 1000})
+	rice := store.addIngredient(models.Ingredient{Name: "Beras", UnitID: kilogram.ID})
+
+	service := NewMenuService(store)
+
+	tests := []struct {
+		name  string
+		items []dto.MenuIngredientRequest
+		want  error
+	}{
+		{"convertible unit", []dto.MenuIngredientRequest{{IngredientID: rice.ID, Quantity: 200, UnitID: gram.ID}}, nil},
+		{"empty recipe", nil, ErrInvalidInput},
+		{"negative quantity", []dto.MenuIngredientRequest{{IngredientID: rice.ID, Quantity: -1, UnitID: gram.ID}}, ErrInvalidInput},
+		{"unknown ingredient", []dto.MenuIngredientRequest{{IngredientID: 999, Quantity: 1, UnitID: gram.ID}}, ErrNotFound},
+		{"unknown unit", []dto.MenuIngredientRequest{{IngredientID: rice.ID, Quantity: 1, UnitID: 999}}, ErrNotFound},
+		{"incompatible unit", []dto.MenuIngredientRequest{{IngredientID: rice.ID, Quantity: 1, UnitID: liter.ID}}, utils.ErrIncompatibleUnit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.ValidateRecipe(tt.items)
+			if tt.want == nil && err != nil {
+				t.Fatalf("err = %v, want nil", err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMenuServiceUpdateReplacesRecipeAndKeepsImage(t *testing.T) {
+	store := newFakeStore()
+	gram := store.addUnit(models.Unit{Name: "Gram", Symbol: "g", Dimension: models.DimensionMass, Factor: 1})
+	rice := store.addIngredient(models.Ingredient{Name: "Beras", UnitID: gram.ID})
+	egg := store.addIngredient(models.Ingredient{Name: "Telur", UnitID: gram.ID})
+
+	service := NewMenuService(store)
+
+	menu, err := service.Create(MenuInput{
+		Name:        "Nasi Goreng",
+		Price:       15000,
+		Image:       "old.png",
+		Ingredients: []dto.MenuIngredientRequest{{IngredientID: rice.ID, Quantity: 200, UnitID: gram.ID}},
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if menu.Slug != "nasi-goreng" {
+		t.Errorf("slug = %s, want nasi-goreng", menu.Slug)
+	}
+
+	updated, oldImage, err := service.Update(menu.ID, MenuInput{
+		Name:        "Nasi Goreng Telur",
+		Price:       18000,
+		Ingredients: []dto.MenuIngredientRequest{{IngredientID: egg.ID, Quantity: 60, UnitID: gram.ID}},
+	})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	if oldImage != "old.png" || updated.Image != "old.png" {
+		t.Errorf("image = %s (old %s), want old.png kept", updated.Image, oldImage)
+	}
+
+	stored := store.data.menus[menu.ID]
+	if len(stored.MenuIngredients) != 1 || stored.MenuIngredients[0].IngredientID != egg.ID {
+		t.Errorf("recipe = %+v, want only the egg", stored.MenuIngredients)
+	}
+}
+
+func TestMenuServiceListAvailableOnly(t *testing.T) {
+	store := newFakeStore()
+	kilogram := store.addUnit(models.Unit{Name: "Kilogram", Symbol: "kg", Dimension: models.DimensionMass, Factor: 1000})
+	gram := store.addUnit(models.Unit{Name: "Gram", Symbol: "g", Dimension: models.DimensionMass, Factor: 1})
+	rice := store.addIngredient(models.Ingredient{Name: "Beras", Stock: 1, UnitID: kilogram.ID})
+	egg := store.addIngredient(models.Ingredient{Name: "Telur", Stock: 0.05, UnitID: kilogram.ID})
+
+	recipe := func(lines ...models.MenuIngredient) models.Menu {
+		return models.Menu{MenuIngredients: lines}
+	}
+	// 1 kg / 300 g = 3 porsi, telur 50 g / 60 g = 0 porsi
+	friedRice := recipe(
+		models.MenuIngredient{IngredientID: rice.ID, Quantity: 200, UnitID: gram.ID},
+		models.MenuIngredient{IngredientID: rice.ID, Quantity: 100, UnitID: gram.ID},
+	)
+	friedRice.Name = "Nasi Goreng"
+	friedRice = store.addMenu(friedRice)
+
+	omelette := recipe(models.MenuIngredient{IngredientID: egg.ID, Quantity: 60, UnitID: gram.ID})
+	omelette.Name = "Telur Dadar"
+	store.addMenu(omelette)
+
+	plainRice := recipe(models.MenuIngredient{IngredientID: rice.ID, Quantity: 100, UnitID: gram.ID})
+	plainRice.Name = "Nasi Putih"
+	plainRice = store.addMenu(plainRice)
+
+	service := NewMenuService(store)
+	if _, err := service.SetSoldOut(plainRice.ID, true); err != nil {
+		t.Fatalf("set sold out: %v", err)
+	}
+
+	menus, _, err := service.List(repositories.MenuFilter{AvailableOnly: true}, utils.ListParams{})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(menus) != 1 || menus[0].ID != friedRice.ID {
+		t.Fatalf("available menus = %+v, want Nasi Goreng only", menus)
+	}
+	if portions, err := utils.AvailablePortions(menus[0].MenuIngredients); err != nil || portions != 3 {
+		t.Errorf("portions = %d (%v), want 3", portions, err)
+	}
+
+	if _, err := service.SetSoldOut(999, true); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unknown menu err = %v, want ErrNotFound", err)
+	}
+}
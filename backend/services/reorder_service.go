@@ -0,0 +1,302 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+	"AwisPalace_IngredientManagement/utils"
+)
+
+// DefaultReorderWindowDays is the sales history used to estimate daily usage
+const DefaultReorderWindowDays = 28
+
+// MaxReorderWindowDays caps the sales history read for a suggestion
+const MaxReorderWindowDays = 365
+
+type ReorderService struct {
+	store     repositories.Store
+	suppliers *SupplierService
+	now       func() time.Time
+}
+
+func NewReorderService(store repositories.Store, suppliers *SupplierService) *ReorderService {
+	return &ReorderService{
+		store:     store,
+		suppliers: suppliers,
+		now:       time.Now,
+	}
+}
+
+// ReorderSuggestion is the quantity of an ingredient to order so the stock is
+// back at its par level when the delivery arrives. Quantities are in the
+// ingredient's stock unit unless noted otherwise.
+type ReorderSuggestion struct {
+	Ingredient        models.Ingredient
+	AverageDailyUsage float64
+	OnOrder           float64 // Sisa yang belum diterima dari PO draft dan terkirim
+	LeadTimeDays      int
+	StockAtDelivery   float64 // Perkiraan stok saat barang datang
+	SuggestedQuantity float64
+
+	Offer         *SupplierOffer // Supplier termurah, kosong jika belum ada supplier
+	OrderQuantity float64        // Dalam OrderUnit, dibulatkan ke atas per kemasan
+	OrderUnit     models.Unit
+	Packs         int // Jumlah kemasan, 0 jika tanpa supplier
+	UnitCost      float64
+	EstimatedCost float64
+}
+
+// Suggestions returns the ingredients that should be ordered now, by name.
+// Daily usage is the average of the sales of the last windowDays days.
+//
+// An ingredient is suggested when the stock expected at delivery (current
+// stock plus open purchase orders minus the usage during the lead time of its
+// cheapest supplier) is at or below its reorder point or minimum stock, or
+// would run out. The suggested quantity brings it back to the par level.
+func (s *ReorderService) Suggestions(windowDays int) ([]ReorderSuggestion, error) {
+	if windowDays < 1 || windowDays > MaxReorderWindowDays {
+		return nil, invalidInput("days must be between 1 and %d", MaxReorderWindowDays)
+	}
+
+	ingredients, err := s.store.Ingredients().FindByIDs(nil)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.Ingredient, len(ingredients))
+	for _, ingredient := range ingredients {
+		byID[ingredient.ID] = ingredient
+	}
+
+	end := s.now()
+	usage, err := s.usage(byID, end.AddDate(0, 0, -windowDays), end)
+	if err != nil {
+		return nil, err
+	}
+
+	onOrder, err := s.onOrder(byID)
+	if err != nil {
+		return nil, err
+	}
+
+	var suggestions []ReorderSuggestion
+	for _, ingredient := range ingredients {
+		offers, err := s.suppliers.IngredientOffers(ingredient)
+		if err != nil {
+			return nil, err
+		}
+
+		suggestion, ok, err := suggestReorder(ingredient, usage[ingredient.ID]/float64(windowDays), onOrder[ingredient.ID], offers)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+
+	return suggestions, nil
+}
+
+// CreateDrafts turns the suggestions for ingredientIDs (all suggestions when
+// empty) into draft purchase orders, one per supplier. Ingredients without a
+// supplier share one purchase order without supplier.
+func (s *ReorderService) CreateDrafts(windowDays int, ingredientIDs []uint) ([]models.PurchaseOrder, error) {
+	suggestions, err := s.Suggestions(windowDays)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ingredientIDs) > 0 {
+		suggestions = slices.DeleteFunc(suggestions, func(suggestion ReorderSuggestion) bool {
+			return !slices.Contains(ingredientIDs, suggestion.Ingredient.ID)
+		})
+	}
+	if len(suggestions) == 0 {
+		return nil, invalidInput("no ingredients need to be reordered")
+	}
+
+	// Urutan PO mengikuti urutan supplier pertama kali muncul
+	var supplierIDs []uint
+	groups := map[uint][]ReorderSuggestion{}
+	for _, suggestion := range suggestions {
+		var supplierID uint
+		if suggestion.Offer != nil {
+			supplierID = suggestion.Offer.SupplierID
+		}
+		if _, ok := groups[supplierID]; !ok {
+			supplierIDs = append(supplierIDs, supplierID)
+		}
+		groups[supplierID] = append(groups[supplierID], suggestion)
+	}
+
+	var purchaseOrders []models.PurchaseOrder
+	err = s.store.Transaction(func(store repositories.Store) error {
+		now := s.now()
+		var ids []uint
+
+		for _, supplierID := range supplierIDs {
+			purchaseOrder := models.PurchaseOrder{
+				PONumber:  utils.NewPurchaseOrderNumber(now),
+				OrderDate: now,
+				Status:    models.PurchaseOrderStatusDraft,
+				Notes:     fmt.Sprintf("Generated from reorder suggestions (%d-day usage)", windowDays),
+			}
+
+			group := groups[supplierID]
+			if supplierID != 0 {
+				purchaseOrder.SupplierID = &supplierID
+				expectedDate := now.AddDate(0, 0, group[0].LeadTimeDays)
+				purchaseOrder.ExpectedDate = &expectedDate
+			}
+
+			for _, suggestion := range group {
+				purchaseOrder.Lines = append(purchaseOrder.Lines, models.PurchaseOrderLine{
+					IngredientID: suggestion.Ingredient.ID,
+					Quantity:     suggestion.OrderQuantity,
+					UnitID:       suggestion.OrderUnit.ID,
+					UnitCost:     suggestion.UnitCost,
+				})
+				purchaseOrder.TotalAmount += suggestion.OrderQuantity * suggestion.UnitCost
+			}
+
+			if err := store.PurchaseOrders().Create(&purchaseOrder); err != nil {
+				return err
+			}
+			ids = append(ids, purchaseOrder.ID)
+		}
+
+		purchaseOrders = purchaseOrders[:0]
+		for _, id := range ids {
+			purchaseOrder, err := store.PurchaseOrders().FindByID(id)
+			if err != nil {
+				return err
+			}
+			purchaseOrders = append(purchaseOrders, purchaseOrder)
+		}
+		return nil
+	})
+
+	return purchaseOrders, err
+}
+
+// usage sums the sales usage per ingredient in its current stock unit
+func (s *ReorderService) usage(ingredients map[uint]models.Ingredient, start, end time.Time) (map[uint]float64, error) {
+	rows, err := s.store.Transactions().Usage(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := map[uint]float64{}
+	units := map[uint]models.Unit{}
+	for _, row := range rows {
+		ingredient, ok := ingredients[row.IngredientID]
+		if !ok {
+			continue
+		}
+
+		quantity := row.Quantity
+		// Unit stok bisa berubah sejak penjualan
+		if row.UnitID != ingredient.UnitID {
+			unit, ok := units[row.UnitID]
+			if !ok {
+				if unit, err = s.store.Units().FindByID(row.UnitID); err != nil {
+					return nil, err
+				}
+				units[row.UnitID] = unit
+			}
+
+			if quantity, err = utils.ConvertQuantity(quantity, unit, ingredient.Unit, ingredient.Conversions); err != nil {
+				return nil, fmt.Errorf("ingredient %s: %w", ingredient.Name, err)
+			}
+		}
+
+		usage[row.IngredientID] += quantity
+	}
+
+	return usage, nil
+}
+
+// onOrder sums the quantity still expected from open purchase orders per
+// ingredient in its stock unit
+func (s *ReorderService) onOrder(ingredients map[uint]models.Ingredient) (map[uint]float64, error) {
+	lines, err := s.store.PurchaseOrders().OpenLines()
+	if err != nil {
+		return nil, err
+	}
+
+	onOrder := map[uint]float64{}
+	for _, line := range lines {
+		ingredient, ok := ingredients[line.IngredientID]
+		if !ok {
+			continue
+		}
+
+		quantity, err := utils.ConvertQuantity(line.Outstanding(), line.Unit, ingredient.Unit, ingredient.Conversions)
+		if err != nil {
+			return nil, fmt.Errorf("ingredient %s: %w", ingredient.Name, err)
+		}
+		onOrder[line.IngredientID] += quantity
+	}
+
+	return onOrder, nil
+}
+
+// suggestReorder computes the suggestion for one ingredient. offers must be
+// sorted cheapest first, the first one is the supplier to order from.
+func suggestReorder(ingredient models.Ingredient, dailyUsage, onOrder float64, offers []SupplierOffer) (ReorderSuggestion, bool, error) {
+	suggestion := ReorderSuggestion{
+		Ingredient:        ingredient,
+		AverageDailyUsage: roundQuantity(dailyUsage),
+		OnOrder:           roundQuantity(onOrder),
+	}
+	if len(offers) > 0 {
+		suggestion.Offer = &offers[0]
+		suggestion.LeadTimeDays = offers[0].Supplier.LeadTimeDays
+	}
+
+	leadTimeUsage := dailyUsage * float64(suggestion.LeadTimeDays)
+	stockAtDelivery := ingredient.Stock + onOrder - leadTimeUsage
+	suggestion.StockAtDelivery = roundQuantity(stockAtDelivery)
+
+	reorderLevel := max(ingredient.ReorderPoint, ingredient.MinimumStock)
+	if stockAtDelivery > reorderLevel || (reorderLevel == 0 && stockAtDelivery >= 0) {
+		return suggestion, false, nil
+	}
+
+	target := max(ingredient.ParLevel, reorderLevel)
+	suggestion.SuggestedQuantity = roundQuantity(target - stockAtDelivery)
+	if suggestion.SuggestedQuantity <= 0 {
+		return suggestion, false, nil
+	}
+
+	if suggestion.Offer == nil {
+		suggestion.OrderQuantity = suggestion.SuggestedQuantity
+		suggestion.OrderUnit = ingredient.Unit
+		suggestion.UnitCost = ingredient.UnitCost
+		suggestion.EstimatedCost = suggestion.OrderQuantity * suggestion.UnitCost
+		return suggestion, true, nil
+	}
+
+	offer := suggestion.Offer
+	inPackUnit, err := utils.ConvertQuantity(suggestion.SuggestedQuantity, ingredient.Unit, offer.PackUnit, ingredient.Conversions)
+	if err != nil {
+		return suggestion, false, fmt.Errorf("ingredient %s: %w", ingredient.Name, err)
+	}
+
+	// Toleransi kecil agar pembulatan float tidak menambah satu kemasan
+	suggestion.Packs = int(math.Ceil(inPackUnit/offer.PackSize - 1e-9))
+	suggestion.OrderQuantity = float64(suggestion.Packs) * offer.PackSize
+	suggestion.OrderUnit = offer.PackUnit
+	suggestion.UnitCost = offer.LastPrice / offer.PackSize
+	suggestion.EstimatedCost = float64(suggestion.Packs) * offer.LastPrice
+	return suggestion, true, nil
+}
+
+// roundQuantity membulatkan ke 4 desimal, presisi yang sama dengan kolom stok
+func roundQuantity(quantity float64) float64 {
+	return math.Round(quantity*10000) / 10000
+}
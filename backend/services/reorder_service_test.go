@@ -0,0 +1,129 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+)
+
+func TestReorderServiceSuggestionsAndDrafts(t *testing.T) {
+	store := newFakeStore()
+	gram := store.addUnit(models.Unit{Name: "Gram", Symbol: "g", Dimension: models.DimensionMass, Factor: 1})
+	kilogram := store.addUnit(models.Unit{Name: "Kilogram", Symbol: "kg", Dimension: models.DimensionMass, Factor: 1000})
+	flour := store.addIngredient(models.Ingredient{Name: "Tepung", Stock: 8, UnitID: kilogram.ID, ReorderPoint: 5, ParLevel: 30})
+	sugar := store.addIngredient(models.Ingredient{Name: "Gula", Stock: 1, UnitID: kilogram.ID, UnitCost: 15000, ReorderPoint: 2, ParLevel: 5})
+	store.addIngredient(models.Ingredient{Name: "Garam", Stock: 10, UnitID: kilogram.ID})
+
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.Local)
+	sale := func(daysAgo int, status string, portions int, quantity float64, unitID uint) models.TransactionItem {
+		transaction := models.Transaction{TransactionDate: now.AddDate(0, 0, -daysAgo), Status: status}
+		if err := store.Transactions().Create(&transaction); err != nil {
+			t.Fatalf("create transaction: %v", err)
+		}
+		item := models.TransactionItem{TransactionID: transaction.ID, Quantity: portions}
+		store.Transactions().CreateItem(&item)
+		store.Transactions().CreateStockReduction(&models.StockReduction{
+			TransactionItemID: item.ID, IngredientID: flour.ID, QuantityReduced: quantity, UnitID: unitID,
+		})
+		return item
+	}
+	// 56 kg dalam 28 hari = 2 kg per hari, sebagian tercatat dalam gram
+	sale(3, models.TransactionStatusCompleted, 1, 28, kilogram.ID)
+	refunded := sale(10, models.TransactionStatusRefunded, 2, 56000, gram.ID)
+	sale(5, models.TransactionStatusCancelled, 1, 100, kilogram.ID)
+	sale(40, models.TransactionStatusCompleted, 1, 100, kilogram.ID)
+
+	// Hanya porsi yang stoknya dikembalikan yang tidak dihitung sebagai pemakaian
+	store.Transactions().CreateRefund(&models.Refund{TransactionID: refunded.TransactionID, Items: []models.RefundItem{
+		{TransactionItemID: refunded.ID, Quantity: 1, Restocked: true},
+		{TransactionItemID: refunded.ID, Quantity: 1},
+	}})
+
+	suppliers := NewSupplierService(store)
+	for _, offer := range []struct {
+		name  string
+		price float64
+	}{{"Pasar Lokal", 300000}, {"Toko Grosir", 250000}} {
+		supplier, err := suppliers.Create(dto.SupplierRequest{Name: offer.name, LeadTimeDays: 3})
+		if err != nil {
+			t.Fatalf("create supplier: %v", err)
+		}
+		if _, err := suppliers.SetIngredient(supplier.ID, flour.ID, dto.SupplierIngredientRequest{PackSize: 25, PackUnitID: kilogram.ID, LastPrice: offer.price}); err != nil {
+			t.Fatalf("set supplier ingredient: %v", err)
+		}
+	}
+
+	// PO terkirim yang masih menunggu 2 kg
+	store.PurchaseOrders().Create(&models.PurchaseOrder{Status: models.PurchaseOrderStatusSent, Lines: []models.PurchaseOrderLine{
+		{IngredientID: flour.ID, Quantity: 2000, UnitID: gram.ID},
+	}})
+
+	service := NewReorderService(store, suppliers)
+	service.now = func() time.Time { return now }
+
+	suggestions, err := service.Suggestions(28)
+	if err != nil {
+		t.Fatalf("suggestions: %v", err)
+	}
+	if len(suggestions) != 2 || suggestions[0].Ingredient.ID != sugar.ID || suggestions[1].Ingredient.ID != flour.ID {
+		t.Fatalf("suggestions = %+v, want Gula and Tepung", suggestions)
+	}
+
+	// Tepung: 8 + 2 dipesan - 3 hari x 2 kg = 4 kg saat barang datang, 26 kg ke par level
+	flourSuggestion := suggestions[1]
+	if flourSuggestion.AverageDailyUsage != 2 || flourSuggestion.OnOrder != 2 || flourSuggestion.StockAtDelivery != 4 {
+		t.Errorf("flour usage = %+v, want 2/day, 2 on order, 4 at delivery", flourSuggestion)
+	}
+	if flourSuggestion.SuggestedQuantity != 26 || flourSuggestion.Packs != 2 || flourSuggestion.OrderQuantity != 50 {
+		t.Errorf("flour order = %v suggested, %d packs, %v kg; want 26, 2 and 50", flourSuggestion.SuggestedQuantity, flourSuggestion.Packs, flourSuggestion.OrderQuantity)
+	}
+	if flourSuggestion.Offer == nil || flourSuggestion.Offer.Supplier.Name != "Toko Grosir" || flourSuggestion.EstimatedCost != 500000 {
+		t.Errorf("flour supplier = %+v, want the cheapest supplier at 500000", flourSuggestion.Offer)
+	}
+
+	sugarSuggestion := suggestions[0]
+	if sugarSuggestion.Offer != nil || sugarSuggestion.OrderQuantity != 4 || sugarSuggestion.EstimatedCost != 60000 {
+		t.Errorf("sugar suggestion = %+v, want 4 kg at the ingredient cost without supplier", sugarSuggestion)
+	}
+
+	purchaseOrders, err := service.CreateDrafts(28, nil)
+	if err != nil {
+		t.Fatalf("create drafts: %v", err)
+	}
+	if len(purchaseOrders) != 2 {
+		t.Fatalf("purchase orders = %d, want one without supplier and one for Toko Grosir", len(purchaseOrders))
+	}
+	if purchaseOrders[0].SupplierID != nil || purchaseOrders[0].Lines[0].IngredientID != sugar.ID {
+		t.Errorf("first purchase order = %+v, want sugar without supplier", purchaseOrders[0])
+	}
+	bulk := purchaseOrders[1]
+	if bulk.Supplier == nil || bulk.Supplier.Name != "Toko Grosir" || bulk.Status != models.PurchaseOrderStatusDraft {
+		t.Errorf("second purchase order supplier = %+v, want a Toko Grosir draft", bulk.Supplier)
+	}
+	if bulk.TotalAmount != 500000 || bulk.ExpectedDate == nil || !bulk.ExpectedDate.Equal(now.AddDate(0, 0, 3)) {
+		t.Errorf("second purchase order = total %v expected %v, want 500000 in 3 days", bulk.TotalAmount, bulk.ExpectedDate)
+	}
+
+	// Draft yang baru dibuat ikut dihitung sebagai barang dalam pesanan
+	if _, err := service.CreateDrafts(28, nil); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("second drafts err = %v, want ErrInvalidInput", err)
+	}
+	if _, err := service.Suggestions(0); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("zero days err = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestSuggestReorderKeepsFourDecimals(t *testing.T) {
+	ingredient := models.Ingredient{Name: "Saffron", Stock: 0.0015, ReorderPoint: 0.002, ParLevel: 0.005}
+
+	suggestion, ok, err := suggestReorder(ingredient, 0.00012, 0, nil)
+	if err != nil || !ok {
+		t.Fatalf("suggest = %v, %v; want a suggestion", ok, err)
+	}
+	if suggestion.AverageDailyUsage != 0.0001 || suggestion.SuggestedQuantity != 0.0035 {
+		t.Errorf("suggestion = %v/day, %v suggested; want 0.0001 and 0.0035", suggestion.AverageDailyUsage, suggestion.SuggestedQuantity)
+	}
+}
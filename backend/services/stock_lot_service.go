@@ -0,0 +1,42 @@
+package services
+
+import (
+	"time"
+
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+)
+
+type StockLotService struct {
+	store repositories.Store
+	now   func() time.Time
+}
+
+func NewStockLotService(store repositories.Store) *StockLotService {
+	return &StockLotService{
+		store: store,
+		now:   time.Now,
+	}
+}
+
+// IngredientLots returns the open lots of an ingredient in FEFO order
+func (s *StockLotService) IngredientLots(ingredientID uint) ([]models.StockLot, error) {
+	if _, err := s.store.Ingredients().FindByID(ingredientID); err != nil {
+		return nil, err
+	}
+	return s.store.StockLots().ListByIngredient(ingredientID)
+}
+
+// Expiring returns the open lots that expire within days, including lots
+// that have already expired
+func (s *StockLotService) Expiring(days int) ([]models.StockLot, error) {
+	if days < 0 {
+		return nil, invalidInput("days must not be negative")
+	}
+
+	// Sampai akhir hari ke-N
+	now := s.now()
+	before := time.Date(now.Year(), now.Month(), now.Day()+days+1, 0, 0, 0, 0, now.Location())
+
+	return s.store.StockLots().ExpiringBefore(before)
+}
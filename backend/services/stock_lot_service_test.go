@@ -0,0 +1,48 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"AwisPalace_IngredientManagement/models"
+)
+
+func TestStockLotServiceExpiring(t *testing.T) {
+	store := newFakeStore()
+	kilogram := store.addUnit(models.Unit{Name: "Kilogram", Symbol: "kg", Dimension: models.DimensionMass, Factor: 1000})
+	milk := store.addIngredient(models.Ingredient{Name: "Susu", Stock: 6, UnitID: kilogram.ID})
+
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.Local)
+	day := func(offset int) *time.Time {
+		date := time.Date(2026, 10, 17+offset, 0, 0, 0, 0, time.Local)
+		return &date
+	}
+
+	expired := store.addLot(models.StockLot{IngredientID: milk.ID, ExpiryDate: day(-1), Quantity: 2, Remaining: 1})
+	soon := store.addLot(models.StockLot{IngredientID: milk.ID, ExpiryDate: day(3), Quantity: 2, Remaining: 2})
+	store.addLot(models.StockLot{IngredientID: milk.ID, ExpiryDate: day(4), Quantity: 2, Remaining: 2})
+	store.addLot(models.StockLot{IngredientID: milk.ID, ExpiryDate: day(1), Quantity: 2, Remaining: 0})
+	store.addLot(models.StockLot{IngredientID: milk.ID, Quantity: 1, Remaining: 1})
+
+	service := NewStockLotService(store)
+	service.now = func() time.Time { return now }
+
+	lots, err := service.Expiring(3)
+	if err != nil {
+		t.Fatalf("expiring: %v", err)
+	}
+	if len(lots) != 2 || lots[0].ID != expired.ID || lots[1].ID != soon.ID {
+		t.Errorf("expiring lots = %+v, want the expired lot and the one expiring in 3 days", lots)
+	}
+	if days := lots[1].DaysUntilExpiry(now); days == nil || *days != 3 {
+		t.Errorf("days until expiry = %v, want 3", days)
+	}
+
+	if _, err := service.Expiring(-1); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("negative days err = %v, want ErrInvalidInput", err)
+	}
+	if _, err := service.IngredientLots(999); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unknown ingredient err = %v, want ErrNotFound", err)
+	}
+}
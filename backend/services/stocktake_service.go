@@ -0,0 +1,296 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+	"AwisPalace_IngredientManagement/utils"
+)
+
+type StocktakeService struct {
+	store repositories.Store
+	codes *utils.CodeGenerator
+	now   func() time.Time
+}
+
+func NewStocktakeService(store repositories.Store, codes *utils.CodeGenerator) *StocktakeService {
+	return &StocktakeService{
+		store: store,
+		codes: codes,
+		now:   time.Now,
+	}
+}
+
+// NewStocktakeCodeGenerator builds the generator of codes such as STK-20261017-01
+func NewStocktakeCodeGenerator(outlet string) (*utils.CodeGenerator, error) {
+	return utils.NewCodeGenerator("stocktake", utils.DefaultStocktakeCodeFormat, outlet)
+}
+
+func (s *StocktakeService) List(filter repositories.StocktakeFilter, params utils.ListParams) ([]models.Stocktake, dto.PageMeta, error) {
+	return s.store.Stocktakes().List(filter, params)
+}
+
+func (s *StocktakeService) Get(id uint) (models.Stocktake, error) {
+	return s.store.Stocktakes().FindByID(id)
+}
+
+// Create starts a draft stocktake of the given ingredients, all ingredients
+// when input.IngredientIDs is empty
+func (s *StocktakeService) Create(input dto.StocktakeCreateRequest, userID *uint) (models.Stocktake, error) {
+	var stocktake models.Stocktake
+
+	err := s.store.Transaction(func(store repositories.Store) error {
+		ids := slices.Clone(input.IngredientIDs)
+		slices.Sort(ids)
+		ids = slices.Compact(ids)
+
+		ingredients, err := store.Ingredients().FindByIDs(ids)
+		if err != nil {
+			return err
+		}
+		if err := checkAllFound(ids, ingredients); err != nil {
+			return err
+		}
+		if len(ingredients) == 0 {
+			return invalidInput("There are no ingredients to count")
+		}
+
+		code, err := s.codes.Next(s.now(), store.Sequences().Next)
+		if err != nil {
+			return err
+		}
+
+		stocktake = models.Stocktake{
+			Code:        code,
+			Status:      models.StocktakeStatusDraft,
+			Notes:       input.Notes,
+			CreatedByID: userID,
+		}
+		for _, ingredient := range ingredients {
+			stocktake.Lines = append(stocktake.Lines, models.StocktakeLine{
+				IngredientID: ingredient.ID,
+				UnitID:       ingredient.UnitID,
+			})
+		}
+
+		return store.Stocktakes().Create(&stocktake)
+	})
+	if err != nil {
+		return stocktake, err
+	}
+
+	return s.Get(stocktake.ID)
+}
+
+// Start moves a draft stocktake to counting and takes the current stock and
+// unit cost of every ingredient as the theoretical values
+func (s *StocktakeService) Start(id uint) (models.Stocktake, error) {
+	err := s.store.Transaction(func(store repositories.Store) error {
+		stocktake, err := store.Stocktakes().FindForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if stocktake.Status != models.StocktakeStatusDraft {
+			return conflict("Stocktake %s is %s, only a draft can be started", stocktake.Code, stocktake.Status)
+		}
+
+		ids := make([]uint, 0, len(stocktake.Lines))
+		for _, line := range stocktake.Lines {
+			ids = append(ids, line.IngredientID)
+		}
+
+		// Kunci ingredient agar stok teoritis tidak berubah di tengah pengambilan
+		if err := store.Ingredients().Lock(ids); err != nil {
+			return err
+		}
+
+		for _, line := range stocktake.Lines {
+			ingredient, err := store.Ingredients().FindByID(line.IngredientID)
+			if err != nil {
+				return err
+			}
+
+			line.TheoreticalStock = ingredient.Stock
+			line.UnitID = ingredient.UnitID
+			line.UnitCost = ingredient.UnitCost
+			if err := store.Stocktakes().UpdateLine(&line); err != nil {
+				return err
+			}
+		}
+
+		now := s.now()
+		stocktake.Status = models.StocktakeStatusCounting
+		stocktake.StartedAt = &now
+		return store.Stocktakes().Update(&stocktake)
+	})
+	if err != nil {
+		return models.Stocktake{}, err
+	}
+
+	return s.Get(id)
+}
+
+// Count records counted quantities of a stocktake that is being counted.
+// Counting an ingredient again replaces the earlier quantity.
+func (s *StocktakeService) Count(id uint, counts []dto.StocktakeCountItem) (models.Stocktake, error) {
+	err := s.store.Transaction(func(store repositories.Store) error {
+		stocktake, err := store.Stocktakes().FindForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if stocktake.Status != models.StocktakeStatusCounting {
+			return conflict("Stocktake %s is %s, quantities can only be entered while counting", stocktake.Code, stocktake.Status)
+		}
+
+		lines := make(map[uint]*models.StocktakeLine, len(stocktake.Lines))
+		for i := range stocktake.Lines {
+			lines[stocktake.Lines[i].IngredientID] = &stocktake.Lines[i]
+		}
+
+		for _, count := range counts {
+			line, ok := lines[count.IngredientID]
+			if !ok {
+				return invalidInput("Ingredient %d is not part of stocktake %s", count.IngredientID, stocktake.Code)
+			}
+			if count.CountedQuantity == nil || *count.CountedQuantity < 0 {
+				return invalidInput("Counted quantity of ingredient %d must not be negative", count.IngredientID)
+			}
+
+			quantity := *count.CountedQuantity
+			line.CountedQuantity = &quantity
+			if err := store.Stocktakes().UpdateLine(line); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return models.Stocktake{}, err
+	}
+
+	return s.Get(id)
+}
+
+// Post books the variance of every counted line as a stocktake movement in
+// one transaction. The variance is applied rather than overwriting the stock
+// with the count, so sales made while counting are kept.
+func (s *StocktakeService) Post(id uint, userID *uint) (models.Stocktake, error) {
+	err := s.store.Transaction(func(store repositories.Store) error {
+		stocktake, err := store.Stocktakes().FindForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if stocktake.Status != models.StocktakeStatusCounting {
+			return conflict("Stocktake %s is %s, only a stocktake being counted can be posted", stocktake.Code, stocktake.Status)
+		}
+
+		var ids []uint
+		for _, line := range stocktake.Lines {
+			if line.Counted() {
+				ids = append(ids, line.IngredientID)
+			}
+		}
+		if len(ids) == 0 {
+			return invalidInput("No quantities have been counted yet")
+		}
+
+		// Lock all ingredients up front in ID order to avoid deadlocks with sales
+		if err := store.Ingredients().Lock(ids); err != nil {
+			return err
+		}
+
+		for _, line := range stocktake.Lines {
+			if !line.Counted() || line.Variance() == 0 {
+				continue
+			}
+
+			ingredient, err := store.Ingredients().FindByID(line.IngredientID)
+			if err != nil {
+				return err
+			}
+
+			// Convert in case the ingredient's stock unit changed since counting started
+			quantity, err := utils.ConvertQuantity(line.Variance(), line.Unit, ingredient.Unit, ingredient.Conversions)
+			if err != nil {
+				return fmt.Errorf("ingredient %s: %w", ingredient.Name, err)
+			}
+
+			movement := models.StockMovement{
+				IngredientID:  ingredient.ID,
+				Type:          models.StockMovementStocktake,
+				Quantity:      quantity,
+				UserID:        userID,
+				ReferenceType: "stocktake_line",
+				ReferenceID:   line.ID,
+				Notes:         stocktake.Code,
+			}
+
+			if err := store.Ingredients().RecordMovement(&movement); err != nil {
+				return err
+			}
+
+			line.StockMovementID = &movement.ID
+			if err := store.Stocktakes().UpdateLine(&line); err != nil {
+				return err
+			}
+		}
+
+		now := s.now()
+		stocktake.Status = models.StocktakeStatusPosted
+		stocktake.PostedAt = &now
+		stocktake.PostedByID = userID
+		return store.Stocktakes().Update(&stocktake)
+	})
+	if err != nil {
+		return models.Stocktake{}, err
+	}
+
+	return s.Get(id)
+}
+
+// VarianceReport returns the stocktake for its variance report. Drafts have
+// no theoretical stock yet.
+func (s *StocktakeService) VarianceReport(id uint) (models.Stocktake, error) {
+	stocktake, err := s.Get(id)
+	if err != nil {
+		return stocktake, err
+	}
+	if stocktake.Status == models.StocktakeStatusDraft {
+		return stocktake, conflict("Stocktake %s has not been started", stocktake.Code)
+	}
+	return stocktake, nil
+}
+
+// Delete removes a stocktake that has not been posted
+func (s *StocktakeService) Delete(id uint) error {
+	return s.store.Transaction(func(store repositories.Store) error {
+		stocktake, err := store.Stocktakes().FindForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if stocktake.Status == models.StocktakeStatusPosted {
+			return conflict("Stocktake %s is posted and cannot be deleted", stocktake.Code)
+		}
+		return store.Stocktakes().Delete(&stocktake)
+	})
+}
+
+// checkAllFound returns a not found error for the first of ids missing from ingredients
+func checkAllFound(ids []uint, ingredients []models.Ingredient) error {
+	found := make(map[uint]bool, len(ingredients))
+	for _, ingredient := range ingredients {
+		found[ingredient.ID] = true
+	}
+
+	for _, id := range ids {
+		if !found[id] {
+			return fmt.Errorf("ingredient with ID %d %w", id, ErrNotFound)
+		}
+	}
+	return nil
+}
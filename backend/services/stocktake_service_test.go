@@ -0,0 +1,160 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+)
+
+// newTestStocktakeService returns a service on a fake store holding 10 kg of
+// rice and 5 kg of sugar
+func newTestStocktakeService(t *testing.T) (*StocktakeService, *fakeStore, models.Ingredient, models.Ingredient) {
+	t.Helper()
+
+	store := newFakeStore()
+	kilogram := store.addUnit(models.Unit{Name: "Kilogram", Symbol: "kg", Dimension: models.DimensionMass, Factor: 1000})
+
+	rice := store.addIngredient(models.Ingredient{Name: "Beras", Stock: 10, UnitID: kilogram.ID, UnitCost: 12000})
+	sugar := store.addIngredient(models.Ingredient{Name: "Gula", Stock: 5, UnitID: kilogram.ID, UnitCost: 15000})
+
+	codes, err := NewStocktakeCodeGenerator("")
+	if err != nil {
+		t.Fatalf("code generator: %v", err)
+	}
+
+	service := NewStocktakeService(store, codes)
+	service.now = func() time.Time {
+		return time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	}
+
+	return service, store, rice, sugar
+}
+
+func counted(quantity float64) *float64 {
+	return &quantity
+}
+
+func TestStocktakeServicePostBooksVariance(t *testing.T) {
+	service, store, rice, sugar := newTestStocktakeService(t)
+
+	stocktake, err := service.Create(dto.StocktakeCreateRequest{}, nil)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if stocktake.Code != "STK-20261017-01" {
+		t.Errorf("code = %s, want STK-20261017-01", stocktake.Code)
+	}
+	if len(stocktake.Lines) != 2 {
+		t.Fatalf("lines = %d, want 2", len(stocktake.Lines))
+	}
+
+	if _, err := service.Start(stocktake.ID); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	// Stock sold while counting must survive the posting
+	if err := store.Ingredients().RecordMovement(&models.StockMovement{IngredientID: rice.ID, Quantity: -1}); err != nil {
+		t.Fatalf("sale: %v", err)
+	}
+
+	stocktake, err = service.Count(stocktake.ID, []dto.StocktakeCountItem{
+		{IngredientID: rice.ID, CountedQuantity: counted(8)},
+		{IngredientID: sugar.ID, CountedQuantity: counted(5)},
+	})
+	if err != nil {
+		t.Fatalf("count: %v", err)
+	}
+
+	line := stocktake.Lines[0]
+	if line.IngredientID != rice.ID || line.Variance() != -2 || line.VarianceValue() != -24000 {
+		t.Errorf("rice line = %+v, want variance -2 valued -24000", line)
+	}
+	if percentage := line.VariancePercentage(); percentage == nil || *percentage != -20 {
+		t.Errorf("rice variance percentage = %v, want -20", percentage)
+	}
+
+	stocktake, err = service.Post(stocktake.ID, nil)
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	if stocktake.Status != models.StocktakeStatusPosted || stocktake.PostedAt == nil {
+		t.Errorf("stocktake = %s posted at %v, want posted", stocktake.Status, stocktake.PostedAt)
+	}
+
+	if got := store.data.ingredients[rice.ID].Stock; got != 7 {
+		t.Errorf("rice stock = %.2f, want 7", got)
+	}
+	if got := store.data.ingredients[sugar.ID].Stock; got != 5 {
+		t.Errorf("sugar stock = %.2f, want 5", got)
+	}
+
+	// One sale and one stocktake movement; sugar had no variance
+	movement := store.data.movements[len(store.data.movements)-1]
+	if len(store.data.movements) != 2 || movement.Type != models.StockMovementStocktake || movement.Quantity != -2 {
+		t.Errorf("movements = %+v, want a stocktake movement of -2", store.data.movements)
+	}
+	if stocktake.Lines[0].StockMovementID == nil || *stocktake.Lines[0].StockMovementID != movement.ID {
+		t.Errorf("rice line movement = %v, want %d", stocktake.Lines[0].StockMovementID, movement.ID)
+	}
+}
+
+func TestStocktakeServiceEnforcesStatus(t *testing.T) {
+	service, _, rice, _ := newTestStocktakeService(t)
+
+	stocktake, err := service.Create(dto.StocktakeCreateRequest{IngredientIDs: []uint{rice.ID}}, nil)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	count := []dto.StocktakeCountItem{{IngredientID: rice.ID, CountedQuantity: counted(9)}}
+	if _, err := service.Count(stocktake.ID, count); !errors.Is(err, ErrConflict) {
+		t.Errorf("count draft err = %v, want ErrConflict", err)
+	}
+	if _, err := service.VarianceReport(stocktake.ID); !errors.Is(err, ErrConflict) {
+		t.Errorf("report draft err = %v, want ErrConflict", err)
+	}
+
+	if _, err := service.Start(stocktake.ID); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if _, err := service.Start(stocktake.ID); !errors.Is(err, ErrConflict) {
+		t.Errorf("start twice err = %v, want ErrConflict", err)
+	}
+	if _, err := service.Post(stocktake.ID, nil); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("post uncounted err = %v, want ErrInvalidInput", err)
+	}
+
+	if _, err := service.Count(stocktake.ID, count); err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if _, err := service.Post(stocktake.ID, nil); err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	if err := service.Delete(stocktake.ID); !errors.Is(err, ErrConflict) {
+		t.Errorf("delete posted err = %v, want ErrConflict", err)
+	}
+}
+
+func TestStocktakeServiceValidatesIngredients(t *testing.T) {
+	service, _, rice, sugar := newTestStocktakeService(t)
+
+	if _, err := service.Create(dto.StocktakeCreateRequest{IngredientIDs: []uint{rice.ID, 99}}, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("create unknown ingredient err = %v, want ErrNotFound", err)
+	}
+
+	stocktake, err := service.Create(dto.StocktakeCreateRequest{IngredientIDs: []uint{rice.ID}}, nil)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := service.Start(stocktake.ID); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	_, err = service.Count(stocktake.ID, []dto.StocktakeCountItem{{IngredientID: sugar.ID, CountedQuantity: counted(1)}})
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("count other ingredient err = %v, want ErrInvalidInput", err)
+	}
+}
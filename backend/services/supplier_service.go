@@ -0,0 +1,194 @@
+package services
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+	"AwisPalace_IngredientManagement/utils"
+)
+
+type SupplierService struct {
+	store repositories.Store
+	now   func() time.Time
+}
+
+func NewSupplierService(store repositories.Store) *SupplierService {
+	return &SupplierService{
+		store: store,
+		now:   time.Now,
+	}
+}
+
+// SupplierOffer is the pack of an ingredient sold by a supplier
+type SupplierOffer struct {
+	models.SupplierIngredient
+	UnitPrice *float64 // Harga per unit stok ingredient, kosong jika belum ada harga
+}
+
+func (s *SupplierService) List(filter repositories.SupplierFilter, params utils.ListParams) ([]models.Supplier, dto.PageMeta, error) {
+	return s.store.Suppliers().List(filter, params)
+}
+
+func (s *SupplierService) Get(id uint) (models.Supplier, error) {
+	return s.store.Suppliers().FindByID(id)
+}
+
+func (s *SupplierService) Create(input dto.SupplierRequest) (models.Supplier, error) {
+	supplier := models.Supplier{}
+	applySupplierInput(&supplier, input)
+
+	if err := s.store.Suppliers().Create(&supplier); err != nil {
+		return supplier, err
+	}
+	return s.Get(supplier.ID)
+}
+
+func (s *SupplierService) Update(id uint, input dto.SupplierRequest) (models.Supplier, error) {
+	supplier, err := s.store.Suppliers().FindByID(id)
+	if err != nil {
+		return supplier, err
+	}
+
+	applySupplierInput(&supplier, input)
+	if err := s.store.Suppliers().Update(&supplier); err != nil {
+		return supplier, err
+	}
+	return s.Get(id)
+}
+
+func (s *SupplierService) Delete(id uint) error {
+	return s.store.Transaction(func(store repositories.Store) error {
+		supplier, err := store.Suppliers().FindByID(id)
+		if err != nil {
+			return err
+		}
+		return store.Suppliers().Delete(&supplier)
+	})
+}
+
+// SetIngredient links an ingredient to the supplier or updates the link. The
+// pack unit must be convertible to the ingredient's stock unit so offers can
+// be compared by unit price.
+func (s *SupplierService) SetIngredient(supplierID, ingredientID uint, input dto.SupplierIngredientRequest) (SupplierOffer, error) {
+	var offer SupplierOffer
+
+	err := s.store.Transaction(func(store repositories.Store) error {
+		if _, err := store.Suppliers().FindByID(supplierID); err != nil {
+			return err
+		}
+
+		ingredient, err := store.Ingredients().FindByID(ingredientID)
+		if err != nil {
+			return err
+		}
+
+		packUnit, err := store.Units().FindByID(input.PackUnitID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := utils.ConvertQuantity(input.PackSize, packUnit, ingredient.Unit, ingredient.Conversions); err != nil {
+			return fmt.Errorf("ingredient %s: %w", ingredient.Name, err)
+		}
+
+		link, err := store.Suppliers().FindIngredient(supplierID, ingredientID)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+
+		link.SupplierID = supplierID
+		link.IngredientID = ingredientID
+		link.SKU = input.SKU
+		link.PackSize = input.PackSize
+		link.PackUnitID = packUnit.ID
+		link.PackUnit = packUnit
+		if link.ID == 0 || link.LastPrice != input.LastPrice {
+			now := s.now()
+			link.LastPrice = input.LastPrice
+			link.LastPriceAt = &now
+		}
+
+		if err := store.Suppliers().SaveIngredient(&link); err != nil {
+			return err
+		}
+
+		link.Ingredient = ingredient
+		offer = NewSupplierOffer(link, ingredient)
+		return nil
+	})
+
+	return offer, err
+}
+
+func (s *SupplierService) RemoveIngredient(supplierID, ingredientID uint) error {
+	return s.store.Transaction(func(store repositories.Store) error {
+		link, err := store.Suppliers().FindIngredient(supplierID, ingredientID)
+		if err != nil {
+			return err
+		}
+		return store.Suppliers().DeleteIngredient(&link)
+	})
+}
+
+// IngredientOffers returns the suppliers of an ingredient, cheapest per
+// stock unit first. Offers without a price come last.
+func (s *SupplierService) IngredientOffers(ingredient models.Ingredient) ([]SupplierOffer, error) {
+	links, err := s.store.Suppliers().ListByIngredient(ingredient.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	offers := make([]SupplierOffer, 0, len(links))
+	for _, link := range links {
+		offers = append(offers, NewSupplierOffer(link, ingredient))
+	}
+
+	slices.SortStableFunc(offers, func(a, b SupplierOffer) int {
+		switch {
+		case a.UnitPrice == nil && b.UnitPrice == nil:
+			return 0
+		case a.UnitPrice == nil:
+			return 1
+		case b.UnitPrice == nil:
+			return -1
+		}
+		return cmp.Compare(*a.UnitPrice, *b.UnitPrice)
+	})
+
+	return offers, nil
+}
+
+// NewSupplierOffer prices the pack of link per stock unit of ingredient.
+// ingredient must be loaded with its unit and conversions.
+func NewSupplierOffer(link models.SupplierIngredient, ingredient models.Ingredient) SupplierOffer {
+	offer := SupplierOffer{SupplierIngredient: link}
+	if link.LastPrice <= 0 {
+		return offer
+	}
+
+	quantity, err := utils.ConvertQuantity(link.PackSize, link.PackUnit, ingredient.Unit, ingredient.Conversions)
+	if err != nil || quantity <= 0 {
+		return offer
+	}
+
+	unitPrice := link.LastPrice / quantity
+	offer.UnitPrice = &unitPrice
+	return offer
+}
+
+func applySupplierInput(supplier *models.Supplier, input dto.SupplierRequest) {
+	supplier.Name = input.Name
+	supplier.ContactName = input.ContactName
+	supplier.Phone = input.Phone
+	supplier.Email = input.Email
+	supplier.Address = input.Address
+	supplier.LeadTimeDays = input.LeadTimeDays
+	supplier.PaymentTerms = input.PaymentTerms
+	supplier.Notes = input.Notes
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/utils"
+)
+
+func TestSupplierServiceIngredientOffers(t *testing.T) {
+	store := newFakeStore()
+	gram := store.addUnit(models.Unit{Name: "Gram", Symbol: "g", Dimension: models.DimensionMass, Factor: 1})
+	kilogram := store.addUnit(models.Unit{Name: "Kilogram", Symbol: "kg", Dimension: models.DimensionMass, Factor: 1000})
+	liter := store.addUnit(models.Unit{Name: "Liter", Symbol: "l", Dimension: models.DimensionVolume, Factor: 1000})
+	flour := store.addIngredient(models.Ingredient{Name: "Tepung", UnitID: kilogram.ID})
+
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.Local)
+	service := NewSupplierService(store)
+	service.now = func() time.Time { return now }
+
+	create := func(name string) models.Supplier {
+		supplier, err := service.Create(dto.SupplierRequest{Name: name, LeadTimeDays: 2})
+		if err != nil {
+			t.Fatalf("create supplier %s: %v", name, err)
+		}
+		return supplier
+	}
+	bulk := create("Toko Grosir")
+	local := create("Pasar Lokal")
+	unpriced := create("Supplier Baru")
+
+	// 25 kg seharga 250.000 = 10.000/kg, 500 g seharga 6.000 = 12.000/kg
+	if _, err := service.SetIngredient(local.ID, flour.ID, dto.SupplierIngredientRequest{PackSize: 500, PackUnitID: gram.ID, LastPrice: 6000}); err != nil {
+		t.Fatalf("set local ingredient: %v", err)
+	}
+	if _, err := service.SetIngredient(unpriced.ID, flour.ID, dto.SupplierIngredientRequest{PackSize: 1, PackUnitID: kilogram.ID}); err != nil {
+		t.Fatalf("set unpriced ingredient: %v", err)
+	}
+	offer, err := service.SetIngredient(bulk.ID, flour.ID, dto.SupplierIngredientRequest{SKU: "TPG-25", PackSize: 25, PackUnitID: kilogram.ID, LastPrice: 250000})
+	if err != nil {
+		t.Fatalf("set bulk ingredient: %v", err)
+	}
+	if offer.UnitPrice == nil || *offer.UnitPrice != 10000 {
+		t.Errorf("bulk unit price = %v, want 10000", offer.UnitPrice)
+	}
+	if offer.LastPriceAt == nil || !offer.LastPriceAt.Equal(now) {
+		t.Errorf("last price at = %v, want %v", offer.LastPriceAt, now)
+	}
+
+	_, offers, err := NewIngredientService(store, service).Get(flour.ID)
+	if err != nil {
+		t.Fatalf("get ingredient: %v", err)
+	}
+	if len(offers) != 3 {
+		t.Fatalf("offers = %d, want 3", len(offers))
+	}
+	want := []uint{bulk.ID, local.ID, unpriced.ID}
+	for i, offer := range offers {
+		if offer.SupplierID != want[i] {
+			t.Errorf("offer %d supplier = %d, want %d", i, offer.SupplierID, want[i])
+		}
+	}
+	if offers[2].UnitPrice != nil {
+		t.Errorf("unpriced offer unit price = %v, want nil", *offers[2].UnitPrice)
+	}
+
+	// Harga yang sama tidak mengubah tanggal harga terakhir
+	later := now.Add(24 * time.Hour)
+	service.now = func() time.Time { return later }
+	offer, err = service.SetIngredient(bulk.ID, flour.ID, dto.SupplierIngredientRequest{SKU: "TPG-25B", PackSize: 25, PackUnitID: kilogram.ID, LastPrice: 250000})
+	if err != nil {
+		t.Fatalf("update bulk ingredient: %v", err)
+	}
+	if !offer.LastPriceAt.Equal(now) || offer.SKU != "TPG-25B" {
+		t.Errorf("updated offer = %+v, want SKU TPG-25B priced at %v", offer, now)
+	}
+
+	_, err = service.SetIngredient(bulk.ID, flour.ID, dto.SupplierIngredientRequest{PackSize: 1, PackUnitID: liter.ID, LastPrice: 1000})
+	if !errors.Is(err, utils.ErrIncompatibleUnit) {
+		t.Errorf("incompatible pack unit err = %v, want ErrIncompatibleUnit", err)
+	}
+
+	if err := service.Delete(local.ID); err != nil {
+		t.Fatalf("delete supplier: %v", err)
+	}
+	offers, err = service.IngredientOffers(flour)
+	if err != nil {
+		t.Fatalf("offers after delete: %v", err)
+	}
+	if len(offers) != 2 {
+		t.Errorf("offers after delete = %d, want 2", len(offers))
+	}
+
+	if err := service.RemoveIngredient(local.ID, flour.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("remove deleted link err = %v, want ErrNotFound", err)
+	}
+	if _, err := service.Get(999); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unknown supplier err = %v, want ErrNotFound", err)
+	}
+}
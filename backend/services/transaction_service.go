@@ -0,0 +1,649 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+	"AwisPalace_IngredientManagement/utils"
+)
+
+type TransactionService struct {
+	store repositories.Store
+	codes *utils.CodeGenerator
+	now   func() time.Time
+}
+
+func NewTransactionService(store repositories.Store, codes *utils.CodeGenerator) *TransactionService {
+	return &TransactionService{
+		store: store,
+		codes: codes,
+		now:   time.Now,
+	}
+}
+
+// NewTransactionCodeGenerator builds the transaction code generator, format
+// defaults to utils.DefaultTransactionCodeFormat
+func NewTransactionCodeGenerator(format, outlet string) (*utils.CodeGenerator, error) {
+	if format == "" {
+		format = utils.DefaultTransactionCodeFormat
+	}
+	return utils.NewCodeGenerator("transaction", format, outlet)
+}
+
+func (s *TransactionService) List(filter repositories.TransactionFilter, params utils.ListParams) ([]models.Transaction, dto.PageMeta, error) {
+	return s.store.Transactions().List(filter, params)
+}
+
+func (s *TransactionService) Get(id uint) (models.Transaction, error) {
+	return s.store.Transactions().FindByID(id)
+}
+
+// Create records the sale and deducts the recipe ingredients of every item,
+// adjusted by its modifiers, from stock. Nothing is stored when any ingredient runs out.
+func (s *TransactionService) Create(input dto.TransactionCreateRequest, userID *uint) (models.Transaction, error) {
+	var transaction models.Transaction
+
+	// Seluruh transaksi diulang jika database membatalkannya karena deadlock/serialization failure
+	err := s.store.Transaction(func(store repositories.Store) error {
+		var err error
+		transaction, err = s.create(store, input, userID)
+		return err
+	})
+
+	return transaction, err
+}
+
+func (s *TransactionService) create(store repositories.Store, input dto.TransactionCreateRequest, userID *uint) (models.Transaction, error) {
+	transaction := models.Transaction{
+		TransactionDate: s.now(),
+		TotalAmount:     0,
+		Notes:           input.Notes,
+		Status:          models.TransactionStatusCompleted,
+	}
+
+	if len(input.Items) == 0 {
+		return transaction, invalidInput("at least one item is required")
+	}
+
+	// Get menu data with the selected modifiers
+	orders := make([]orderedItem, len(input.Items))
+	var ingredientIDs []uint
+	for i, item := range input.Items {
+		if item.Quantity <= 0 {
+			return transaction, invalidInput("quantity of menu %d must be positive", item.MenuID)
+		}
+
+		menu, err := store.Menus().FindByID(item.MenuID)
+		if err != nil {
+			return transaction, err
+		}
+		if menu.SoldOut {
+			return transaction, conflict("menu %s is sold out", menu.Name)
+		}
+
+		orders[i], err = orderItem(menu, item.ModifierOptionIDs)
+		if err != nil {
+			return transaction, err
+		}
+
+		for _, line := range orders[i].recipe() {
+			ingredientIDs = append(ingredientIDs, line.IngredientID)
+		}
+	}
+
+	// Lock all ingredients up front in ID order to avoid deadlocks between concurrent sales
+	if err := store.Ingredients().Lock(ingredientIDs); err != nil {
+		return transaction, err
+	}
+
+	// Generate transaction code from the daily sequence
+	transactionCode, err := s.codes.Next(transaction.TransactionDate, store.Sequences().Next)
+	if err != nil {
+		return transaction, err
+	}
+	transaction.TransactionCode = transactionCode
+
+	if err := store.Transactions().Create(&transaction); err != nil {
+		return transaction, err
+	}
+
+	for i, item := range input.Items {
+		order := orders[i]
+
+		// Create transaction item, the price per portion includes the modifiers
+		transactionItem := models.TransactionItem{
+			TransactionID: transaction.ID,
+			MenuID:        order.Menu.ID,
+			Quantity:      item.Quantity,
+			Price:         order.Price,
+			Modifiers:     order.Modifiers,
+		}
+
+		if err := store.Transactions().CreateItem(&transactionItem); err != nil {
+			return transaction, err
+		}
+
+		transaction.TotalAmount += order.Price * float64(item.Quantity)
+
+		itemCost, err := deductRecipe(store, transaction, transactionItem, order.recipe(), userID)
+		if err != nil {
+			return transaction, err
+		}
+
+		// Snapshot cost per portion for historical margins
+		transactionItem.Cost = itemCost / float64(item.Quantity)
+		if err := store.Transactions().UpdateItemCost(transactionItem.ID, transactionItem.Cost); err != nil {
+			return transaction, err
+		}
+	}
+
+	// Update total amount
+	if err := store.Transactions().UpdateTotal(transaction.ID, transaction.TotalAmount); err != nil {
+		return transaction, err
+	}
+
+	return transaction, nil
+}
+
+// Cancel voids a completed transaction, keeps its record and restores the
+// stock of all its items
+func (s *TransactionService) Cancel(id uint, reason string, userID *uint) (models.Transaction, error) {
+	var transaction models.Transaction
+
+	err := s.store.Transaction(func(store repositories.Store) error {
+		var err error
+		transaction, err = store.Transactions().FindForUpdate(id)
+		if err != nil {
+			return err
+		}
+
+		if transaction.Status != models.TransactionStatusCompleted {
+			return conflict("Cannot cancel a transaction with status %s", transaction.Status)
+		}
+
+		if err := store.Ingredients().Lock(transactionIngredientIDs(transaction)); err != nil {
+			return err
+		}
+
+		for _, item := range transaction.TransactionItems {
+			if err := restoreItemStock(store, item, item.Quantity, "transaction_item", item.ID, transaction.TransactionCode, userID); err != nil {
+				return err
+			}
+		}
+
+		cancelledAt := s.now()
+		transaction.Status = models.TransactionStatusCancelled
+		transaction.CancelReason = reason
+		transaction.CancelledAt = &cancelledAt
+		transaction.CancelledByID = userID
+		if err := store.Transactions().UpdateStatus(&transaction); err != nil {
+			return err
+		}
+
+		transaction, err = store.Transactions().FindByID(id)
+		return err
+	})
+
+	return transaction, err
+}
+
+// Refund refunds quantities of the items of a transaction. Stock is only
+// restored for items marked restock, the others were already prepared.
+func (s *TransactionService) Refund(id uint, input dto.TransactionRefundRequest, userID *uint) (models.Transaction, error) {
+	var transaction models.Transaction
+
+	err := s.store.Transaction(func(store repositories.Store) error {
+		var err error
+		transaction, err = store.Transactions().FindForUpdate(id)
+		if err != nil {
+			return err
+		}
+
+		if transaction.Status != models.TransactionStatusCompleted &&
+			transaction.Status != models.TransactionStatusPartiallyRefunded {
+			return conflict("Cannot refund a transaction with status %s", transaction.Status)
+		}
+
+		if err := store.Ingredients().Lock(transactionIngredientIDs(transaction)); err != nil {
+			return err
+		}
+
+		items := make(map[uint]*models.TransactionItem, len(transaction.TransactionItems))
+		for i := range transaction.TransactionItems {
+			items[transaction.TransactionItems[i].ID] = &transaction.TransactionItems[i]
+		}
+
+		refund := models.Refund{
+			TransactionID: transaction.ID,
+			Reason:        input.Reason,
+			UserID:        userID,
+		}
+
+		// Item yang sama boleh muncul lebih dari sekali, jumlahnya diakumulasi
+		refunded := map[uint]int{}
+		for _, itemReq := range input.Items {
+			item, ok := items[itemReq.TransactionItemID]
+			if !ok {
+				return invalidInput("Item with ID %d does not belong to transaction %s", itemReq.TransactionItemID, transaction.TransactionCode)
+			}
+
+			refundable := item.Quantity - item.RefundedQuantity - refunded[item.ID]
+			if itemReq.Quantity <= 0 || itemReq.Quantity > refundable {
+				return invalidInput("Refund quantity for item %d exceeds refundable quantity. Refundable: %d, Requested: %d", item.ID, refundable, itemReq.Quantity)
+			}
+			refunded[item.ID] += itemReq.Quantity
+
+			refund.Items = append(refund.Items, models.RefundItem{
+				TransactionItemID: item.ID,
+				Quantity:          itemReq.Quantity,
+				Amount:            item.Price * float64(itemReq.Quantity),
+				Restocked:         itemReq.Restock,
+			})
+			refund.TotalAmount += item.Price * float64(itemReq.Quantity)
+		}
+
+		if err := store.Transactions().CreateRefund(&refund); err != nil {
+			return err
+		}
+
+		for _, refundItem := range refund.Items {
+			item := items[refundItem.TransactionItemID]
+
+			// Bahan yang sudah terpakai (contoh: komplain rasa) tidak dikembalikan ke stok
+			if refundItem.Restocked {
+				if err := restoreItemStock(store, *item, refundItem.Quantity, "refund_item", refundItem.ID, transaction.TransactionCode, userID); err != nil {
+					return err
+				}
+			}
+
+			item.RefundedQuantity += refundItem.Quantity
+			if err := store.Transactions().UpdateRefundedQuantity(item.ID, item.RefundedQuantity); err != nil {
+				return err
+			}
+		}
+
+		transaction.Status = models.TransactionStatusRefunded
+		for _, item := range transaction.TransactionItems {
+			if item.RefundedQuantity < item.Quantity {
+				transaction.Status = models.TransactionStatusPartiallyRefunded
+				break
+			}
+		}
+		transaction.RefundedAmount += refund.TotalAmount
+		if err := store.Transactions().UpdateStatus(&transaction); err != nil {
+			return err
+		}
+
+		transaction, err = store.Transactions().FindByID(id)
+		return err
+	})
+
+	return transaction, err
+}
+
+// Delete removes a completed transaction and restores the stock of its
+// items. Cancel or Refund keep the record and are preferred.
+func (s *TransactionService) Delete(id uint, userID *uint) (models.Transaction, error) {
+	var transaction models.Transaction
+
+	err := s.store.Transaction(func(store repositories.Store) error {
+		var err error
+		transaction, err = store.Transactions().FindForUpdate(id)
+		if err != nil {
+			return err
+		}
+
+		// Transaksi yang sudah dibatalkan/direfund sudah mengembalikan sebagian stok
+		if transaction.Status != models.TransactionStatusCompleted {
+			return conflict("Cannot delete a transaction with status %s", transaction.Status)
+		}
+
+		if err := store.Ingredients().Lock(transactionIngredientIDs(transaction)); err != nil {
+			return err
+		}
+
+		for _, item := range transaction.TransactionItems {
+			if err := restoreItemStock(store, item, item.Quantity, "transaction_item", item.ID, transaction.TransactionCode, userID); err != nil {
+				return err
+			}
+		}
+
+		return store.Transactions().Delete(&transaction)
+	})
+
+	return transaction, err
+}
+
+// TransactionPreview is what a transaction would sell and take from stock
+type TransactionPreview struct {
+	Items        []TransactionPreviewItem
+	Requirements []IngredientRequirement // Urut sesuai kemunculan pertama di pesanan
+	TotalAmount  float64
+	Cost         float64
+	Feasible     bool // Semua menu tersedia dan stok mencukupi
+}
+
+type TransactionPreviewItem struct {
+	Menu      models.Menu
+	Quantity  int
+	Price     float64 // Harga per porsi termasuk modifier
+	Subtotal  float64
+	Modifiers []models.TransactionItemModifier
+}
+
+// IngredientRequirement is the quantity of an ingredient needed by all items
+// of an order, in the ingredient's stock unit
+type IngredientRequirement struct {
+	Ingredient models.Ingredient
+	Required   float64
+	Shortage   float64 // Kekurangan stok, 0 jika cukup
+}
+
+// Preview computes the total of input and the ingredients it needs,
+// aggregated over all items, against the current stock. Nothing is written,
+// so the stock may still change before the transaction is created.
+func (s *TransactionService) Preview(input dto.TransactionCreateRequest) (TransactionPreview, error) {
+	preview := TransactionPreview{Feasible: true}
+
+	if len(input.Items) == 0 {
+		return preview, invalidInput("at least one item is required")
+	}
+
+	requirements := map[uint]int{} // ingredient ID -> index di preview.Requirements
+	for _, item := range input.Items {
+		if item.Quantity <= 0 {
+			return preview, invalidInput("quantity of menu %d must be positive", item.MenuID)
+		}
+
+		menu, err := s.store.Menus().FindByID(item.MenuID)
+		if err != nil {
+			return preview, err
+		}
+		if menu.SoldOut {
+			preview.Feasible = false
+		}
+
+		order, err := orderItem(menu, item.ModifierOptionIDs)
+		if err != nil {
+			return preview, err
+		}
+
+		subtotal := order.Price * float64(item.Quantity)
+		preview.Items = append(preview.Items, TransactionPreviewItem{
+			Menu:      menu,
+			Quantity:  item.Quantity,
+			Price:     order.Price,
+			Subtotal:  subtotal,
+			Modifiers: order.Modifiers,
+		})
+		preview.TotalAmount += subtotal
+
+		quantities, err := recipeQuantities(s.store, order.recipe(), item.Quantity)
+		if err != nil {
+			return preview, err
+		}
+
+		for _, quantity := range quantities {
+			i, ok := requirements[quantity.Ingredient.ID]
+			if !ok {
+				i = len(preview.Requirements)
+				requirements[quantity.Ingredient.ID] = i
+				preview.Requirements = append(preview.Requirements, IngredientRequirement{Ingredient: quantity.Ingredient})
+			}
+
+			preview.Requirements[i].Required += quantity.Quantity
+			preview.Cost += quantity.Quantity * quantity.Ingredient.UnitCost
+		}
+	}
+
+	for i := range preview.Requirements {
+		requirement := &preview.Requirements[i]
+		if shortage := requirement.Required - requirement.Ingredient.Stock; shortage > 1e-9 {
+			requirement.Shortage = shortage
+			preview.Feasible = false
+		}
+	}
+
+	return preview, nil
+}
+
+// orderedItem is a menu with the modifier options selected for it
+type orderedItem struct {
+	Menu      models.Menu
+	Options   []models.MenuModifierOption
+	Modifiers []models.TransactionItemModifier // Snapshot pilihan untuk item transaksi
+	Price     float64                          // Harga per porsi termasuk modifier
+}
+
+// orderItem checks optionIDs against the modifier groups of menu. Every option
+// must belong to the menu, be chosen once, and every group must get between
+// MinSelect and MaxSelect options.
+func orderItem(menu models.Menu, optionIDs []uint) (orderedItem, error) {
+	order := orderedItem{Menu: menu, Price: menu.Price}
+
+	groups := map[uint]models.MenuModifierGroup{} // option ID -> kelompoknya
+	options := map[uint]models.MenuModifierOption{}
+	for _, group := range menu.ModifierGroups {
+		for _, option := range group.Options {
+			groups[option.ID] = group
+			options[option.ID] = option
+		}
+	}
+
+	selected := map[uint]int{} // group ID -> jumlah pilihan
+	for _, optionID := range optionIDs {
+		option, ok := options[optionID]
+		if !ok {
+			return order, invalidInput("modifier option %d is not available for menu %s", optionID, menu.Name)
+		}
+		if slices.ContainsFunc(order.Options, func(chosen models.MenuModifierOption) bool { return chosen.ID == optionID }) {
+			return order, invalidInput("modifier %s is selected more than once for menu %s", option.Name, menu.Name)
+		}
+
+		group := groups[optionID]
+		selected[group.ID]++
+		order.Options = append(order.Options, option)
+		order.Modifiers = append(order.Modifiers, models.TransactionItemModifier{
+			ModifierOptionID: option.ID,
+			GroupName:        group.Name,
+			OptionName:       option.Name,
+			PriceDelta:       option.PriceDelta,
+		})
+		order.Price += option.PriceDelta
+	}
+
+	for _, group := range menu.ModifierGroups {
+		count := selected[group.ID]
+		if count < group.MinSelect {
+			return order, invalidInput("select at least %d %s for menu %s", group.MinSelect, group.Name, menu.Name)
+		}
+		if group.MaxSelect > 0 && count > group.MaxSelect {
+			return order, invalidInput("select at most %d %s for menu %s", group.MaxSelect, group.Name, menu.Name)
+		}
+	}
+
+	if order.Price < 0 {
+		return order, invalidInput("price of menu %s with its modifiers cannot be negative", menu.Name)
+	}
+
+	return order, nil
+}
+
+// recipe returns the recipe lines of one portion: the menu recipe followed by
+// the ingredient changes of the selected options
+func (o orderedItem) recipe() []models.MenuIngredient {
+	recipe := slices.Clone(o.Menu.MenuIngredients)
+	for _, option := range o.Options {
+		for _, change := range option.Ingredients {
+			recipe = append(recipe, models.MenuIngredient{
+				IngredientID: change.IngredientID,
+				Quantity:     change.Quantity,
+				UnitID:       change.UnitID,
+				Unit:         change.Unit,
+			})
+		}
+	}
+	return recipe
+}
+
+// recipeQuantity is the quantity of an ingredient in its stock unit
+type recipeQuantity struct {
+	Ingredient models.Ingredient
+	Quantity   float64
+}
+
+// recipeQuantities converts portions of recipe into the stock unit of each
+// ingredient, summed per ingredient in the order they first appear. An
+// ingredient removed entirely by a modifier is left out.
+func recipeQuantities(store repositories.Store, recipe []models.MenuIngredient, portions int) ([]recipeQuantity, error) {
+	var quantities []recipeQuantity
+	indexes := map[uint]int{} // ingredient ID -> index di quantities
+
+	for _, line := range recipe {
+		i, ok := indexes[line.IngredientID]
+		if !ok {
+			ingredient, err := store.Ingredients().FindByID(line.IngredientID)
+			if err != nil {
+				return nil, err
+			}
+
+			i = len(quantities)
+			indexes[ingredient.ID] = i
+			quantities = append(quantities, recipeQuantity{Ingredient: ingredient})
+		}
+		quantity := &quantities[i]
+
+		converted, err := utils.ConvertQuantity(
+			line.Quantity*float64(portions),
+			line.Unit,
+			quantity.Ingredient.Unit,
+			quantity.Ingredient.Conversions,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("ingredient %s: %w", quantity.Ingredient.Name, err)
+		}
+		quantity.Quantity += converted
+	}
+
+	return slices.DeleteFunc(quantities, func(quantity recipeQuantity) bool {
+		return quantity.Quantity <= 1e-9
+	}), nil
+}
+
+// deductRecipe takes the ingredients of recipe for item from stock and
+// returns their cost
+func deductRecipe(store repositories.Store, transaction models.Transaction, item models.TransactionItem, recipe []models.MenuIngredient, userID *uint) (float64, error) {
+	var cost float64
+
+	quantities, err := recipeQuantities(store, recipe, item.Quantity)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, quantity := range quantities {
+		ingredient := quantity.Ingredient
+		quantityToReduce := quantity.Quantity
+
+		// Record stock movement in the ledger, this also checks the stock is sufficient
+		movement := models.StockMovement{
+			IngredientID:  ingredient.ID,
+			Type:          models.StockMovementSale,
+			Quantity:      -quantityToReduce,
+			UserID:        userID,
+			ReferenceType: "transaction_item",
+			ReferenceID:   item.ID,
+			Notes:         transaction.TransactionCode,
+		}
+
+		if err := store.Ingredients().RecordMovement(&movement); err != nil {
+			return 0, err
+		}
+
+		// Create stock reduction record
+		stockReduction := models.StockReduction{
+			TransactionItemID: item.ID,
+			IngredientID:      ingredient.ID,
+			QuantityReduced:   quantityToReduce,
+			StockBefore:       movement.StockBefore,
+			StockAfter:        movement.StockAfter,
+			UnitID:            ingredient.UnitID,
+		}
+
+		// Lot yang terpakai (FEFO) ikut disimpan bersama pengurangan stok
+		for _, lot := range movement.Lots {
+			stockReduction.Lots = append(stockReduction.Lots, models.StockReductionLot{
+				StockLotID: lot.StockLotID,
+				Quantity:   lot.Quantity,
+			})
+		}
+
+		if err := store.Transactions().CreateStockReduction(&stockReduction); err != nil {
+			return 0, err
+		}
+
+		cost += quantityToReduce * ingredient.UnitCost
+	}
+
+	return cost, nil
+}
+
+// restoreItemStock returns the ingredients of quantity portions of item to
+// stock as sale reversal movements and to the lots they were drawn from.
+// item must be loaded with StockReductions.Unit and StockReductions.Lots.
+func restoreItemStock(store repositories.Store, item models.TransactionItem, quantity int, referenceType string, referenceID uint, notes string, userID *uint) error {
+	if item.Quantity == 0 {
+		return nil
+	}
+
+	for _, reduction := range item.StockReductions {
+		ingredient, err := store.Ingredients().FindByID(reduction.IngredientID)
+		if err != nil {
+			return err
+		}
+
+		// Reduction dicatat untuk seluruh quantity item, ambil porsinya saja
+		reduced := reduction.QuantityReduced * float64(quantity) / float64(item.Quantity)
+
+		// Convert back in case the ingredient's stock unit changed since the sale
+		quantityToRestore, err := utils.ConvertQuantity(reduced, reduction.Unit, ingredient.Unit, ingredient.Conversions)
+		if err != nil {
+			return fmt.Errorf("ingredient %s: %w", ingredient.Name, err)
+		}
+
+		movement := models.StockMovement{
+			IngredientID:  ingredient.ID,
+			Type:          models.StockMovementSaleReversal,
+			Quantity:      quantityToRestore,
+			UserID:        userID,
+			ReferenceType: referenceType,
+			ReferenceID:   referenceID,
+			Notes:         notes,
+		}
+
+		if err := store.Ingredients().RecordMovement(&movement); err != nil {
+			return err
+		}
+
+		if err := store.StockLots().Return(reduction.Lots, float64(quantity)/float64(item.Quantity)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// transactionIngredientIDs returns the ingredients deducted by transaction.
+// TransactionItems must be loaded with StockReductions.
+func transactionIngredientIDs(transaction models.Transaction) []uint {
+	var ids []uint
+	for _, item := range transaction.TransactionItems {
+		for _, reduction := range item.StockReductions {
+			ids = append(ids, reduction.IngredientID)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,385 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/utils"
+)
+
+// newTestTransactionService returns a service on a fake store holding rice in
+// kilograms and a menu using 200 g of it per portion
+func newTestTransactionService(t *testing.T, stock float64) (*TransactionService, *fakeStore, models.Ingredient, models.Menu) {
+	t.Helper()
+
+	store := newFakeStore()
+	kilogram := store.addUnit(models.Unit{Name: "Kilogram", Symbol: "kg", Dimension: models.DimensionMass, Factor: 1000})
+	gram := store.addUnit(models.Unit{Name: "Gram", Symbol: "g", Dimension: models.DimensionMass, Factor: 1})
+
+	rice := store.addIngredient(models.Ingredient{Name: "Beras", Stock: stock, UnitID: kilogram.ID, UnitCost: 12000})
+	menu := store.addMenu(models.Menu{
+		Name:  "Nasi",
+		Price: 5000,
+		MenuIngredients: []models.MenuIngredient{
+			{IngredientID: rice.ID, Quantity: 200, UnitID: gram.ID},
+		},
+	})
+
+	codes, err := NewTransactionCodeGenerator("", "")
+	if err != nil {
+		t.Fatalf("code generator: %v", err)
+	}
+
+	service := NewTransactionService(store, codes)
+	service.now = func() time.Time {
+		return time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	}
+
+	return service, store, rice, menu
+}
+
+func TestTransactionServiceCreateDeductsRecipeStock(t *testing.T) {
+	service, store, rice, menu := newTestTransactionService(t, 2)
+
+	transaction, err := service.Create(dto.TransactionCreateRequest{
+		Items: []dto.TransactionItemRequest{{MenuID: menu.ID, Quantity: 3}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	if transaction.TransactionCode != "TRX-20261017-0001" {
+		t.Errorf("code = %s, want TRX-20261017-0001", transaction.TransactionCode)
+	}
+	if transaction.TotalAmount != 15000 {
+		t.Errorf("total = %.2f, want 15000", transaction.TotalAmount)
+	}
+
+	// 3 porsi x 200 g = 0.6 kg
+	if got := store.data.ingredients[rice.ID].Stock; got != 1.4 {
+		t.Errorf("stock = %.2f, want 1.4", got)
+	}
+
+	if len(store.data.movements) != 1 {
+		t.Fatalf("got %d movements, want 1", len(store.data.movements))
+	}
+	movement := store.data.movements[0]
+	if movement.Type != models.StockMovementSale || movement.Quantity != -0.6 || movement.StockBefore != 2 {
+		t.Errorf("movement = %+v", movement)
+	}
+
+	if len(store.data.reductions) != 1 || store.data.reductions[0].QuantityReduced != 0.6 {
+		t.Errorf("reductions = %+v", store.data.reductions)
+	}
+
+	// Harga pokok per porsi: 0.2 kg x 12000
+	if len(store.data.items) != 1 || store.data.items[0].Cost != 2400 {
+		t.Errorf("items = %+v", store.data.items)
+	}
+}
+
+func TestTransactionServiceCreateRejectsInsufficientStock(t *testing.T) {
+	service, store, rice, menu := newTestTransactionService(t, 0.5)
+
+	_, err := service.Create(dto.TransactionCreateRequest{
+		Items: []dto.TransactionItemRequest{{MenuID: menu.ID, Quantity: 3}},
+	}, nil)
+	if !errors.Is(err, utils.ErrInsufficientStock) {
+		t.Fatalf("err = %v, want ErrInsufficientStock", err)
+	}
+
+	if got := store.data.ingredients[rice.ID].Stock; got != 0.5 {
+		t.Errorf("stock = %.2f, want unchanged 0.5", got)
+	}
+	if len(store.data.transactions) != 0 || len(store.data.movements) != 0 {
+		t.Errorf("rejected sale left %d transactions and %d movements", len(store.data.transactions), len(store.data.movements))
+	}
+
+	// Nomor urut yang dipakai transaksi gagal ikut dibatalkan
+	transaction, err := service.Create(dto.TransactionCreateRequest{
+		Items: []dto.TransactionItemRequest{{MenuID: menu.ID, Quantity: 1}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if transaction.TransactionCode != "TRX-20261017-0001" {
+		t.Errorf("code = %s, want TRX-20261017-0001", transaction.TransactionCode)
+	}
+}
+
+func TestTransactionServiceCreateDeductsNothingWhenALaterItemFails(t *testing.T) {
+	service, store, rice, menu := newTestTransactionService(t, 1)
+
+	_, err := service.Create(dto.TransactionCreateRequest{
+		Items: []dto.TransactionItemRequest{
+			{MenuID: menu.ID, Quantity: 1},
+			{MenuID: 999, Quantity: 1},
+		},
+	}, nil)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+
+	if got := store.data.ingredients[rice.ID].Stock; got != 1 {
+		t.Errorf("stock = %.2f, want unchanged 1", got)
+	}
+}
+
+func TestTransactionServiceCreateRejectsIncompatibleRecipeUnit(t *testing.T) {
+	service, store, rice, _ := newTestTransactionService(t, 5)
+
+	liter := store.addUnit(models.Unit{Name: "Liter", Symbol: "l", Dimension: models.DimensionVolume, Factor: 1000})
+	menu := store.addMenu(models.Menu{
+		Name:  "Bubur",
+		Price: 8000,
+		MenuIngredients: []models.MenuIngredient{
+			{IngredientID: rice.ID, Quantity: 1, UnitID: liter.ID},
+		},
+	})
+
+	_, err := service.Create(dto.TransactionCreateRequest{
+		Items: []dto.TransactionItemRequest{{MenuID: menu.ID, Quantity: 1}},
+	}, nil)
+	if !errors.Is(err, utils.ErrIncompatibleUnit) {
+		t.Fatalf("err = %v, want ErrIncompatibleUnit", err)
+	}
+}
+
+func TestTransactionServiceCreateRejectsEmptyOrder(t *testing.T) {
+	service, _, _, menu := newTestTransactionService(t, 5)
+
+	for name, items := range map[string][]dto.TransactionItemRequest{
+		"no items":      nil,
+		"zero quantity": {{MenuID: menu.ID, Quantity: 0}},
+	} {
+		_, err := service.Create(dto.TransactionCreateRequest{Items: items}, nil)
+		if !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("%s: err = %v, want ErrInvalidInput", name, err)
+		}
+	}
+}
+
+func TestTransactionServicePreviewAggregatesIngredients(t *testing.T) {
+	service, store, rice, menu := newTestTransactionService(t, 1)
+
+	gram := store.data.menus[menu.ID].MenuIngredients[0].Unit
+	egg := store.addIngredient(models.Ingredient{Name: "Telur", Stock: 10, UnitID: gram.ID, UnitCost: 2})
+	friedRice := store.addMenu(models.Menu{
+		Name:  "Nasi Goreng",
+		Price: 15000,
+		MenuIngredients: []models.MenuIngredient{
+			{IngredientID: rice.ID, Quantity: 250, UnitID: gram.ID},
+			{IngredientID: egg.ID, Quantity: 5, UnitID: gram.ID},
+		},
+	})
+
+	// Beras: 3 x 0.2 kg + 2 x 0.25 kg = 1.1 kg dari stok 1 kg
+	preview, err := service.Preview(dto.TransactionCreateRequest{
+		Items: []dto.TransactionItemRequest{
+			{MenuID: menu.ID, Quantity: 3},
+			{MenuID: friedRice.ID, Quantity: 2},
+		},
+	})
+	if err != nil {
+		t.Fatalf("preview: %v", err)
+	}
+
+	if preview.TotalAmount != 45000 || preview.Feasible {
+		t.Errorf("preview total = %.2f feasible = %v, want 45000 and not feasible", preview.TotalAmount, preview.Feasible)
+	}
+	if len(preview.Requirements) != 2 {
+		t.Fatalf("requirements = %+v, want rice and egg", preview.Requirements)
+	}
+	riceNeed, eggNeed := preview.Requirements[0], preview.Requirements[1]
+	if riceNeed.Ingredient.ID != rice.ID || math.Abs(riceNeed.Required-1.1) > 1e-9 || math.Abs(riceNeed.Shortage-0.1) > 1e-9 {
+		t.Errorf("rice requirement = %+v, want 1.1 kg with 0.1 kg short", riceNeed)
+	}
+	if eggNeed.Required != 10 || eggNeed.Shortage != 0 {
+		t.Errorf("egg requirement = %+v, want 10 g without shortage", eggNeed)
+	}
+
+	// Preview tidak menulis apa pun
+	if len(store.data.transactions) != 0 || len(store.data.movements) != 0 || store.data.ingredients[rice.ID].Stock != 1 {
+		t.Errorf("preview wrote data: %d transactions, %d movements", len(store.data.transactions), len(store.data.movements))
+	}
+
+	preview, err = service.Preview(dto.TransactionCreateRequest{
+		Items: []dto.TransactionItemRequest{{MenuID: menu.ID, Quantity: 5}},
+	})
+	if err != nil || !preview.Feasible {
+		t.Errorf("preview of 5 portions = %+v (%v), want feasible", preview, err)
+	}
+
+	if _, err := service.Preview(dto.TransactionCreateRequest{}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("empty preview err = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestTransactionServiceCreateAppliesModifiers(t *testing.T) {
+	service, store, rice, menu := newTestTransactionService(t, 2)
+
+	gram := store.data.menus[menu.ID].MenuIngredients[0].Unit
+	egg := store.addIngredient(models.Ingredient{Name: "Telur", Stock: 500, UnitID: gram.ID, UnitCost: 2})
+	chili := store.addIngredient(models.Ingredient{Name: "Cabai", Stock: 100, UnitID: gram.ID, UnitCost: 1})
+	friedRice := store.addMenu(models.Menu{
+		Name:  "Nasi Goreng",
+		Price: 15000,
+		MenuIngredients: []models.MenuIngredient{
+			{IngredientID: rice.ID, Quantity: 250, UnitID: gram.ID},
+			{IngredientID: chili.ID, Quantity: 10, UnitID: gram.ID},
+		},
+	})
+
+	menus := NewMenuService(store)
+	friedRice, err := menus.SetModifiers(friedRice.ID, []dto.MenuModifierGroupRequest{
+		{Name: "Ukuran", MinSelect: 1, MaxSelect: 1, Options: []dto.MenuModifierOptionRequest{
+			{Name: "Reguler"},
+			{Name: "Besar", PriceDelta: 5000, Ingredients: []dto.MenuIngredientRequest{{IngredientID: rice.ID, Quantity: 100, UnitID: gram.ID}}},
+		}},
+		{Name: "Tambahan", Options: []dto.MenuModifierOptionRequest{
+			{Name: "Telur", PriceDelta: 3000, Ingredients: []dto.MenuIngredientRequest{{IngredientID: egg.ID, Quantity: 50, UnitID: gram.ID}}},
+		}},
+		{Name: "Tanpa", Options: []dto.MenuModifierOptionRequest{
+			{Name: "Tanpa Cabai", Ingredients: []dto.MenuIngredientRequest{{IngredientID: chili.ID, Quantity: -10, UnitID: gram.ID}}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("set modifiers: %v", err)
+	}
+	size, extra, removal := friedRice.ModifierGroups[0], friedRice.ModifierGroups[1], friedRice.ModifierGroups[2]
+	regular, large := size.Options[0].ID, size.Options[1].ID
+
+	invalid := map[string][]uint{
+		"missing required size": {extra.Options[0].ID},
+		"two sizes":             {regular, large},
+		"duplicate option":      {large, large},
+		"option of other menu":  {large, 9999},
+	}
+	for name, optionIDs := range invalid {
+		_, err := service.Create(dto.TransactionCreateRequest{
+			Items: []dto.TransactionItemRequest{{MenuID: friedRice.ID, Quantity: 1, ModifierOptionIDs: optionIDs}},
+		}, nil)
+		if !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("%s: err = %v, want ErrInvalidInput", name, err)
+		}
+	}
+
+	// Besar + telur, tanpa cabai: 2 x (250 g + 100 g) beras dan 2 x 50 g telur
+	transaction, err := service.Create(dto.TransactionCreateRequest{
+		Items: []dto.TransactionItemRequest{{
+			MenuID:            friedRice.ID,
+			Quantity:          2,
+			ModifierOptionIDs: []uint{large, extra.Options[0].ID, removal.Options[0].ID},
+		}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	if transaction.TotalAmount != 46000 {
+		t.Errorf("total = %.2f, want 46000", transaction.TotalAmount)
+	}
+	item := store.data.items[0]
+	if item.Price != 23000 || len(item.Modifiers) != 3 || item.Modifiers[0].GroupName != "Ukuran" || item.Modifiers[0].OptionName != "Besar" {
+		t.Errorf("item = price %.2f modifiers %+v, want 23000 with Besar, Telur and Tanpa Cabai", item.Price, item.Modifiers)
+	}
+
+	if stock := store.data.ingredients[rice.ID].Stock; math.Abs(stock-1.3) > 1e-9 {
+		t.Errorf("rice stock = %v, want 1.3", stock)
+	}
+	if stock := store.data.ingredients[egg.ID].Stock; stock != 400 {
+		t.Errorf("egg stock = %v, want 400", stock)
+	}
+	if stock := store.data.ingredients[chili.ID].Stock; stock != 100 {
+		t.Errorf("chili stock = %v, want 100 without a deduction", stock)
+	}
+	if len(store.data.reductions) != 2 {
+		t.Errorf("reductions = %+v, want rice and egg only", store.data.reductions)
+	}
+
+	_, err = menus.SetModifiers(friedRice.ID, []dto.MenuModifierGroupRequest{
+		{Name: "Saus", MinSelect: 2, Options: []dto.MenuModifierOptionRequest{{Name: "Sambal"}}},
+	})
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("min_select above options err = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestTransactionServiceRefundCancelAndDeleteRestoreStock(t *testing.T) {
+	service, store, rice, menu := newTestTransactionService(t, 2)
+
+	sell := func(quantity int) models.Transaction {
+		t.Helper()
+		transaction, err := service.Create(dto.TransactionCreateRequest{
+			Items: []dto.TransactionItemRequest{{MenuID: menu.ID, Quantity: quantity}},
+		}, nil)
+		if err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		return transaction
+	}
+
+	// 5 porsi = 1 kg, stok tersisa 1 kg
+	sale := sell(5)
+	itemID := store.data.items[0].ID
+
+	refunded, err := service.Refund(sale.ID, dto.TransactionRefundRequest{
+		Reason: "komplain",
+		Items: []dto.TransactionRefundItemRequest{
+			{TransactionItemID: itemID, Quantity: 2, Restock: true},
+			{TransactionItemID: itemID, Quantity: 1},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("refund: %v", err)
+	}
+	if refunded.Status != models.TransactionStatusPartiallyRefunded || refunded.RefundedAmount != 15000 {
+		t.Errorf("refunded = %s %.2f, want partially_refunded 15000", refunded.Status, refunded.RefundedAmount)
+	}
+	// Hanya 2 porsi yang dikembalikan ke stok: 1 + 0.4 kg
+	if stock := store.data.ingredients[rice.ID].Stock; math.Abs(stock-1.4) > 1e-9 {
+		t.Errorf("stock after refund = %v, want 1.4", stock)
+	}
+
+	_, err = service.Refund(sale.ID, dto.TransactionRefundRequest{
+		Reason: "lagi",
+		Items:  []dto.TransactionRefundItemRequest{{TransactionItemID: itemID, Quantity: 3}},
+	}, nil)
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("refund above refundable err = %v, want ErrInvalidInput", err)
+	}
+
+	if _, err := service.Cancel(sale.ID, "salah input", nil); !errors.Is(err, ErrConflict) {
+		t.Errorf("cancel of refunded transaction err = %v, want ErrConflict", err)
+	}
+
+	second := sell(2)
+	cancelled, err := service.Cancel(second.ID, "salah input", nil)
+	if err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if cancelled.Status != models.TransactionStatusCancelled || cancelled.CancelReason != "salah input" || cancelled.CancelledAt == nil {
+		t.Errorf("cancelled = %+v", cancelled)
+	}
+	if stock := store.data.ingredients[rice.ID].Stock; math.Abs(stock-1.4) > 1e-9 {
+		t.Errorf("stock after cancel = %v, want 1.4", stock)
+	}
+	if _, err := service.Delete(second.ID, nil); !errors.Is(err, ErrConflict) {
+		t.Errorf("delete of cancelled transaction err = %v, want ErrConflict", err)
+	}
+
+	third := sell(1)
+	if _, err := service.Delete(third.ID, nil); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := service.Get(third.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("get deleted transaction err = %v, want ErrNotFound", err)
+	}
+	if stock := store.data.ingredients[rice.ID].Stock; math.Abs(stock-1.4) > 1e-9 {
+		t.Errorf("stock after delete = %v, want 1.4", stock)
+	}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+	"AwisPalace_IngredientManagement/utils"
+)
+
+type UnitService struct {
+	store repositories.Store
+}
+
+func NewUnitService(store repositories.Store) *UnitService {
+	return &UnitService{store: store}
+}
+
+func (s *UnitService) List(filter repositories.UnitFilter, params utils.ListParams) ([]models.Unit, dto.PageMeta, error) {
+	return s.store.Units().List(filter, params)
+}
+
+func (s *UnitService) Create(input dto.UnitParamRequest) (models.Unit, error) {
+	unit := models.Unit{}
+	applyUnitInput(&unit, input)
+
+	err := s.store.Units().Create(&unit)
+	return unit, err
+}
+
+func (s *UnitService) Update(id uint, input dto.UnitParamRequest) (models.Unit, error) {
+	unit, err := s.store.Units().FindByID(id)
+	if err != nil {
+		return unit, err
+	}
+
+	applyUnitInput(&unit, input)
+	err = s.store.Units().Update(&unit)
+	return unit, err
+}
+
+func (s *UnitService) Delete(id uint) error {
+	unit, err := s.store.Units().FindByID(id)
+	if err != nil {
+		return err
+	}
+	return s.store.Units().Delete(&unit)
+}
+
+func applyUnitInput(unit *models.Unit, input dto.UnitParamRequest) {
+	unit.Name = input.Name
+	unit.Symbol = input.Symbol
+	unit.Dimension = input.Dimension
+	unit.Factor = input.Factor
+	// Factor default ke 1 jika tidak diisi
+	if unit.Factor <= 0 {
+		unit.Factor = 1
+	}
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+	"AwisPalace_IngredientManagement/utils"
+)
+
+type UserService struct {
+	store repositories.Store
+}
+
+func NewUserService(store repositories.Store) *UserService {
+	return &UserService{store: store}
+}
+
+func (s *UserService) List(filter repositories.UserFilter, params utils.ListParams) ([]models.User, dto.PageMeta, error) {
+	return s.store.Users().List(filter, params)
+}
+
+// Get returns the user, failing with ErrNotFound once the user is deleted
+func (s *UserService) Get(id uint) (models.User, error) {
+	return s.store.Users().FindByID(id)
+}
+
+// UpdateRole assigns role to the user, keeping at least one owner
+func (s *UserService) UpdateRole(id uint, role string) (models.User, error) {
+	var user models.User
+
+	err := s.store.Transaction(func(store repositories.Store) error {
+		var err error
+		user, err = store.Users().FindByID(id)
+		if err != nil {
+			return err
+		}
+
+		// Jangan sampai tidak ada owner yang tersisa
+		if user.Role == models.RoleOwner && role != models.RoleOwner {
+			ownerCount, err := store.Users().CountByRole(models.RoleOwner)
+			if err != nil {
+				return err
+			}
+			if ownerCount <= 1 {
+				return conflict("Cannot remove the last owner")
+			}
+		}
+
+		return store.Users().UpdateRole(&user, role)
+	})
+
+	return user, err
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"AwisPalace_IngredientManagement/models"
+)
+
+func TestUserServiceUpdateRoleKeepsLastOwner(t *testing.T) {
+	store := newFakeStore()
+	store.data.users[1] = models.User{ID: 1, Email: "owner@example.com", Role: models.RoleOwner}
+	store.data.users[2] = models.User{ID: 2, Email: "cashier@example.com", Role: models.RoleCashier}
+
+	service := NewUserService(store)
+
+	if _, err := service.UpdateRole(1, models.RoleManager); !errors.Is(err, ErrConflict) {
+		t.Fatalf("demoting the last owner: err = %v, want ErrConflict", err)
+	}
+
+	if _, err := service.UpdateRole(2, models.RoleOwner); err != nil {
+		t.Fatalf("promote: %v", err)
+	}
+
+	user, err := service.UpdateRole(1, models.RoleManager)
+	if err != nil {
+		t.Fatalf("demote with a second owner: %v", err)
+	}
+	if user.Role != models.RoleManager || store.data.users[1].Role != models.RoleManager {
+		t.Errorf("role = %s, want manager", user.Role)
+	}
+
+	if _, err := service.UpdateRole(99, models.RoleOwner); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unknown user: err = %v, want ErrNotFound", err)
+	}
+}
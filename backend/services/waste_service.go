@@ -0,0 +1,193 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+	"AwisPalace_IngredientManagement/utils"
+)
+
+type WasteService struct {
+	store repositories.Store
+	now   func() time.Time
+}
+
+func NewWasteService(store repositories.Store) *WasteService {
+	return &WasteService{
+		store: store,
+		now:   time.Now,
+	}
+}
+
+func (s *WasteService) List(filter repositories.WasteFilter, params utils.ListParams) ([]models.WasteRecord, dto.PageMeta, error) {
+	return s.store.Waste().List(filter, params)
+}
+
+// Record deducts wasted stock through the ledger. The quantity may be given
+// in any unit convertible to the ingredient's stock unit.
+func (s *WasteService) Record(input dto.WasteCreateRequest, userID *uint) (models.WasteRecord, error) {
+	if !slices.Contains(models.WasteReasons, input.Reason) {
+		return models.WasteRecord{}, invalidInput("Reason must be one of %s", strings.Join(models.WasteReasons, ", "))
+	}
+	if input.Quantity <= 0 {
+		return models.WasteRecord{}, invalidInput("Quantity must be greater than 0")
+	}
+
+	var record models.WasteRecord
+
+	err := s.store.Transaction(func(store repositories.Store) error {
+		if err := store.Ingredients().Lock([]uint{input.IngredientID}); err != nil {
+			return err
+		}
+
+		ingredient, err := store.Ingredients().FindByID(input.IngredientID)
+		if err != nil {
+			return err
+		}
+
+		unit, err := store.Units().FindByID(input.UnitID)
+		if errors.Is(err, ErrNotFound) {
+			return invalidInput("unit with ID %d does not exist", input.UnitID)
+		}
+		if err != nil {
+			return err
+		}
+
+		quantity, err := utils.ConvertQuantity(input.Quantity, unit, ingredient.Unit, ingredient.Conversions)
+		if err != nil {
+			return fmt.Errorf("ingredient %s: %w", ingredient.Name, err)
+		}
+
+		record = models.WasteRecord{
+			IngredientID:    ingredient.ID,
+			Quantity:        input.Quantity,
+			WastedUnitID:    unit.ID,
+			QuantityReduced: quantity,
+			StockBefore:     ingredient.Stock,
+			StockAfter:      ingredient.Stock - quantity,
+			UnitID:          ingredient.UnitID,
+			UnitCost:        ingredient.UnitCost,
+			Reason:          input.Reason,
+			Notes:           input.Notes,
+			WastedAt:        s.now(),
+			UserID:          userID,
+		}
+		if err := store.Waste().Create(&record); err != nil {
+			return err
+		}
+
+		movement := models.StockMovement{
+			IngredientID:  ingredient.ID,
+			Type:          models.StockMovementWaste,
+			Quantity:      -quantity,
+			UserID:        userID,
+			ReferenceType: "waste_record",
+			ReferenceID:   record.ID,
+			Notes:         input.Reason,
+		}
+		if err := store.Ingredients().RecordMovement(&movement); err != nil {
+			return err
+		}
+
+		record.StockBefore = movement.StockBefore
+		record.StockAfter = movement.StockAfter
+		record.StockMovementID = &movement.ID
+		return store.Waste().Update(&record)
+	})
+	if err != nil {
+		return record, err
+	}
+
+	return s.store.Waste().FindByID(record.ID)
+}
+
+// Report totals the matching waste by reason, ingredient and date
+func (s *WasteService) Report(filter repositories.WasteFilter) (dto.WasteReport, error) {
+	records, err := s.store.Waste().FindAll(filter)
+	if err != nil {
+		return dto.WasteReport{}, err
+	}
+	return SummarizeWaste(records), nil
+}
+
+// SummarizeWaste totals records by reason (in the order of
+// models.WasteReasons), by ingredient name and by local date
+func SummarizeWaste(records []models.WasteRecord) dto.WasteReport {
+	report := dto.WasteReport{
+		Records:      len(records),
+		ByReason:     make([]dto.WasteReasonTotal, 0),
+		ByIngredient: make([]dto.WasteIngredientTotal, 0),
+		ByDate:       make([]dto.WasteDateTotal, 0),
+	}
+
+	reasons := make(map[string]*dto.WasteReasonTotal)
+	ingredients := make(map[uint]*dto.WasteIngredientTotal)
+	dates := make(map[string]*dto.WasteDateTotal)
+
+	for _, record := range records {
+		value := record.Value()
+		report.TotalValue += value
+
+		reason, ok := reasons[record.Reason]
+		if !ok {
+			reason = &dto.WasteReasonTotal{Reason: record.Reason}
+			reasons[record.Reason] = reason
+		}
+		reason.Records++
+		reason.Value += value
+
+		ingredient, ok := ingredients[record.IngredientID]
+		if !ok {
+			ingredient = &dto.WasteIngredientTotal{
+				Ingredient: dto.StockReductionIngredient{
+					ID:   record.Ingredient.ID,
+					Name: record.Ingredient.Name,
+					Slug: record.Ingredient.Slug,
+				},
+				Unit: dto.StockReductionUnit{
+					ID:   record.Unit.ID,
+					Name: record.Unit.Name,
+				},
+			}
+			ingredients[record.IngredientID] = ingredient
+		}
+		ingredient.Records++
+		ingredient.Quantity += record.QuantityReduced
+		ingredient.Value += value
+
+		day := record.WastedAt.In(time.Local).Format("2006-01-02")
+		date, ok := dates[day]
+		if !ok {
+			date = &dto.WasteDateTotal{Date: day}
+			dates[day] = date
+		}
+		date.Records++
+		date.Value += value
+	}
+
+	for _, code := range models.WasteReasons {
+		if reason, ok := reasons[code]; ok {
+			report.ByReason = append(report.ByReason, *reason)
+		}
+	}
+	for _, ingredient := range ingredients {
+		report.ByIngredient = append(report.ByIngredient, *ingredient)
+	}
+	slices.SortFunc(report.ByIngredient, func(a, b dto.WasteIngredientTotal) int {
+		return strings.Compare(a.Ingredient.Name, b.Ingredient.Name)
+	})
+	for _, date := range dates {
+		report.ByDate = append(report.ByDate, *date)
+	}
+	slices.SortFunc(report.ByDate, func(a, b dto.WasteDateTotal) int {
+		return strings.Compare(a.Date, b.Date)
+	})
+
+	return report
+}
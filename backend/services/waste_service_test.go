@@ -0,0 +1,126 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"AwisPalace_IngredientManagement/dto"
+	"AwisPalace_IngredientManagement/models"
+	"AwisPalace_IngredientManagement/repositories"
+	"AwisPalace_IngredientManagement/utils"
+)
+
+// newTestWasteService returns a service on a fake store holding 2 kg of rice
+func newTestWasteService(t *testing.T) (*WasteService, *fakeStore, models.Ingredient, models.Unit) {
+	t.Helper()
+
+	store := newFakeStore()
+	kilogram := store.addUnit(models.Unit{Name: "Kilogram", Symbol: "kg", Dimension: models.DimensionMass, Factor: 1000})
+	gram := store.addUnit(models.Unit{Name: "Gram", Symbol: "g", Dimension: models.DimensionMass, Factor: 1})
+	rice := store.addIngredient(models.Ingredient{Name: "Beras", Stock: 2, UnitID: kilogram.ID, UnitCost: 12000})
+
+	service := NewWasteService(store)
+	service.now = func() time.Time {
+		return time.Date(2026, 10, 17, 12, 0, 0, 0, time.Local)
+	}
+
+	return service, store, rice, gram
+}
+
+func TestWasteServiceRecordDeductsStock(t *testing.T) {
+	service, store, rice, gram := newTestWasteService(t)
+
+	record, err := service.Record(dto.WasteCreateRequest{
+		IngredientID: rice.ID, Quantity: 500, UnitID: gram.ID, Reason: models.WasteReasonDamaged,
+	}, nil)
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	if record.QuantityReduced != 0.5 || record.StockBefore != 2 || record.StockAfter != 1.5 {
+		t.Errorf("record = %.2f from %.2f to %.2f, want 0.5 from 2 to 1.5", record.QuantityReduced, record.StockBefore, record.StockAfter)
+	}
+	if record.Value() != 6000 {
+		t.Errorf("value = %.2f, want 6000", record.Value())
+	}
+	if got := store.data.ingredients[rice.ID].Stock; got != 1.5 {
+		t.Errorf("stock = %.2f, want 1.5", got)
+	}
+
+	movement := store.data.movements[0]
+	if movement.Type != models.StockMovementWaste || movement.Quantity != -0.5 || movement.ReferenceID != record.ID {
+		t.Errorf("movement = %+v, want waste of -0.5 referencing the record", movement)
+	}
+	if record.StockMovementID == nil || *record.StockMovementID != movement.ID {
+		t.Errorf("record movement = %v, want %d", record.StockMovementID, movement.ID)
+	}
+}
+
+func TestWasteServiceRejectsInvalidRecords(t *testing.T) {
+	service, store, rice, gram := newTestWasteService(t)
+
+	_, err := service.Record(dto.WasteCreateRequest{IngredientID: rice.ID, Quantity: 1, UnitID: gram.ID, Reason: "lost"}, nil)
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("unknown reason err = %v, want ErrInvalidInput", err)
+	}
+
+	_, err = service.Record(dto.WasteCreateRequest{IngredientID: rice.ID, Quantity: 1, UnitID: 999, Reason: models.WasteReasonDamaged}, nil)
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("unknown unit err = %v, want ErrInvalidInput", err)
+	}
+
+	_, err = service.Record(dto.WasteCreateRequest{IngredientID: rice.ID, Quantity: 3000, UnitID: gram.ID, Reason: models.WasteReasonExpired}, nil)
+	if !errors.Is(err, utils.ErrInsufficientStock) {
+		t.Errorf("too much waste err = %v, want ErrInsufficientStock", err)
+	}
+	if len(store.data.waste) != 0 || store.data.ingredients[rice.ID].Stock != 2 {
+		t.Errorf("failed record was kept: %d records, stock %.2f", len(store.data.waste), store.data.ingredients[rice.ID].Stock)
+	}
+}
+
+func TestWasteServiceReport(t *testing.T) {
+	service, _, rice, gram := newTestWasteService(t)
+
+	for _, waste := range []struct {
+		day      int
+		quantity float64
+		reason   string
+	}{
+		{17, 100, models.WasteReasonStaffMeal},
+		{17, 200, models.WasteReasonExpired},
+		{18, 300, models.WasteReasonExpired},
+	} {
+		service.now = func() time.Time {
+			return time.Date(2026, 10, waste.day, 12, 0, 0, 0, time.Local)
+		}
+		if _, err := service.Record(dto.WasteCreateRequest{
+			IngredientID: rice.ID, Quantity: waste.quantity, UnitID: gram.ID, Reason: waste.reason,
+		}, nil); err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+
+	report, err := service.Report(repositories.WasteFilter{})
+	if err != nil {
+		t.Fatalf("report: %v", err)
+	}
+
+	if report.Records != 3 || report.TotalValue != 7200 {
+		t.Errorf("report = %d records valued %.2f, want 3 valued 7200", report.Records, report.TotalValue)
+	}
+	if len(report.ByReason) != 2 || report.ByReason[0].Reason != models.WasteReasonExpired || report.ByReason[0].Value != 6000 {
+		t.Errorf("by reason = %+v, want expired first valued 6000", report.ByReason)
+	}
+	if len(report.ByDate) != 2 || report.ByDate[0].Date != "2026-10-17" || report.ByDate[0].Records != 2 {
+		t.Errorf("by date = %+v, want 2 records on 2026-10-17 first", report.ByDate)
+	}
+
+	filtered, err := service.Report(repositories.WasteFilter{StartDate: time.Date(2026, 10, 18, 0, 0, 0, 0, time.Local)})
+	if err != nil {
+		t.Fatalf("filtered report: %v", err)
+	}
+	if filtered.Records != 1 {
+		t.Errorf("records from 2026-10-18 = %d, want 1", filtered.Records)
+	}
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultTransactionCodeFormat produces codes such as TRX-20261017-0001
+const DefaultTransactionCodeFormat = "TRX-{date}-{seq:4}"
+
+// DefaultStocktakeCodeFormat produces codes such as STK-20261017-01
+const DefaultStocktakeCodeFormat = "STK-{date}-{seq:2}"
+
+var seqPlaceholder = regexp.MustCompile(`\{seq(?::(\d+))?\}`)
+
+// NextSequence increments the named counter and returns its new value. The
+// counter row stays locked until tx ends, so concurrent callers get
+// consecutive values and a rolled back transaction does not leave a gap.
+func NextSequence(tx *gorm.DB, name string) (int64, error) {
+	var value int64
+
+	err := tx.Raw(`INSERT INTO document_sequences (name, value, updated_at) VALUES (?, 1, ?)
+		ON CONFLICT (name) DO UPDATE SET value = document_sequences.value + 1, updated_at = excluded.updated_at
+		RETURNING value`, name, time.Now()).Scan(&value).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return value, nil
+}
+
+// CodeGenerator builds document codes from a format with the placeholders
+// {outlet}, {date} (YYYYMMDD) and {seq} or {seq:N} (zero padded to N digits).
+// The sequence restarts every day when the format contains {date}.
+type CodeGenerator struct {
+	Prefix string // Nama sequence, contoh: transaction
+	Format string
+	Outlet string
+}
+
+func NewCodeGenerator(prefix, format, outlet string) (*CodeGenerator, error) {
+	if !seqPlaceholder.MatchString(format) {
+		return nil, errors.New("code format must contain {seq}")
+	}
+	if strings.Contains(format, "{outlet}") && outlet == "" {
+		return nil, errors.New("code format uses {outlet} but no outlet code is configured")
+	}
+
+	return &CodeGenerator{
+		Prefix: prefix,
+		Format: format,
+		Outlet: outlet,
+	}, nil
+}
+
+// Next reserves the next sequence number with nextSequence (e.g. NextSequence
+// bound to the current transaction) and returns the formatted code
+func (g *CodeGenerator) Next(now time.Time, nextSequence func(name string) (int64, error)) (string, error) {
+	date := now.Format("20060102")
+
+	name := g.Prefix + ":" + g.Outlet
+	if strings.Contains(g.Format, "{date}") {
+		name += ":" + date
+	}
+
+	seq, err := nextSequence(name)
+	if err != nil {
+		return "", fmt.Errorf("failed to reserve %s code: %w", g.Prefix, err)
+	}
+
+	code := strings.NewReplacer("{outlet}", g.Outlet, "{date}", date).Replace(g.Format)
+	code = seqPlaceholder.ReplaceAllStringFunc(code, func(placeholder string) string {
+		width := 0
+		if match := seqPlaceholder.FindStringSubmatch(placeholder); match[1] != "" {
+			width, _ = strconv.Atoi(match[1])
+		}
+		return fmt.Sprintf("%0*d", width, seq)
+	})
+
+	return code, nil
+}
+
+// NewPurchaseOrderNumber returns a unique purchase order number such as
+// PO-20261017-1A2B3C4D
+func NewPurchaseOrderNumber(date time.Time) string {
+	return fmt.Sprintf("PO-%s-%s", date.Format("20060102"), strings.ToUpper(uuid.New().String()[:8]))
+}
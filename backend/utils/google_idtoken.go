@@ -0,0 +1,213 @@
+package utils
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// GoogleJWKSURL is the endpoint publishing the keys Google signs ID tokens with
+const GoogleJWKSURL = "https://www.googleapis.com/oauth2/v3/certs"
+
+var googleIssuers = []string{"accounts.google.com", "https://accounts.google.com"}
+
+var maxAgeRegexp = regexp.MustCompile(`max-age=(\d+)`)
+
+// KeySource provides the public key for a key ID. Tests can plug in a
+// JWKSKeySource pointing at a local fake JWKS server.
+type KeySource interface {
+	Key(ctx context.Context, kid string) (*rsa.PublicKey, error)
+}
+
+// JWKSKeySource fetches RSA keys from a JWKS endpoint and caches them until
+// the Cache-Control max-age of the response expires.
+type JWKSKeySource struct {
+	URL        string
+	Client     *http.Client
+	DefaultTTL time.Duration // used when the response has no max-age
+
+	mu          sync.Mutex
+	keys        map[string]*rsa.PublicKey
+	expiresAt   time.Time
+	lastFetched time.Time
+}
+
+func NewJWKSKeySource(url string) *JWKSKeySource {
+	return &JWKSKeySource{
+		URL:        url,
+		Client:     &http.Client{Timeout: 10 * time.Second},
+		DefaultTTL: time.Hour,
+	}
+}
+
+// Key returns the cached key for kid, refreshing the cache when it expired or
+// when kid is unknown (Google rotates keys), at most once per minute.
+func (s *JWKSKeySource) Key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	key, found := s.keys[kid]
+	expired := now.After(s.expiresAt)
+
+	if found && !expired {
+		return key, nil
+	}
+
+	if now.Sub(s.lastFetched) > time.Minute {
+		if err := s.refresh(ctx); err != nil {
+			if found {
+				// Keep using the stale key rather than failing every login
+				return key, nil
+			}
+			return nil, err
+		}
+		key, found = s.keys[kid]
+	}
+
+	if !found {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	return key, nil
+}
+
+func (s *JWKSKeySource) refresh(ctx context.Context) error {
+	s.lastFetched = time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch JWKS: status %d", resp.StatusCode)
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return fmt.Errorf("invalid JWKS: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Kty != "RSA" {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return fmt.Errorf("invalid JWKS modulus for key %q: %w", jwk.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return fmt.Errorf("invalid JWKS exponent for key %q: %w", jwk.Kid, err)
+		}
+
+		keys[jwk.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	ttl := s.DefaultTTL
+	if match := maxAgeRegexp.FindStringSubmatch(resp.Header.Get("Cache-Control")); match != nil {
+		if seconds, err := strconv.Atoi(match[1]); err == nil {
+			ttl = time.Duration(seconds) * time.Second
+		}
+	}
+
+	s.keys = keys
+	s.expiresAt = time.Now().Add(ttl)
+
+	return nil
+}
+
+// GoogleClaims are the claims of a verified Google ID token
+type GoogleClaims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	Picture       string `json:"picture"`
+	jwt.RegisteredClaims
+}
+
+// GoogleTokenVerifier verifies Google ID tokens against the signing keys and
+// the configured audiences (OAuth client IDs).
+type GoogleTokenVerifier struct {
+	Audiences []string
+	Keys      KeySource
+}
+
+func NewGoogleTokenVerifier(audiences []string, keys KeySource) *GoogleTokenVerifier {
+	return &GoogleTokenVerifier{
+		Audiences: audiences,
+		Keys:      keys,
+	}
+}
+
+// Verify checks the signature, issuer, audience and expiry of idToken
+func (v *GoogleTokenVerifier) Verify(ctx context.Context, idToken string) (*GoogleClaims, error) {
+	if len(v.Audiences) == 0 {
+		return nil, errors.New("google client ID is not configured")
+	}
+
+	claims := &GoogleClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return v.Keys.Key(ctx, kid)
+	}, jwt.WithValidMethods([]string{"RS256"}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(googleIssuers, claims.Issuer) {
+		return nil, fmt.Errorf("invalid issuer %q", claims.Issuer)
+	}
+
+	validAudience := false
+	for _, audience := range claims.Audience {
+		if slices.Contains(v.Audiences, audience) {
+			validAudience = true
+			break
+		}
+	}
+	if !validAudience {
+		return nil, errors.New("token was not issued for this application")
+	}
+
+	if claims.Subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+
+	if !claims.EmailVerified {
+		return nil, errors.New("email is not verified")
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,211 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testClientID = "test-client.apps.googleusercontent.com"
+
+// testJWKS is a local JWKS endpoint publishing one RSA key
+type testJWKS struct {
+	server   *httptest.Server
+	key      *rsa.PrivateKey
+	kid      string
+	requests atomic.Int32
+}
+
+func newTestJWKS(t *testing.T) *testJWKS {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	jwks := &testJWKS{key: key, kid: "test-key"}
+	jwks.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jwks.requests.Add(1)
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": jwks.kid,
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	t.Cleanup(jwks.server.Close)
+
+	return jwks
+}
+
+// validGoogleClaims are the claims of a token Google would issue for testClientID
+func validGoogleClaims() GoogleClaims {
+	now := time.Now()
+	return GoogleClaims{
+		Email:         "cashier@example.com",
+		EmailVerified: true,
+		Name:          "Cashier",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "https://accounts.google.com",
+			Subject:   "google-subject-1",
+			Audience:  jwt.ClaimStrings{testClientID},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+	}
+}
+
+func signGoogleToken(t *testing.T, key *rsa.PrivateKey, kid string, claims GoogleClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+func TestGoogleTokenVerifierVerify(t *testing.T) {
+	jwks := newTestJWKS(t)
+	verifier := NewGoogleTokenVerifier([]string{testClientID}, NewJWKSKeySource(jwks.server.URL))
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		token   func() string
+		wantErr string
+	}{
+		{
+			name: "valid token",
+			token: func() string {
+				return signGoogleToken(t, jwks.key, jwks.kid, validGoogleClaims())
+			},
+		},
+		{
+			name: "wrong audience",
+			token: func() string {
+				claims := validGoogleClaims()
+				claims.Audience = jwt.ClaimStrings{"other-client.apps.googleusercontent.com"}
+				return signGoogleToken(t, jwks.key, jwks.kid, claims)
+			},
+			wantErr: "not issued for this application",
+		},
+		{
+			name: "wrong issuer",
+			token: func() string {
+				claims := validGoogleClaims()
+				claims.Issuer = "https://evil.example.com"
+				return signGoogleToken(t, jwks.key, jwks.kid, claims)
+			},
+			wantErr: "invalid issuer",
+		},
+		{
+			name: "expired",
+			token: func() string {
+				claims := validGoogleClaims()
+				claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+				return signGoogleToken(t, jwks.key, jwks.kid, claims)
+			},
+			wantErr: "expired",
+		},
+		{
+			name: "unknown kid",
+			token: func() string {
+				return signGoogleToken(t, jwks.key, "rotated-key", validGoogleClaims())
+			},
+			wantErr: "unknown signing key",
+		},
+		{
+			name: "bad signature",
+			token: func() string {
+				return signGoogleToken(t, otherKey, jwks.kid, validGoogleClaims())
+			},
+			wantErr: "signature is invalid",
+		},
+		{
+			name: "unverified email",
+			token: func() string {
+				claims := validGoogleClaims()
+				claims.EmailVerified = false
+				return signGoogleToken(t, jwks.key, jwks.kid, claims)
+			},
+			wantErr: "email is not verified",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := verifier.Verify(context.Background(), tt.token())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("verify: %v", err)
+				}
+				if claims.Email != "cashier@example.com" || claims.Subject != "google-subject-1" {
+					t.Errorf("claims = %+v, want cashier@example.com / google-subject-1", claims)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGoogleTokenVerifierRequiresAudience(t *testing.T) {
+	jwks := newTestJWKS(t)
+	verifier := NewGoogleTokenVerifier(nil, NewJWKSKeySource(jwks.server.URL))
+
+	if _, err := verifier.Verify(context.Background(), signGoogleToken(t, jwks.key, jwks.kid, validGoogleClaims())); err == nil {
+		t.Error("token was accepted without a configured client ID")
+	}
+}
+
+func TestJWKSKeySourceCachesKeys(t *testing.T) {
+	jwks := newTestJWKS(t)
+	source := NewJWKSKeySource(jwks.server.URL)
+
+	for range 3 {
+		key, err := source.Key(context.Background(), jwks.kid)
+		if err != nil {
+			t.Fatalf("key: %v", err)
+		}
+		if key.N.Cmp(jwks.key.N) != 0 {
+			t.Fatal("key does not match the published key")
+		}
+	}
+
+	// Kid yang tidak dikenal tidak memicu fetch ulang dalam satu menit
+	if _, err := source.Key(context.Background(), "rotated-key"); err == nil {
+		t.Error("unknown kid was accepted")
+	}
+
+	if got := jwks.requests.Load(); got != 1 {
+		t.Errorf("JWKS requests = %d, want 1", got)
+	}
+	if ttl := time.Until(source.expiresAt); ttl < 59*time.Minute || ttl > time.Hour {
+		t.Errorf("cache expires in %v, want the max-age of one hour", ttl)
+	}
+}
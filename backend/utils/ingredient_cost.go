@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"fmt"
+
+	"AwisPalace_IngredientManagement/models"
+)
+
+// RecipeCost returns the cost of one portion built from menuIngredients, with
+// each recipe quantity converted into the ingredient's stock unit.
+// MenuIngredients must be loaded with Unit and Ingredient (including its Unit and Conversions).
+func RecipeCost(menuIngredients []models.MenuIngredient) (float64, error) {
+	var cost float64
+
+	for _, mi := range menuIngredients {
+		quantity, err := ConvertQuantity(mi.Quantity, mi.Unit, mi.Ingredient.Unit, mi.Ingredient.Conversions)
+		if err != nil {
+			return 0, fmt.Errorf("ingredient %s: %w", mi.Ingredient.Name, err)
+		}
+
+		cost += quantity * mi.Ingredient.UnitCost
+	}
+
+	return cost, nil
+}
+
+// NextUnitCost returns the ingredient unit cost after receiving quantityAdded
+// (in the stock unit) at purchaseUnitCost per stock unit.
+func NextUnitCost(ingredient models.Ingredient, stockBefore, quantityAdded, purchaseUnitCost float64) float64 {
+	if ingredient.CostMethod == models.CostMethodLastPurchase {
+		return purchaseUnitCost
+	}
+
+	// Moving average, stok kosong/negatif tidak ikut dihitung
+	if stockBefore <= 0 || stockBefore+quantityAdded <= 0 {
+		return purchaseUnitCost
+	}
+
+	return (stockBefore*ingredient.UnitCost + quantityAdded*purchaseUnitCost) / (stockBefore + quantityAdded)
+}
+
+// FoodCostPercentage returns cost as a percentage of price
+func FoodCostPercentage(cost, price float64) float64 {
+	if price == 0 {
+		return 0
+	}
+	return cost / price * 100
+}
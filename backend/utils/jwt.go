@@ -7,21 +7,36 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtSecret = []byte("your-secret-key-change-this-in-production") // Change this!
+var (
+	jwtSecret []byte
+	jwtTTL    = 7 * 24 * time.Hour
+)
+
+// ConfigureJWT sets the signing secret and token lifetime, called once at startup
+func ConfigureJWT(secret string, ttl time.Duration) {
+	jwtSecret = []byte(secret)
+	jwtTTL = ttl
+}
 
 type Claims struct {
 	UserID uint   `json:"user_id"`
 	Email  string `json:"email"`
+	Role   string `json:"role"`
 	jwt.RegisteredClaims
 }
 
 // GenerateToken generates JWT token for user
-func GenerateToken(userID uint, email string) (string, error) {
-	expirationTime := time.Now().Add(24 * 7 * time.Hour) // 7 days
+func GenerateToken(userID uint, email string, role string) (string, error) {
+	if len(jwtSecret) == 0 {
+		return "", errors.New("JWT secret is not configured")
+	}
+
+	expirationTime := time.Now().Add(jwtTTL)
 
 	claims := &Claims{
 		UserID: userID,
 		Email:  email,
+		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -43,7 +58,14 @@ func GenerateToken(userID uint, email string) (string, error) {
 func ValidateToken(tokenString string) (*Claims, error) {
 	claims := &Claims{}
 
+	if len(jwtSecret) == 0 {
+		return nil, errors.New("JWT secret is not configured")
+	}
+
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
 		return jwtSecret, nil
 	})
 
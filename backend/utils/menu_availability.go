@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+
+	"AwisPalace_IngredientManagement/models"
+)
+
+// AvailablePortions returns how many portions can be made from the current
+// stock: the lowest stock divided by the recipe quantity over all ingredients.
+// MenuIngredients must be loaded with Unit and Ingredient (including its Unit and Conversions).
+func AvailablePortions(menuIngredients []models.MenuIngredient) (int, error) {
+	if len(menuIngredients) == 0 {
+		return 0, nil
+	}
+
+	// Ingredient yang muncul di beberapa baris resep dijumlahkan
+	required := map[uint]float64{}
+	stock := map[uint]float64{}
+	for _, mi := range menuIngredients {
+		quantity, err := ConvertQuantity(mi.Quantity, mi.Unit, mi.Ingredient.Unit, mi.Ingredient.Conversions)
+		if err != nil {
+			return 0, fmt.Errorf("ingredient %s: %w", mi.Ingredient.Name, err)
+		}
+
+		required[mi.IngredientID] += quantity
+		stock[mi.IngredientID] = mi.Ingredient.Stock
+	}
+
+	portions := math.MaxInt
+	for ingredientID, quantity := range required {
+		if quantity <= 0 {
+			continue
+		}
+
+		// Toleransi kecil agar pembulatan float tidak mengurangi satu porsi
+		portions = min(portions, int(math.Floor(stock[ingredientID]/quantity+1e-9)))
+	}
+
+	if portions == math.MaxInt {
+		return 0, nil
+	}
+	return max(portions, 0), nil
+}
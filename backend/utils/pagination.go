@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"AwisPalace_IngredientManagement/dto"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+var ErrInvalidListParams = errors.New("invalid list parameters")
+
+// SortFields maps the sort names accepted in the query string to columns
+type SortFields map[string]string
+
+// ListParams are the pagination and sorting options of a list request.
+// Either Page (offset pagination) or Cursor (keyset pagination on id) is used.
+type ListParams struct {
+	Page   int
+	Limit  int
+	Cursor uint // ID terakhir dari halaman sebelumnya
+	Sort   string
+	Desc   bool
+
+	column string
+}
+
+// ParseListParams reads page, limit, cursor and sort from the query string.
+// sort is a name from sorts, prefixed with "-" for descending order
+// (e.g. "-created_at"). defaultSort uses the same syntax.
+func ParseListParams(query url.Values, sorts SortFields, defaultSort string) (ListParams, error) {
+	params := ListParams{Page: 1, Limit: DefaultPageLimit}
+
+	if page := query.Get("page"); page != "" {
+		value, err := strconv.Atoi(page)
+		if err != nil || value < 1 {
+			return params, fmt.Errorf("%w: page must be a positive number", ErrInvalidListParams)
+		}
+		params.Page = value
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value < 1 || value > MaxPageLimit {
+			return params, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidListParams, MaxPageLimit)
+		}
+		params.Limit = value
+	}
+
+	sort := query.Get("sort")
+	if sort == "" {
+		sort = defaultSort
+	}
+	params.Desc = strings.HasPrefix(sort, "-")
+	params.Sort = strings.TrimPrefix(sort, "-")
+
+	column, ok := sorts[params.Sort]
+	if !ok {
+		names := make([]string, 0, len(sorts))
+		for name := range sorts {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		return params, fmt.Errorf("%w: sort must be one of %s", ErrInvalidListParams, strings.Join(names, ", "))
+	}
+	params.column = column
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		value, err := strconv.ParseUint(cursor, 10, 64)
+		if err != nil || value == 0 {
+			return params, fmt.Errorf("%w: invalid cursor", ErrInvalidListParams)
+		}
+		if column != "id" {
+			return params, fmt.Errorf("%w: cursor can only be used when sorting by id", ErrInvalidListParams)
+		}
+		params.Cursor = uint(value)
+		params.Page = 0
+	}
+
+	return params, nil
+}
+
+// Paginate counts the rows matched by query and loads one page of them into
+// dest, a pointer to a slice of models. query must only hold filters; preload
+// (optional) adds the relations to load for the page.
+func Paginate(query *gorm.DB, params ListParams, dest interface{}, preload func(*gorm.DB) *gorm.DB) (dto.PageMeta, error) {
+	meta := dto.PageMeta{
+		Page:  params.Page,
+		Limit: params.Limit,
+		Sort:  params.Sort,
+	}
+	if params.Desc {
+		meta.Sort = "-" + params.Sort
+	}
+
+	if err := query.Session(&gorm.Session{}).Model(dest).Count(&meta.Total).Error; err != nil {
+		return meta, err
+	}
+	meta.TotalPages = int((meta.Total + int64(params.Limit) - 1) / int64(params.Limit))
+
+	page := query.Session(&gorm.Session{})
+	if preload != nil {
+		page = preload(page)
+	}
+
+	direction := "ASC"
+	if params.Desc {
+		direction = "DESC"
+	}
+	page = page.Order(fmt.Sprintf("%s %s", params.column, direction))
+	if params.column != "id" {
+		// id sebagai tie-breaker supaya urutan antar halaman stabil
+		page = page.Order("id " + direction)
+	}
+
+	if params.Cursor > 0 {
+		if params.Desc {
+			page = page.Where("id < ?", params.Cursor)
+		} else {
+			page = page.Where("id > ?", params.Cursor)
+		}
+	} else {
+		page = page.Offset((params.Page - 1) * params.Limit)
+	}
+
+	// Ambil satu baris lebih untuk mengetahui apakah masih ada halaman berikutnya
+	if err := page.Limit(params.Limit + 1).Find(dest).Error; err != nil {
+		return meta, err
+	}
+
+	rows := reflect.ValueOf(dest).Elem()
+	if rows.Len() > params.Limit {
+		meta.HasMore = true
+		rows.Set(rows.Slice(0, params.Limit))
+	}
+
+	if meta.HasMore && params.column == "id" {
+		last := rows.Index(rows.Len() - 1)
+		if id := reflect.Indirect(last).FieldByName("ID"); id.IsValid() {
+			meta.NextCursor = strconv.FormatUint(id.Uint(), 10)
+		}
+	}
+
+	return meta, nil
+}
@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"AwisPalace_IngredientManagement/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+// RecordStockMovement applies movement.Quantity to the ingredient stock and
+// stores the movement with the stock before and after the change.
+// Ingredient.Stock must only be changed through this function so the current
+// stock can always be reconciled against the ledger.
+//
+// Outgoing movements draw from the ingredient's lots first expiry first out
+// and report what they took in movement.Lots.
+//
+// The ingredient row is locked for the rest of tx and the stock is changed
+// with a conditional update, so concurrent movements cannot lose an update or
+// drive the stock negative.
+func RecordStockMovement(tx *gorm.DB, movement *models.StockMovement) error {
+	var ingredient models.Ingredient
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&ingredient, movement.IngredientID).Error; err != nil {
+		// Tetap dibungkus agar deadlock/serialization failure bisa diulang
+		// oleh RunInTransaction dan error lain tidak dianggap not found
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("ingredient with ID %d not found: %w", movement.IngredientID, err)
+		}
+		return fmt.Errorf("lock ingredient %d: %w", movement.IngredientID, err)
+	}
+
+	movement.StockBefore = ingredient.Stock
+	movement.StockAfter = ingredient.Stock + movement.Quantity
+	movement.UnitID = ingredient.UnitID
+
+	if movement.StockAfter < 0 {
+		return insufficientStockError(ingredient, movement.Quantity)
+	}
+
+	result := tx.Model(&models.Ingredient{}).
+		Where("id = ? AND stock + ? >= 0", ingredient.ID, movement.Quantity).
+		Update("stock", gorm.Expr("stock + ?", movement.Quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		// Stok sudah berubah oleh transaksi lain (database tanpa row lock)
+		return insufficientStockError(ingredient, movement.Quantity)
+	}
+
+	if movement.Quantity < 0 {
+		lots, err := consumeLots(tx, ingredient.ID, -movement.Quantity)
+		if err != nil {
+			return err
+		}
+		movement.Lots = lots
+	}
+
+	if err := tx.Create(movement).Error; err != nil {
+		return err
+	}
+
+	return raiseStockAlerts(tx, ingredient, movement)
+}
+
+// LockIngredients locks the ingredient rows for the rest of tx in ascending ID
+// order. Call it before recording movements for several ingredients so two
+// transactions touching the same ingredients always lock them in the same
+// order and cannot deadlock.
+func LockIngredients(tx *gorm.DB, ingredientIDs []uint) error {
+	if len(ingredientIDs) == 0 {
+		return nil
+	}
+
+	ids := slices.Clone(ingredientIDs)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	var ingredients []models.Ingredient
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id IN ?", ids).
+		Order("id").
+		Find(&ingredients).Error
+}
+
+func insufficientStockError(ingredient models.Ingredient, quantity float64) error {
+	return fmt.Errorf("%w for ingredient: %s. Available: %.2f, Required: %.2f",
+		ErrInsufficientStock, ingredient.Name, ingredient.Stock, -quantity)
+}
+
+// raiseStockAlerts creates an alert for every threshold the movement pushed
+// the stock through. Stock that was already below a threshold does not raise
+// a new alert for it.
+func raiseStockAlerts(tx *gorm.DB, ingredient models.Ingredient, movement *models.StockMovement) error {
+	if movement.Quantity >= 0 {
+		return nil
+	}
+
+	thresholds := []struct {
+		level string
+		value float64
+	}{
+		{models.StockAlertBelowReorderPoint, ingredient.ReorderPoint},
+		{models.StockAlertBelowMinimum, ingredient.MinimumStock},
+	}
+
+	for _, threshold := range thresholds {
+		if threshold.value <= 0 || movement.StockBefore <= threshold.value || movement.StockAfter > threshold.value {
+			continue
+		}
+
+		alert := models.StockAlert{
+			IngredientID:    ingredient.ID,
+			Level:           threshold.level,
+			Stock:           movement.StockAfter,
+			Threshold:       threshold.value,
+			StockMovementID: movement.ID,
+		}
+
+		if err := tx.Create(&alert).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"AwisPalace_IngredientManagement/models"
+
+	"github.com/glebarez/sqlite"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newLedgerTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "ledger.db")), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Unit{}, &models.Ingredient{}, &models.StockMovement{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func TestRecordStockMovementReportsMissingIngredient(t *testing.T) {
+	db := newLedgerTestDB(t)
+
+	err := RecordStockMovement(db, &models.StockMovement{IngredientID: 42, Type: models.StockMovementAdjustment, Quantity: 1})
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("err = %v, want gorm.ErrRecordNotFound", err)
+	}
+}
+
+func TestRecordStockMovementKeepsDeadlockRetryable(t *testing.T) {
+	db := newLedgerTestDB(t)
+
+	deadlock := &pgconn.PgError{Code: "40P01", Message: "deadlock detected"}
+	if err := db.Callback().Query().Before("gorm:query").Register("test:deadlock", func(db *gorm.DB) {
+		db.AddError(deadlock)
+	}); err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+
+	err := RecordStockMovement(db, &models.StockMovement{IngredientID: 1, Type: models.StockMovementAdjustment, Quantity: 1})
+	if !IsRetryableTxError(err) {
+		t.Errorf("err = %v, want a retryable deadlock", err)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("deadlock reported as not found: %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"math"
+
+	"AwisPalace_IngredientManagement/models"
+
+	"gorm.io/gorm"
+)
+
+// consumeLots draws quantity from the open lots of the ingredient, first
+// expiry first out. Lots without an expiry date come last, and stock that is
+// not covered by any lot is drawn once all lots are empty, so the returned
+// allocations may add up to less than quantity.
+func consumeLots(tx *gorm.DB, ingredientID uint, quantity float64) ([]models.LotAllocation, error) {
+	var lots []models.StockLot
+	if err := tx.Where("ingredient_id = ? AND remaining > 0", ingredientID).
+		Order("expiry_date IS NULL, expiry_date, received_date, id").
+		Find(&lots).Error; err != nil {
+		return nil, err
+	}
+
+	var allocations []models.LotAllocation
+	for _, lot := range lots {
+		// Toleransi kecil agar sisa pembulatan float tidak membuat alokasi baru
+		if quantity <= 1e-9 {
+			break
+		}
+
+		taken := math.Min(lot.Remaining, quantity)
+		if err := tx.Model(&models.StockLot{}).
+			Where("id = ?", lot.ID).
+			Update("remaining", gorm.Expr("remaining - ?", taken)).Error; err != nil {
+			return nil, err
+		}
+
+		allocations = append(allocations, models.LotAllocation{StockLotID: lot.ID, Quantity: taken})
+		quantity -= taken
+	}
+
+	return allocations, nil
+}
+
+// ReturnToLots puts fraction (0-1) of what a stock reduction drew from its
+// lots back into them, for sales that are cancelled, deleted or refunded with
+// restock. The stock itself is restored by the sale reversal movement.
+func ReturnToLots(tx *gorm.DB, lots []models.StockReductionLot, fraction float64) error {
+	for _, lot := range lots {
+		if err := tx.Model(&models.StockLot{}).
+			Where("id = ?", lot.StockLotID).
+			Update("remaining", gorm.Expr("remaining + ?", lot.Quantity*fraction)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// MaxTransactionAttempts is how often RunInTransaction tries a transaction
+// that failed on a serialization failure or deadlock
+const MaxTransactionAttempts = 3
+
+// RunInTransaction runs fn in a database transaction and commits it when fn
+// returns nil. The whole transaction is retried when Postgres aborts it with a
+// serialization failure or a detected deadlock, so fn must not have side
+// effects outside tx.
+func RunInTransaction(db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	var err error
+
+	for attempt := 1; attempt <= MaxTransactionAttempts; attempt++ {
+		err = db.Transaction(fn)
+		if err == nil || !IsRetryableTxError(err) {
+			return err
+		}
+
+		time.Sleep(time.Duration(attempt*attempt) * 10 * time.Millisecond)
+	}
+
+	return err
+}
+
+// IsRetryableTxError reports whether err is a serialization failure (40001)
+// or deadlock (40P01) reported by Postgres
+func IsRetryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "40001" || pgErr.Code == "40P01"
+	}
+	return false
+}
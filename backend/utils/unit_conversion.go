@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+
+	"AwisPalace_IngredientManagement/models"
+)
+
+var ErrIncompatibleUnit = errors.New("incompatible unit")
+
+// ConvertQuantity converts quantity from one unit to another.
+// Units within the same dimension are converted by their factor, otherwise the
+// ingredient specific conversions are used (e.g. 1 pcs egg = 60 g).
+// Conversions must be loaded with their Unit and ToUnit.
+func ConvertQuantity(quantity float64, from, to models.Unit, conversions []models.IngredientUnitConversion) (float64, error) {
+	if result, ok := convertByDimension(quantity, from, to); ok {
+		return result, nil
+	}
+
+	for _, conversion := range conversions {
+		// from -> conversion.Unit -> conversion.ToUnit -> to
+		if qty, ok := convertByDimension(quantity, from, conversion.Unit); ok {
+			if result, ok := convertByDimension(qty*conversion.Quantity, conversion.ToUnit, to); ok {
+				return result, nil
+			}
+		}
+
+		// from -> conversion.ToUnit -> conversion.Unit -> to
+		if conversion.Quantity == 0 {
+			continue
+		}
+		if qty, ok := convertByDimension(quantity, from, conversion.ToUnit); ok {
+			if result, ok := convertByDimension(qty/conversion.Quantity, conversion.Unit, to); ok {
+				return result, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("%w: cannot convert %s to %s", ErrIncompatibleUnit, unitLabel(from), unitLabel(to))
+}
+
+func convertByDimension(quantity float64, from, to models.Unit) (float64, bool) {
+	if from.ID != 0 && from.ID == to.ID {
+		return quantity, true
+	}
+
+	if from.Dimension == "" || from.Dimension != to.Dimension || from.Factor == 0 || to.Factor == 0 {
+		return 0, false
+	}
+
+	return quantity * from.Factor / to.Factor, true
+}
+
+func unitLabel(unit models.Unit) string {
+	if unit.Symbol != "" {
+		return unit.Symbol
+	}
+	return unit.Name
+}